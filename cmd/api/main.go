@@ -3,12 +3,29 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"product-listing/config"
+	"product-listing/internal/auth"
+	"product-listing/internal/cache"
+	"product-listing/internal/cdn"
+	"product-listing/internal/changebus"
 	"product-listing/internal/delivery/router"
+	"product-listing/internal/delivery/rpc"
+	"product-listing/internal/domain"
+	"product-listing/internal/feed"
+	"product-listing/internal/publisher"
+	"product-listing/internal/ratelimit"
+	"product-listing/internal/repository"
+	"product-listing/internal/storage"
+	"product-listing/internal/storefront"
+	"product-listing/internal/usecase"
+	"product-listing/internal/webhook"
 	"product-listing/pkg/logger"
+	"product-listing/pkg/urlsign"
+	"strings"
 	"syscall"
 	"time"
 
@@ -32,7 +49,7 @@ func run() error {
 	cfg := config.Load()
 
 	// Connect to database
-	db, err := config.NewDatabase(cfg)
+	db, err := config.NewDatabase(cfg, repository.StoreSetting)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -43,8 +60,95 @@ func run() error {
 		return fmt.Errorf("failed to initialize database schema: %w", err)
 	}
 
+	// Setup media URL signing
+	signer, err := newURLSigner(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to configure media url signing: %w", err)
+	}
+
+	// Setup JWT verification
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		HS256Secret: cfg.JWTHS256Secret,
+		JWKSFile:    cfg.JWTJWKSFile,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		RoleClaim:   cfg.JWTRoleClaim,
+		StoreClaim:  cfg.JWTStoreClaim,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure jwt verification: %w", err)
+	}
+
+	// Setup rate limiting
+	limiter, err := newRateLimiter(cfg, db)
+	if err != nil {
+		return fmt.Errorf("failed to configure rate limiting: %w", err)
+	}
+
+	// Background workers stop before the server shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Listen for catalog changes made through any replica
+	changes := changebus.New()
+	go changebus.Listen(workerCtx, db, changes)
+
+	// Event streams end once the server starts shutting down
+	streamCtx, stopStreams := context.WithCancel(context.Background())
+	defer stopStreams()
+
+	// Setup the product and category cache
+	catalogCache, err := newCatalogCache(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to configure caching: %w", err)
+	}
+	go catalogCache.Watch(workerCtx, changes)
+
+	// Purge a CDN in front of the API on changes
+	if cfg.CDNPurgeURL != "" {
+		purger := cdn.NewHTTPPurger(cfg.CDNPurgeURL, cfg.CDNPurgeAllURL, cfg.CDNPurgeTokenHeader, cfg.CDNPurgeToken, cfg.CDNPurgeTimeout)
+		go cdn.Watch(workerCtx, purger, changes)
+	}
+
+	// Setup the usecases shared by the REST and gRPC servers
+	u := newUsecases(cfg, db, signer, verifier, catalogCache)
+
 	// Setup router
-	r := router.SetupRouter(db)
+	r := router.SetupRouter(cfg, u, limiter, changes, streamCtx.Done())
+
+	// Start media garbage collector
+	if cfg.MediaGCInterval > 0 {
+		if cfg.MediaBaseURL == "" {
+			return fmt.Errorf("MEDIA_BASE_URL is required when MEDIA_GC_INTERVAL is set")
+		}
+		store := storage.NewLocalStore(cfg.MediaStorageDir)
+		gc := usecase.NewMediaGCUsecase(store, repository.NewProductImageRepository(db), cfg.MediaBaseURL, cfg.MediaGCGrace)
+		go runMediaGC(workerCtx, gc, cfg.MediaGCInterval, cfg.MediaGCDryRun)
+	}
+
+	// Start webhook dispatcher
+	if cfg.WebhookPollInterval > 0 {
+		dispatcher := usecase.NewWebhookDispatcher(repository.NewWebhookRepository(db), webhook.NewSender(cfg.WebhookTimeout),
+			cfg.WebhookMaxAttempts, cfg.WebhookBackoffBase, cfg.WebhookBackoffMax)
+		go runWebhookDispatcher(workerCtx, dispatcher, cfg.WebhookPollInterval)
+	}
+
+	// Start outbox relay
+	if cfg.OutboxPollInterval > 0 {
+		publishers, err := newEventPublishers(cfg, u)
+		if err != nil {
+			return fmt.Errorf("failed to configure outbox publishers: %w", err)
+		}
+		relay := usecase.NewOutboxRelay(repository.NewOutboxRepository(db), repository.NewTransactor(db), publishers, cfg.OutboxRetention)
+		go runOutboxRelay(workerCtx, relay, cfg.OutboxPollInterval)
+	}
+
+	// Start product import runner
+	if cfg.ImportPollInterval > 0 {
+		runner := usecase.NewProductImportRunner(repository.NewProductImportRepository(db), repository.NewStoreRepository(db),
+			repository.NewTransactor(db), u.Audit, cfg.ImportRetention)
+		go runProductImports(workerCtx, runner, cfg.ImportPollInterval)
+	}
 
 	// Start server
 	serverAddr := fmt.Sprintf(":%s", cfg.Port)
@@ -52,6 +156,7 @@ func run() error {
 		Addr:    serverAddr,
 		Handler: r,
 	}
+	srv.RegisterOnShutdown(stopStreams)
 
 	// Initializing the server in a goroutine
 	go func() {
@@ -61,12 +166,28 @@ func run() error {
 		}
 	}()
 
+	// Start gRPC server
+	grpcAddr := fmt.Sprintf(":%s", cfg.GRPCPort)
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for grpc: %w", err)
+	}
+	grpcSrv := rpc.NewServer(cfg, u, limiter)
+
+	go func() {
+		log.Infof("gRPC server starting on %s", grpcAddr)
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.Fatalf("gRPC listen error: %s", err)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info("Shutting down server...")
+	stopWorkers()
 
 	// 5-second timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -76,6 +197,239 @@ func run() error {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
+	if err := grpcSrv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("grpc server forced to shutdown: %w", err)
+	}
+
 	log.Info("Server exiting")
 	return nil
 }
+
+func newURLSigner(cfg *config.Config) (*urlsign.Signer, error) {
+	keys, err := urlsign.ParseKeys(cfg.MediaSigningKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		log.Warning("MEDIA_SIGNING_KEYS is not set, signed media URLs will not survive a restart")
+		keys = []urlsign.Key{urlsign.RandomKey()}
+	}
+
+	return urlsign.New(keys, cfg.MediaURLTTL)
+}
+
+func newRateLimiter(cfg *config.Config, db *config.Database) (domain.RateLimiter, error) {
+	if cfg.RateLimitWindow <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_WINDOW must be positive")
+	}
+
+	switch cfg.RateLimitBackend {
+	case "memory":
+		return ratelimit.NewMemoryLimiter(), nil
+	case "postgres":
+		return ratelimit.NewPostgresLimiter(db), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_BACKEND %q, expected memory or postgres", cfg.RateLimitBackend)
+	}
+}
+
+func newCatalogCache(cfg *config.Config) (*repository.CatalogCache, error) {
+	switch cfg.CacheBackend {
+	case "none":
+		return repository.NewCatalogCache(nil, 0), nil
+	case "memory":
+		if cfg.CacheMaxEntries <= 0 {
+			return nil, fmt.Errorf("CACHE_MAX_ENTRIES must be positive")
+		}
+		return repository.NewCatalogCache(cache.NewMemoryCache(cfg.CacheMaxEntries), cfg.CacheTTL), nil
+	case "redis":
+		return repository.NewCatalogCache(cache.NewRedisCache(cache.RedisConfig{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+			Prefix:   cfg.RedisPrefix,
+			Timeout:  cfg.RedisTimeout,
+		}), cfg.CacheTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND %q, expected memory, redis or none", cfg.CacheBackend)
+	}
+}
+
+// newUsecases builds the usecases once, so that the REST and gRPC servers
+// share them.
+func newUsecases(cfg *config.Config, db *config.Database, signer domain.URLSigner, verifier domain.TokenVerifier, catalogCache *repository.CatalogCache) *usecase.Usecases {
+	transactor := repository.NewTransactor(db)
+
+	storeRepo := repository.NewStoreRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	outboxUsecase := usecase.NewOutboxUsecase(outboxRepo)
+	auditUsecase := usecase.NewAuditUsecase(repository.NewAuditRepository(db), outboxUsecase)
+
+	categoryRepo := repository.NewCachedCategoryRepository(repository.NewCategoryRepository(db), catalogCache)
+	productRepo := repository.NewCachedProductRepository(repository.NewProductRepository(db), catalogCache)
+	productImageRepo := repository.NewProductImageRepository(db)
+
+	links := storefront.Links{BaseURL: cfg.StorefrontBaseURL, ProductPath: cfg.StorefrontProductPath, CategoryPath: cfg.StorefrontCategoryPath}
+
+	return &usecase.Usecases{
+		Auth:          usecase.NewAuthUsecase(repository.NewAPIKeyRepository(db), storeRepo, verifier, cfg.AuthBootstrapKey),
+		Store:         usecase.NewStoreUsecase(storeRepo, cfg.DefaultStore),
+		Idempotency:   usecase.NewIdempotencyUsecase(repository.NewIdempotencyRepository(db), cfg.IdempotencyTTL, cfg.IdempotencyLockTimeout),
+		Outbox:        outboxUsecase,
+		Audit:         auditUsecase,
+		Webhook:       usecase.NewWebhookUsecase(repository.NewWebhookRepository(db)),
+		Category:      usecase.NewCategoryUsecase(categoryRepo, transactor, auditUsecase),
+		Product:       usecase.NewProductUsecase(productRepo, productImageRepo, signer, transactor, auditUsecase),
+		ProductImage:  usecase.NewProductImageUsecase(productImageRepo, transactor, signer, auditUsecase),
+		ChangeFeed:    usecase.NewChangeFeedUsecase(outboxRepo),
+		ProductImport: usecase.NewProductImportUsecase(repository.NewProductImportRepository(db), transactor),
+		ProductExport: usecase.NewProductExportUsecase(repository.NewProductExportRepository(db), signer),
+		// Feeds are rendered from the database, not the catalog cache
+		ProductFeed: usecase.NewProductFeedUsecase(repository.NewProductFeedRepository(db), repository.NewProductExportRepository(db),
+			repository.NewProductRepository(db), productImageRepo, storeRepo, transactor, feed.NewRenderer(cfg)),
+		Sitemap: usecase.NewSitemapUsecase(repository.NewSitemapRepository(db), links),
+	}
+}
+
+func newEventPublishers(cfg *config.Config, u *usecase.Usecases) (map[string]domain.EventPublisher, error) {
+	publishers := map[string]domain.EventPublisher{}
+	for _, name := range cfg.OutboxPublishers {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+			continue
+		case "webhook":
+			publishers[name] = u.Webhook
+		case "nats":
+			nats, err := publisher.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubjectPrefix, cfg.NATSTimeout)
+			if err != nil {
+				return nil, err
+			}
+			publishers[name] = nats
+		case "stdout":
+			publishers[name] = publisher.NewWriterPublisher(os.Stdout)
+		default:
+			return nil, fmt.Errorf("unknown outbox publisher %q, expected webhook, nats or stdout", name)
+		}
+	}
+
+	// Merchant feeds follow changes as soon as they are configured
+	if feed.NewRenderer(cfg) != nil {
+		publishers["feed"] = u.ProductFeed
+	}
+	return publishers, nil
+}
+
+func runMediaGC(ctx context.Context, gc usecase.MediaGCUsecase, interval time.Duration, dryRun bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := gc.Collect(ctx, dryRun)
+			if err != nil {
+				log.Errorf("Media GC failed: %v", err)
+				continue
+			}
+			log.Infof("Media GC scanned %d blobs: %d referenced, %d within grace period, %d orphaned, %d deleted (dry run: %t)",
+				report.Scanned, report.Referenced, report.Recent, len(report.Orphaned), report.Deleted, report.DryRun)
+		}
+	}
+}
+
+// runWebhookDispatcher sends due deliveries every interval, and keeps going
+// without waiting while it finds any.
+func runWebhookDispatcher(ctx context.Context, dispatcher usecase.WebhookDispatcher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				sent, err := dispatcher.Dispatch(ctx)
+				if err != nil {
+					log.Errorf("Webhook dispatch failed: %v", err)
+					break
+				}
+				if sent == 0 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runOutboxRelay relays every interval, and keeps going without waiting while
+// there are events. Relayed events are pruned once an hour.
+func runOutboxRelay(ctx context.Context, relay usecase.OutboxRelay, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pruneTicker.C:
+			pruned, err := relay.Prune(ctx)
+			if err != nil {
+				log.Errorf("Outbox prune failed: %v", err)
+				continue
+			}
+			log.Infof("Outbox pruned %d relayed events", pruned)
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				relayed, err := relay.Relay(ctx)
+				if err != nil {
+					log.Errorf("Outbox relay failed: %v", err)
+					break
+				}
+				if relayed == 0 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runProductImports looks for a queued import every interval and runs jobs
+// back to back while there are any. A job cut short by shutdown is resumed
+// once its lease runs out.
+func runProductImports(ctx context.Context, runner usecase.ProductImportRunner, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pruneTicker.C:
+			pruned, err := runner.Prune(ctx)
+			if err != nil {
+				log.Errorf("Import prune failed: %v", err)
+				continue
+			}
+			log.Infof("Import pruned %d finished jobs", pruned)
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				ran, err := runner.Run(ctx)
+				if err != nil && ctx.Err() == nil {
+					log.Errorf("Import failed: %v", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
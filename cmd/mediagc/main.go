@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"product-listing/config"
+	"product-listing/internal/repository"
+	"product-listing/internal/storage"
+	"product-listing/internal/usecase"
+	"product-listing/pkg/logger"
+
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("mediagc")
+
+func main() {
+	if err := run(); err != nil {
+		log.Errorf("Media GC stopped with error: %v", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	logger.ConfigureLogger()
+	cfg := config.Load()
+
+	dryRun := flag.Bool("dry-run", false, "report orphaned media without deleting it")
+	grace := flag.Duration("grace", cfg.MediaGCGrace, "keep unreferenced media younger than this")
+	flag.Parse()
+
+	if cfg.MediaBaseURL == "" {
+		return fmt.Errorf("MEDIA_BASE_URL is required")
+	}
+
+	db, err := config.NewDatabase(cfg, repository.StoreSetting)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	store := storage.NewLocalStore(cfg.MediaStorageDir)
+	gc := usecase.NewMediaGCUsecase(store, repository.NewProductImageRepository(db), cfg.MediaBaseURL, *grace)
+
+	report, err := gc.Collect(context.Background(), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, blob := range report.Orphaned {
+		fmt.Printf("orphaned\t%s\t%d bytes\t%s\n", blob.Key, blob.Size, blob.ModTime.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("Scanned: %d\nReferenced: %d\nWithin grace period: %d\nOrphaned: %d\nDeleted: %d\nDry run: %t\n",
+		report.Scanned, report.Referenced, report.Recent, len(report.Orphaned), report.Deleted, report.DryRun)
+
+	return nil
+}
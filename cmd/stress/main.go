@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 )
@@ -145,7 +146,10 @@ func doPOSTProduct(catIDs []string) Result {
 		"category_ids": catIDs,
 		"price":        99.99,
 	})
-	resp, err := http.Post(baseURL+"/products/", "application/json", bytes.NewBuffer(body))
+	req, _ := http.NewRequest("POST", baseURL+"/products/", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", os.Getenv("API_KEY"))
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return Result{"POST /products/", 0, err}
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/op/go-logging"
@@ -11,11 +12,140 @@ var configLog = logging.MustGetLogger("config")
 
 type Config struct {
 	Port       string `env:"PORT" env-default:"8080"`
+	GRPCPort   string `env:"GRPC_PORT" env-default:"9090"`
 	DBHost     string `env:"DB_HOST" env-required:"true"`
 	DBPort     string `env:"DB_PORT" env-default:"5432"`
 	DBUser     string `env:"DB_USER" env-required:"true"`
 	DBPassword string `env:"DB_PASSWORD" env-required:"true"`
 	DBName     string `env:"DB_NAME" env-required:"true"`
+
+	// MediaSigningKeys holds "id:secret" pairs separated by commas. The first
+	// key signs new media URLs, all of them are accepted when verifying.
+	MediaSigningKeys string        `env:"MEDIA_SIGNING_KEYS"`
+	MediaURLTTL      time.Duration `env:"MEDIA_URL_TTL" env-default:"15m"`
+	// MediaOrigins lists the origins, e.g. "https://images.example.com", that
+	// private images outside MediaBaseURL may be streamed from.
+	MediaOrigins []string `env:"MEDIA_ORIGINS" env-separator:","`
+
+	// MediaStorageDir holds uploaded media files, which image URLs reference
+	// as MediaBaseURL followed by the file path inside the directory.
+	MediaStorageDir string        `env:"MEDIA_STORAGE_DIR" env-default:"storage/media"`
+	MediaBaseURL    string        `env:"MEDIA_BASE_URL"`
+	MediaGCGrace    time.Duration `env:"MEDIA_GC_GRACE" env-default:"24h"`
+	MediaGCInterval time.Duration `env:"MEDIA_GC_INTERVAL" env-default:"0"`
+	MediaGCDryRun   bool          `env:"MEDIA_GC_DRY_RUN" env-default:"false"`
+
+	// AuthBootstrapKey is an admin API key accepted without a database record,
+	// meant for creating the first real keys.
+	AuthBootstrapKey string `env:"AUTH_BOOTSTRAP_KEY"`
+	JWTHS256Secret   string `env:"JWT_HS256_SECRET"`
+	JWTJWKSFile      string `env:"JWT_JWKS_FILE"`
+	JWTIssuer        string `env:"JWT_ISSUER"`
+	JWTAudience      string `env:"JWT_AUDIENCE"`
+	JWTRoleClaim     string `env:"JWT_ROLE_CLAIM" env-default:"role"`
+	JWTStoreClaim    string `env:"JWT_STORE_CLAIM" env-default:"store"`
+
+	// DefaultStore is the slug of the store used when a request names none.
+	// Leave it empty to require every request to name its store.
+	DefaultStore    string `env:"DEFAULT_STORE" env-default:"default"`
+	StoreBaseDomain string `env:"STORE_BASE_DOMAIN"`
+
+	// RateLimitBackend is "memory" for limits per replica or "postgres" for
+	// limits shared by all replicas. The read and write limits are requests
+	// per RateLimitWindow for each caller; 0 disables the limit.
+	RateLimitBackend string        `env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+	RateLimitRead    int           `env:"RATE_LIMIT_READ" env-default:"600"`
+	RateLimitWrite   int           `env:"RATE_LIMIT_WRITE" env-default:"60"`
+	RateLimitWindow  time.Duration `env:"RATE_LIMIT_WINDOW" env-default:"1m"`
+
+	// IdempotencyTTL is how long responses to requests with an
+	// Idempotency-Key header are kept for replay. A request holds its key for
+	// IdempotencyLockTimeout at most, after that a retry may claim it again.
+	IdempotencyTTL         time.Duration `env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	IdempotencyLockTimeout time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT" env-default:"1m"`
+
+	// APIV1DeprecatedAt and APIV1Sunset are announced on every v1 response:
+	// the day v1 was deprecated in favor of /api/v2 and the day it goes away.
+	APIV1DeprecatedAt time.Time `env:"API_V1_DEPRECATED_AT" env-layout:"2006-01-02" env-default:"2026-10-19"`
+	APIV1Sunset       time.Time `env:"API_V1_SUNSET" env-layout:"2006-01-02" env-default:"2027-04-19"`
+
+	// GraphQLMaxDepth caps how deeply a GraphQL query may nest fields and
+	// GraphQLMaxComplexity the estimated number of fields it may resolve.
+	GraphQLMaxDepth      int `env:"GRAPHQL_MAX_DEPTH" env-default:"8"`
+	GraphQLMaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY" env-default:"10000"`
+
+	// Webhook deliveries are polled every WebhookPollInterval, 0 disables the
+	// in-process dispatcher. A failed delivery waits WebhookBackoffBase,
+	// doubling per attempt up to WebhookBackoffMax, and is dead after
+	// WebhookMaxAttempts.
+	WebhookPollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" env-default:"1s"`
+	WebhookTimeout      time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	WebhookMaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+	WebhookBackoffBase  time.Duration `env:"WEBHOOK_BACKOFF_BASE" env-default:"30s"`
+	WebhookBackoffMax   time.Duration `env:"WEBHOOK_BACKOFF_MAX" env-default:"1h"`
+
+	// Change events are relayed from the outbox to OutboxPublishers (webhook,
+	// nats, stdout) every OutboxPollInterval, 0 disables the relay. Relayed
+	// events are kept for OutboxRetention.
+	OutboxPublishers   []string      `env:"OUTBOX_PUBLISHERS" env-separator:"," env-default:"webhook"`
+	OutboxPollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" env-default:"500ms"`
+	OutboxRetention    time.Duration `env:"OUTBOX_RETENTION" env-default:"168h"`
+	NATSURL            string        `env:"NATS_URL" env-default:"nats://localhost:4222"`
+	NATSSubjectPrefix  string        `env:"NATS_SUBJECT_PREFIX" env-default:"catalog"`
+	NATSTimeout        time.Duration `env:"NATS_TIMEOUT" env-default:"5s"`
+
+	// Event streams read new events every EventStreamPollInterval and send a
+	// heartbeat after EventStreamHeartbeat without events, so proxies keep
+	// idle streams open.
+	EventStreamPollInterval time.Duration `env:"EVENT_STREAM_POLL_INTERVAL" env-default:"1s"`
+	EventStreamHeartbeat    time.Duration `env:"EVENT_STREAM_HEARTBEAT" env-default:"15s"`
+
+	// CacheBackend is "memory" to cache products and categories per replica,
+	// "redis" to share the cache between replicas or "none". Entries live for
+	// CacheTTL at most, changes drop them earlier.
+	CacheBackend    string        `env:"CACHE_BACKEND" env-default:"memory"`
+	CacheTTL        time.Duration `env:"CACHE_TTL" env-default:"5m"`
+	CacheMaxEntries int           `env:"CACHE_MAX_ENTRIES" env-default:"10000"`
+	RedisAddr       string        `env:"REDIS_ADDR" env-default:"localhost:6379"`
+	RedisPassword   string        `env:"REDIS_PASSWORD"`
+	RedisDB         int           `env:"REDIS_DB" env-default:"0"`
+	RedisPrefix     string        `env:"REDIS_PREFIX" env-default:"catalog:"`
+	RedisTimeout    time.Duration `env:"REDIS_TIMEOUT" env-default:"500ms"`
+
+	// Anonymous catalog reads may be kept by browsers for HTTPCacheMaxAge and
+	// by a CDN for HTTPCacheSharedMaxAge. When CDNPurgeURL is set, changes
+	// purge their surrogate keys there, and missed changes everything through
+	// CDNPurgeAllURL, authenticated by CDNPurgeToken sent in the
+	// CDNPurgeTokenHeader header.
+	HTTPCacheMaxAge       time.Duration `env:"HTTP_CACHE_MAX_AGE" env-default:"60s"`
+	HTTPCacheSharedMaxAge time.Duration `env:"HTTP_CACHE_SHARED_MAX_AGE" env-default:"1h"`
+	CDNPurgeURL           string        `env:"CDN_PURGE_URL"`
+	CDNPurgeAllURL        string        `env:"CDN_PURGE_ALL_URL"`
+	CDNPurgeToken         string        `env:"CDN_PURGE_TOKEN"`
+	CDNPurgeTokenHeader   string        `env:"CDN_PURGE_TOKEN_HEADER" env-default:"Fastly-Key"`
+	CDNPurgeTimeout       time.Duration `env:"CDN_PURGE_TIMEOUT" env-default:"10s"`
+
+	// Product import files of up to ImportMaxBytes are applied in the
+	// background, polling for queued jobs every ImportPollInterval, 0
+	// disables the runner. Finished jobs are kept for ImportRetention.
+	ImportMaxBytes     int64         `env:"IMPORT_MAX_BYTES" env-default:"104857600"`
+	ImportPollInterval time.Duration `env:"IMPORT_POLL_INTERVAL" env-default:"1s"`
+	ImportRetention    time.Duration `env:"IMPORT_RETENTION" env-default:"168h"`
+
+	// Storefront pages live at StorefrontBaseURL, which may contain {store},
+	// followed by StorefrontProductPath or StorefrontCategoryPath, which may
+	// contain {store}, {slug} and {id}. Merchant feeds and sitemaps are served
+	// when StorefrontBaseURL is set. Feeds have prices in FeedCurrency and
+	// categories mapped to the Google product taxonomy by
+	// FeedGoogleCategories, "slug:taxonomy ID" pairs. Feeds may be cached for
+	// FeedMaxAge and sitemaps for SitemapMaxAge.
+	StorefrontBaseURL      string            `env:"STOREFRONT_BASE_URL"`
+	StorefrontProductPath  string            `env:"STOREFRONT_PRODUCT_PATH" env-default:"/products/{slug}"`
+	StorefrontCategoryPath string            `env:"STOREFRONT_CATEGORY_PATH" env-default:"/categories/{slug}"`
+	FeedCurrency           string            `env:"FEED_CURRENCY" env-default:"USD"`
+	FeedGoogleCategories   map[string]string `env:"FEED_GOOGLE_CATEGORIES" env-separator:","`
+	FeedMaxAge             time.Duration     `env:"FEED_MAX_AGE" env-default:"1h"`
+	SitemapMaxAge          time.Duration     `env:"SITEMAP_MAX_AGE" env-default:"1h"`
 }
 
 func Load() *Config {
@@ -4,18 +4,30 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/op/go-logging"
 )
 
 var dbLog = logging.MustGetLogger("database")
 
+// maxListenBackoff caps the wait between attempts to reopen a lost listener
+// connection.
+const maxListenBackoff = 30 * time.Second
+
 type Database struct {
 	Pool *pgxpool.Pool
+
+	// listenConfig opens listener connections, which stay out of the pool
+	listenConfig *pgx.ConnConfig
 }
 
-func NewDatabase(cfg *Config) (*Database, error) {
+// NewDatabase opens the connection pool. storeSetting gives the app.store_id
+// setting for the context a connection is acquired with, see storeSessions.
+func NewDatabase(cfg *Config, storeSetting func(context.Context) string) (*Database, error) {
 	if err := ensureDatabaseExists(cfg); err != nil {
 		return nil, err
 	}
@@ -26,6 +38,10 @@ func NewDatabase(cfg *Config) (*Database, error) {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
 
+	sessions := &storeSessions{setting: storeSetting}
+	config.PrepareConn = sessions.prepare
+	config.BeforeClose = sessions.forget
+
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
@@ -36,7 +52,37 @@ func NewDatabase(cfg *Config) (*Database, error) {
 	}
 
 	dbLog.Info("Database connected successfully")
-	return &Database{Pool: pool}, nil
+	return &Database{Pool: pool, listenConfig: config.ConnConfig.Copy()}, nil
+}
+
+// storeSessions keeps each connection's app.store_id setting in step with the
+// store of the request that acquires it. The row level security policies read
+// the setting, so a query that forgot its store filter still cannot see
+// another store's rows. The last value per connection is remembered to avoid
+// a round trip when it does not change.
+type storeSessions struct {
+	setting func(context.Context) string
+	current sync.Map // *pgx.Conn -> string
+}
+
+func (s *storeSessions) prepare(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	storeID := s.setting(ctx)
+
+	if current, ok := s.current.Load(conn); ok && current.(string) == storeID {
+		return true, nil
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT set_config('app.store_id', $1, false)", storeID); err != nil {
+		// Drop the connection rather than hand it out with a stale store
+		s.current.Delete(conn)
+		return false, fmt.Errorf("failed to set store for connection: %w", err)
+	}
+	s.current.Store(conn, storeID)
+	return true, nil
+}
+
+func (s *storeSessions) forget(conn *pgx.Conn) {
+	s.current.Delete(conn)
 }
 
 func ensureDatabaseExists(cfg *Config) error {
@@ -85,6 +131,57 @@ func (db *Database) InitSchema(schemaPath string) error {
 	return nil
 }
 
+// Listen hands every notification on channel to handle until ctx is done. It
+// holds a connection of its own, reopened with backoff when it is lost.
+// reconnected is called each time a lost connection listens again, since
+// notifications sent while it was down are gone. It is not called when the
+// first connection starts listening, nothing was missed yet.
+func (db *Database) Listen(ctx context.Context, channel string, reconnected func(), handle func(payload string)) {
+	backoff := time.Second
+	listened := false
+	for ctx.Err() == nil {
+		err := db.listen(ctx, channel, func() {
+			backoff = time.Second
+			if listened {
+				reconnected()
+			}
+			listened = true
+		}, handle)
+		if ctx.Err() != nil {
+			return
+		}
+
+		dbLog.Errorf("Listener on %s lost: %v, reconnecting in %s", channel, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+func (db *Database) listen(ctx context.Context, channel string, connected func(), handle func(payload string)) error {
+	conn, err := pgx.ConnectConfig(ctx, db.listenConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	connected()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
+
 func (db *Database) Close() {
 	db.Pool.Close()
 }
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"product-listing/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTConfig struct {
+	// HS256Secret enables HS256 tokens when set
+	HS256Secret string
+	// JWKSFile enables RS256 tokens signed by the RSA keys in the file
+	JWKSFile  string
+	Issuer    string
+	Audience  string
+	RoleClaim string
+	// StoreClaim names the claim binding a token to one store
+	StoreClaim string
+}
+
+type jwtVerifier struct {
+	secret     []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	parser     *jwt.Parser
+	roleClaim  string
+	storeClaim string
+}
+
+// NewJWTVerifier returns nil when neither HS256 nor RS256 is configured.
+func NewJWTVerifier(cfg JWTConfig) (domain.TokenVerifier, error) {
+	if cfg.HS256Secret == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+
+	v := &jwtVerifier{roleClaim: cfg.RoleClaim, storeClaim: cfg.StoreClaim}
+	var methods []string
+
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, "HS256")
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+		methods = append(methods, "RS256")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+func (v *jwtVerifier) Verify(token string) (*domain.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims.GetSubject()
+	role := highestRole(claims[v.roleClaim])
+	if role == "" {
+		return nil, fmt.Errorf("token has no valid %q claim", v.roleClaim)
+	}
+
+	principal := &domain.Principal{Subject: subject, Role: role, Method: "jwt"}
+	if v.storeClaim != "" {
+		principal.Store, _ = claims[v.storeClaim].(string)
+	}
+	return principal, nil
+}
+
+func (v *jwtVerifier) key(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case "HS256":
+		return v.secret, nil
+	case "RS256":
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		// Tokens without a kid are accepted when the set holds a single key
+		if kid == "" && len(v.rsaKeys) == 1 {
+			for _, key := range v.rsaKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// highestRole accepts a single role or a list of roles.
+func highestRole(claim any) domain.Role {
+	var names []string
+	switch value := claim.(type) {
+	case string:
+		names = append(names, value)
+	case []any:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var best domain.Role
+	for _, name := range names {
+		role := domain.Role(name)
+		if role.Valid() && (best == "" || !best.Allows(role)) {
+			best = role
+		}
+	}
+	return best
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for key %q: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwks file contains no RSA keys")
+	}
+	return keys, nil
+}
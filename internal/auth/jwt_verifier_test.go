@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "hs256 test secret"
+
+// writeJWKS saves the public half of key under kid and returns the file.
+func writeJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	set := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "https://issuer.example.com",
+		"aud":   "product-listing",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"viewer", "editor"},
+		"store": "acme",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, claims jwt.MapClaims, kid string, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed
+}
+
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The public key as an attacker would find it, to sign HS256 tokens with
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	jwksFile := writeJWKS(t, "k1", rsaKey)
+
+	config := func(hs256, rs256 bool) JWTConfig {
+		cfg := JWTConfig{Issuer: "https://issuer.example.com", Audience: "product-listing", RoleClaim: "roles", StoreClaim: "store"}
+		if hs256 {
+			cfg.HS256Secret = testSecret
+		}
+		if rs256 {
+			cfg.JWKSFile = jwksFile
+		}
+		return cfg
+	}
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		cfg   JWTConfig
+		token string
+		valid bool
+	}{
+		{"HS256", config(true, false), sign(t, jwt.SigningMethodHS256, validClaims(), "", []byte(testSecret)), true},
+		{"RS256", config(false, true), sign(t, jwt.SigningMethodRS256, validClaims(), "k1", rsaKey), true},
+		{"RS256 without kid", config(false, true), sign(t, jwt.SigningMethodRS256, validClaims(), "", rsaKey), true},
+		{"HS256 with public key against RS256", config(false, true), sign(t, jwt.SigningMethodHS256, validClaims(), "k1", publicPEM), false},
+		{"HS256 with public key against both", config(true, true), sign(t, jwt.SigningMethodHS256, validClaims(), "k1", publicPEM), false},
+		{"RS256 against HS256", config(true, false), sign(t, jwt.SigningMethodRS256, validClaims(), "k1", rsaKey), false},
+		{"none", config(true, true), sign(t, jwt.SigningMethodNone, validClaims(), "", jwt.UnsafeAllowNoneSignatureType), false},
+		{"unknown kid", config(false, true), sign(t, jwt.SigningMethodRS256, validClaims(), "k2", rsaKey), false},
+		{"unlisted key", config(false, true), sign(t, jwt.SigningMethodRS256, validClaims(), "k1", otherKey), false},
+		{"wrong secret", config(true, false), sign(t, jwt.SigningMethodHS256, validClaims(), "", []byte("guessed")), false},
+		{"wrong issuer", config(true, false), sign(t, jwt.SigningMethodHS256, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }), "", []byte(testSecret)), false},
+		{"wrong audience", config(true, false), sign(t, jwt.SigningMethodHS256, with(func(c jwt.MapClaims) { c["aud"] = "billing" }), "", []byte(testSecret)), false},
+		{"expired", config(true, false), sign(t, jwt.SigningMethodHS256, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }), "", []byte(testSecret)), false},
+		{"no expiry", config(true, false), sign(t, jwt.SigningMethodHS256, with(func(c jwt.MapClaims) { delete(c, "exp") }), "", []byte(testSecret)), false},
+		{"no role", config(true, false), sign(t, jwt.SigningMethodHS256, with(func(c jwt.MapClaims) { c["roles"] = []string{"owner"} }), "", []byte(testSecret)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewJWTVerifier(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewJWTVerifier: %v", err)
+			}
+			principal, err := v.Verify(tt.token)
+			if tt.valid && err != nil {
+				t.Errorf("Verify = %v, want a principal", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Verify = %+v, want an error", principal)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierMapsClaims(t *testing.T) {
+	v, err := NewJWTVerifier(JWTConfig{HS256Secret: testSecret, RoleClaim: "roles", StoreClaim: "store"})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		roles any
+		store any
+		want  domain.Principal
+	}{
+		{"highest of several roles", []string{"viewer", "admin", "editor"}, "acme", domain.Principal{Role: domain.RoleAdmin, Store: "acme"}},
+		{"single role", "editor", nil, domain.Principal{Role: domain.RoleEditor}},
+		{"unknown roles skipped", []any{"owner", "viewer", 3}, "acme", domain.Principal{Role: domain.RoleViewer, Store: "acme"}},
+		{"non-string store ignored", "viewer", 42, domain.Principal{Role: domain.RoleViewer}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix(), "roles": tt.roles}
+			if tt.store != nil {
+				claims["store"] = tt.store
+			}
+			principal, err := v.Verify(sign(t, jwt.SigningMethodHS256, claims, "", []byte(testSecret)))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			tt.want.Subject, tt.want.Method = "alice", "jwt"
+			if *principal != tt.want {
+				t.Errorf("principal = %+v, want %+v", *principal, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifierDisabled(t *testing.T) {
+	v, err := NewJWTVerifier(JWTConfig{RoleClaim: "roles"})
+	if v != nil || err != nil {
+		t.Errorf("NewJWTVerifier = %v, %v, want nil, nil", v, err)
+	}
+}
@@ -0,0 +1,44 @@
+package cache
+
+import "sync"
+
+// Group coalesces concurrent loads of the same key, so a hot key that misses
+// is loaded once while the other callers wait for that load.
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// Do runs load unless a load of key is already running, and returns what
+// that load returned.
+func (g *Group) Do(key string, load func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err
+	}
+
+	c := &call{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = map[string]*call{}
+	}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.value, c.err = load()
+	return c.value, c.err
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupCoalescesConcurrentLoads(t *testing.T) {
+	var g Group
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _ := g.Do("hot", func() ([]byte, error) {
+				loads.Add(1)
+				<-release
+				return []byte("value"), nil
+			})
+			results[i] = string(v)
+		}()
+	}
+
+	// Give every caller time to join the running load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+	for i, v := range results {
+		if v != "value" {
+			t.Errorf("caller %d got %q", i, v)
+		}
+	}
+}
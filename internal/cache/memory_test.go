@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("a is missing")
+	}
+	_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b survived, want it evicted as the least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestMemoryCacheExpiresAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	_ = c.Set(ctx, "old", []byte("1"), -time.Second)
+	_ = c.Set(ctx, "gone", []byte("2"), time.Minute)
+	_ = c.Set(ctx, "kept", []byte("3"), time.Minute)
+	_ = c.Delete(ctx, "gone")
+
+	if _, ok, _ := c.Get(ctx, "old"); ok {
+		t.Error("expired entry was returned")
+	}
+	if _, ok, _ := c.Get(ctx, "gone"); ok {
+		t.Error("deleted entry was returned")
+	}
+	if v, ok, _ := c.Get(ctx, "kept"); !ok || string(v) != "3" {
+		t.Errorf("Get(kept) = %q, %t", v, ok)
+	}
+
+	_ = c.Clear(ctx)
+	if _, ok, _ := c.Get(ctx, "kept"); ok {
+		t.Error("entry survived Clear")
+	}
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"product-listing/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisPoolSize is how many idle connections are kept for reuse.
+const redisPoolSize = 16
+
+// redisScanCount is how many keys Clear asks for per SCAN.
+const redisScanCount = 500
+
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix is put in front of every key, so the catalog can share a
+	// database and Clear only drops its own keys.
+	Prefix  string
+	Timeout time.Duration
+}
+
+// redisCache speaks the Redis protocol, so it works with Redis and with
+// compatible servers such as Valkey or KeyDB. It is shared by every replica.
+type redisCache struct {
+	cfg  RedisConfig
+	idle chan *redisConn
+}
+
+func NewRedisCache(cfg RedisConfig) domain.Cache {
+	return &redisCache{cfg: cfg, idle: make(chan *redisConn, redisPoolSize)}
+}
+
+// redisError is an error reply. The connection stays usable after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", c.cfg.Prefix+key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %v", reply)
+	}
+	return value, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", c.cfg.Prefix+key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, c.cfg.Prefix+key)
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Clear deletes the keys under the prefix, a batch at a time. Keys written
+// while it runs may survive.
+func (c *redisCache) Clear(ctx context.Context) error {
+	pattern := escapeGlob(c.cfg.Prefix) + "*"
+	cursor := "0"
+	for {
+		reply, err := c.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			return err
+		}
+
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]any)
+
+		if len(keys) > 0 {
+			args := []string{"DEL"}
+			for _, key := range keys {
+				if b, ok := key.([]byte); ok {
+					args = append(args, string(b))
+				}
+			}
+			if _, err := c.do(ctx, args...); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// do sends one command on a pooled connection and reads its reply. A nil
+// bulk reply is returned as nil.
+func (c *redisCache) do(ctx context.Context, args ...string) (any, error) {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.netConn.SetDeadline(deadline); err != nil {
+		conn.netConn.Close()
+		return nil, err
+	}
+
+	reply, err := conn.do(args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		conn.netConn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.idle <- conn:
+	default:
+		conn.netConn.Close()
+	}
+	return reply, err
+}
+
+func (c *redisCache) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.cfg.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{netConn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if err := netConn.SetDeadline(time.Now().Add(c.cfg.Timeout)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if c.cfg.Password != "" {
+		if _, err := conn.do([]string{"AUTH", c.cfg.Password}); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if c.cfg.DB != 0 {
+		if _, err := conn.do([]string{"SELECT", strconv.Itoa(c.cfg.DB)}); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+type redisConn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	w       *bufio.Writer
+}
+
+func (c *redisConn) do(args []string) (any, error) {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// readReply reads one RESP reply: simple strings and bulk strings as []byte,
+// integers as int64 and arrays as []any.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
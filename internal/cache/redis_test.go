@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a stand-in Redis server that keeps strings in memory and
+// understands the commands the cache sends. Expiry is not modelled.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedis{listener: listener, values: map[string]string{}}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]any) {
+			args = append(args, string(arg.([]byte)))
+		}
+		fmt.Fprint(conn, s.handle(args))
+	}
+}
+
+func (s *fakeRedis) handle(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = append(s.commands, args[0])
+	switch strings.ToUpper(args[0]) {
+	case "AUTH":
+		if args[1] != "secret" {
+			return "-WRONGPASS invalid password\r\n"
+		}
+		return "+OK\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		v, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		s.values[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.values[key]; ok {
+				delete(s.values, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "SCAN":
+		var keys []string
+		for key := range s.values {
+			if ok, _ := path.Match(args[3], key); ok {
+				keys = append(keys, key)
+			}
+		}
+		reply := fmt.Sprintf("*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+		for _, key := range keys {
+			reply += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+		}
+		return reply
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+// state returns copies of the stored values and the commands received.
+func (s *fakeRedis) state() (map[string]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := map[string]string{}
+	for k, v := range s.values {
+		values[k] = v
+	}
+	return values, append([]string(nil), s.commands...)
+}
+
+func TestRedisCacheRoundTrip(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedisCache(RedisConfig{Addr: server.listener.Addr().String(), Password: "secret", DB: 2, Prefix: "catalog:", Timeout: time.Second})
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Get(missing) = %t, %v, want a miss", ok, err)
+	}
+
+	value := []byte("line\r\nbreak")
+	if err := c.Set(ctx, "product", value, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, ok, err := c.Get(ctx, "product")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Errorf("Get(product) = %q, %t, %v, want %q", got, ok, err, value)
+	}
+	if values, _ := server.state(); values["catalog:product"] == "" {
+		t.Error("key was stored without its prefix")
+	}
+
+	if err := c.Delete(ctx, "product"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "product"); ok {
+		t.Error("deleted key was returned")
+	}
+
+	// The connection is authenticated and reused
+	_, commands := server.state()
+	if commands[0] != "AUTH" || commands[1] != "SELECT" {
+		t.Errorf("connection opened with %v", commands[:2])
+	}
+	for _, cmd := range commands[2:] {
+		if cmd == "AUTH" {
+			t.Errorf("connection was not reused: %v", commands)
+			break
+		}
+	}
+}
+
+func TestRedisCacheClearKeepsOtherKeys(t *testing.T) {
+	server := newFakeRedis(t)
+	server.values["other:key"] = "kept"
+	c := NewRedisCache(RedisConfig{Addr: server.listener.Addr().String(), Prefix: "catalog:", Timeout: time.Second})
+	ctx := context.Background()
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if values, _ := server.state(); len(values) != 1 || values["other:key"] != "kept" {
+		t.Errorf("after Clear the server holds %v", values)
+	}
+}
+
+func TestRedisCacheReportsErrorReplies(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedisCache(RedisConfig{Addr: server.listener.Addr().String(), Password: "wrong", Timeout: time.Second})
+
+	if _, _, err := c.Get(context.Background(), "key"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Get = %v, want the AUTH error", err)
+	}
+}
@@ -0,0 +1,124 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"product-listing/internal/domain"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/op/go-logging"
+)
+
+var cdnLog = logging.MustGetLogger("cdn")
+
+// maxKeysPerPurge caps the keys sent in one purge request.
+const maxKeysPerPurge = 256
+
+// httpPurger purges by POSTing the keys, space separated, in the
+// Surrogate-Key header, the way Fastly's batch purge API takes them. Purging
+// everything is a POST to allURL, like Fastly's purge_all.
+type httpPurger struct {
+	url         string
+	allURL      string
+	tokenHeader string
+	token       string
+	client      *http.Client
+}
+
+// NewHTTPPurger purges keys through url and everything through allURL,
+// sending token in tokenHeader when set. Without allURL nothing can be
+// purged at once, and stale responses are left to expire.
+func NewHTTPPurger(url, allURL, tokenHeader, token string, timeout time.Duration) domain.CachePurger {
+	return &httpPurger{url: url, allURL: allURL, tokenHeader: tokenHeader, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *httpPurger) Purge(ctx context.Context, keys []string) error {
+	for batch := range slices.Chunk(keys, maxKeysPerPurge) {
+		if err := p.post(ctx, p.url, strings.Join(batch, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *httpPurger) PurgeAll(ctx context.Context) error {
+	if p.allURL == "" {
+		return errors.New("no purge all URL configured")
+	}
+	return p.post(ctx, p.allURL, "")
+}
+
+func (p *httpPurger) post(ctx context.Context, url, keys string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	if keys != "" {
+		req.Header.Set("Surrogate-Key", keys)
+	}
+	if p.token != "" {
+		req.Header.Set(p.tokenHeader, p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("purge responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Watch purges what the changes announced on changes made stale until ctx is
+// done. Changes that are waiting together are purged in one call, and a
+// missed change purges everything. A failed purge is logged and not retried,
+// the responses expire on their own.
+func Watch(ctx context.Context, purger domain.CachePurger, changes domain.ChangeBus) {
+	ch, unsubscribe := changes.Subscribe()
+	defer unsubscribe()
+
+	for {
+		var keys []string
+		missed := false
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-ch:
+			keys = change.SurrogateKeys()
+			missed = change.Missed()
+		}
+
+	drain:
+		for {
+			select {
+			case change := <-ch:
+				keys = append(keys, change.SurrogateKeys()...)
+				missed = missed || change.Missed()
+			default:
+				break drain
+			}
+		}
+
+		if missed {
+			if err := purger.PurgeAll(ctx); err != nil && ctx.Err() == nil {
+				cdnLog.Errorf("CDN purge of everything after missed changes failed: %v", err)
+			}
+			continue
+		}
+
+		slices.Sort(keys)
+		keys = slices.Compact(keys)
+		if err := purger.Purge(ctx, keys); err != nil && ctx.Err() == nil {
+			cdnLog.Errorf("CDN purge of %d keys failed: %v", len(keys), err)
+		}
+	}
+}
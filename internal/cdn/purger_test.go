@@ -0,0 +1,144 @@
+package cdn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"product-listing/internal/changebus"
+	"product-listing/internal/domain"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHTTPPurgerSendsKeysInBatches(t *testing.T) {
+	var mu sync.Mutex
+	var batches []string
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Fastly-Key") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		batches = append(batches, r.Header.Get("Surrogate-Key"))
+		mu.Unlock()
+	}))
+	defer cdn.Close()
+
+	keys := make([]string, maxKeysPerPurge+1)
+	for i := range keys {
+		keys[i] = domain.ProductSurrogateKey(uuid.NewString())
+	}
+
+	if err := NewHTTPPurger(cdn.URL, "", "Fastly-Key", "token", time.Second).Purge(context.Background(), keys); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(batches) != 2 || len(strings.Fields(batches[0])) != maxKeysPerPurge || batches[1] != keys[maxKeysPerPurge] {
+		t.Errorf("purged in %d batches, want %d keys and then 1", len(batches), maxKeysPerPurge)
+	}
+
+	if err := NewHTTPPurger(cdn.URL, "", "Fastly-Key", "wrong", time.Second).Purge(context.Background(), keys[:1]); err == nil {
+		t.Error("Purge succeeded although the CDN refused it")
+	}
+}
+
+func TestHTTPPurgerPurgesAll(t *testing.T) {
+	var paths []string
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+" "+r.Header.Get("Surrogate-Key"))
+	}))
+	defer cdn.Close()
+
+	purger := NewHTTPPurger(cdn.URL+"/purge", cdn.URL+"/purge_all", "Fastly-Key", "token", time.Second)
+	if err := purger.PurgeAll(context.Background()); err != nil {
+		t.Fatalf("PurgeAll: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/purge_all " {
+		t.Errorf("requests = %q, want one to /purge_all without keys", paths)
+	}
+
+	if err := NewHTTPPurger(cdn.URL+"/purge", "", "Fastly-Key", "token", time.Second).PurgeAll(context.Background()); err == nil {
+		t.Error("PurgeAll succeeded without a purge all URL")
+	}
+}
+
+// allKeys is what recordingPurger reports for a purge of everything.
+const allKeys = "*"
+
+// recordingPurger hands every purge to purged.
+type recordingPurger struct {
+	purged chan []string
+}
+
+func (p *recordingPurger) Purge(ctx context.Context, keys []string) error {
+	p.purged <- keys
+	return nil
+}
+
+func (p *recordingPurger) PurgeAll(ctx context.Context) error {
+	p.purged <- []string{allKeys}
+	return nil
+}
+
+func TestWatchPurgesChangedEntities(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := changebus.New()
+	purger := &recordingPurger{purged: make(chan []string, 1)}
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, purger, bus)
+		close(done)
+	}()
+
+	// Wait for the subscription before publishing
+	storeID := uuid.New()
+	productID := uuid.NewString()
+	deadline := time.After(time.Second)
+	var keys []string
+	for keys == nil {
+		bus.Publish(domain.CatalogChange{StoreID: storeID, EntityType: domain.AuditEntityProductImage, EntityID: uuid.NewString(), ProductID: productID, Action: domain.AuditActionCreate})
+		select {
+		case keys = <-purger.purged:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("nothing was purged")
+		}
+	}
+
+	if len(keys) != 1 || keys[0] != domain.ProductSurrogateKey(productID) {
+		t.Errorf("purged %v, want the key of the image's product", keys)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWatchPurgesAllAfterMissedChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := changebus.New()
+	purger := &recordingPurger{purged: make(chan []string, 1)}
+	go Watch(ctx, purger, bus)
+
+	deadline := time.After(time.Second)
+	var keys []string
+	for keys == nil {
+		bus.Publish(domain.CatalogChange{})
+		select {
+		case keys = <-purger.purged:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("nothing was purged")
+		}
+	}
+
+	if len(keys) != 1 || keys[0] != allKeys {
+		t.Errorf("purged %v, want everything", keys)
+	}
+}
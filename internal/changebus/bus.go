@@ -0,0 +1,58 @@
+package changebus
+
+import (
+	"product-listing/internal/domain"
+	"sync"
+)
+
+// subscriberBuffer is how many changes a subscriber may fall behind before
+// it is told that it missed some.
+const subscriberBuffer = 64
+
+// Bus fans catalog changes out to the subscribers in this process. Publish
+// never waits for a subscriber: one that falls behind gets a missed change
+// in place of the changes it had no room for.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan domain.CatalogChange]struct{}
+}
+
+func New() *Bus {
+	return &Bus{subscribers: map[chan domain.CatalogChange]struct{}{}}
+}
+
+func (b *Bus) Subscribe() (<-chan domain.CatalogChange, func()) {
+	ch := make(chan domain.CatalogChange, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+func (b *Bus) Publish(change domain.CatalogChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- change:
+		default:
+			// Drop the oldest change for a missed one, which stands for it
+			// and for this one
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- domain.CatalogChange{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package changebus
+
+import (
+	"product-listing/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBusFansOutUntilUnsubscribed(t *testing.T) {
+	bus := New()
+	first, unsubscribe := bus.Subscribe()
+	second, _ := bus.Subscribe()
+
+	change := domain.CatalogChange{StoreID: uuid.New(), EntityType: domain.AuditEntityProduct, EntityID: "1", Action: domain.AuditActionUpdate}
+	bus.Publish(change)
+	if got := <-first; got != change {
+		t.Errorf("first subscriber got %+v, want %+v", got, change)
+	}
+	if got := <-second; got != change {
+		t.Errorf("second subscriber got %+v, want %+v", got, change)
+	}
+
+	unsubscribe()
+	bus.Publish(change)
+	select {
+	case got := <-first:
+		t.Errorf("unsubscribed subscriber got %+v", got)
+	default:
+	}
+	if got := <-second; got != change {
+		t.Errorf("second subscriber got %+v, want %+v", got, change)
+	}
+}
+
+func TestBusReportsMissedChangesToSlowSubscribers(t *testing.T) {
+	bus := New()
+	changes, _ := bus.Subscribe()
+
+	for i := range subscriberBuffer + 10 {
+		bus.Publish(domain.CatalogChange{EntityType: domain.AuditEntityCategory, EntityID: string(rune('a' + i%26))})
+	}
+
+	var last domain.CatalogChange
+	for range subscriberBuffer {
+		last = <-changes
+	}
+	if !last.Missed() {
+		t.Errorf("last change = %+v, want a missed change", last)
+	}
+}
+
+func TestParseNotification(t *testing.T) {
+	storeID := uuid.New()
+	for _, tc := range []struct {
+		payload string
+		want    domain.CatalogChange
+	}{
+		{
+			payload: `{"table":"products","op":"insert","store_id":"` + storeID.String() + `","id":"p1","product_id":null}`,
+			want:    domain.CatalogChange{StoreID: storeID, EntityType: domain.AuditEntityProduct, EntityID: "p1", Action: domain.AuditActionCreate},
+		},
+		{
+			payload: `{"table":"product_images","op":"delete","store_id":"` + storeID.String() + `","id":"i1","product_id":"p1"}`,
+			want:    domain.CatalogChange{StoreID: storeID, EntityType: domain.AuditEntityProductImage, EntityID: "i1", ProductID: "p1", Action: domain.AuditActionDelete},
+		},
+		{
+			payload: `{"table":"product_categories","op":"delete","store_id":"` + storeID.String() + `","id":"p1","product_id":"p1"}`,
+			want:    domain.CatalogChange{StoreID: storeID, EntityType: domain.AuditEntityProduct, EntityID: "p1", Action: domain.AuditActionUpdate},
+		},
+	} {
+		got, err := parseNotification(tc.payload)
+		if err != nil || got != tc.want {
+			t.Errorf("parseNotification(%s) = %+v, %v, want %+v", tc.payload, got, err, tc.want)
+		}
+	}
+
+	if _, err := parseNotification(`{"table":"stores","op":"insert"}`); err == nil {
+		t.Error("parseNotification accepted a table outside the catalog")
+	}
+}
@@ -0,0 +1,80 @@
+package changebus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-listing/config"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/op/go-logging"
+)
+
+var changeBusLog = logging.MustGetLogger("changebus")
+
+// Channel is where the catalog triggers of the schema notify.
+const Channel = "catalog_changes"
+
+// notification is the payload of the catalog triggers.
+type notification struct {
+	Table     string    `json:"table"`
+	Op        string    `json:"op"`
+	StoreID   uuid.UUID `json:"store_id"`
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+}
+
+var notificationActions = map[string]string{
+	"insert": domain.AuditActionCreate,
+	"update": domain.AuditActionUpdate,
+	"delete": domain.AuditActionDelete,
+}
+
+// Listen publishes the changes announced by the database on bus until ctx is
+// done. Each time the listener reconnects after a lost connection, which
+// means notifications were missed, a missed change is published.
+func Listen(ctx context.Context, database *config.Database, bus *Bus) {
+	database.Listen(ctx, Channel, func() {
+		bus.Publish(domain.CatalogChange{})
+	}, func(payload string) {
+		change, err := parseNotification(payload)
+		if err != nil {
+			changeBusLog.Warningf("Ignoring catalog notification: %v", err)
+			return
+		}
+		bus.Publish(change)
+	})
+}
+
+func parseNotification(payload string) (domain.CatalogChange, error) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return domain.CatalogChange{}, fmt.Errorf("invalid payload %q: %w", payload, err)
+	}
+
+	change := domain.CatalogChange{
+		StoreID:  n.StoreID,
+		EntityID: n.ID,
+		Action:   notificationActions[n.Op],
+	}
+	switch n.Table {
+	case "categories":
+		change.EntityType = domain.AuditEntityCategory
+	case "products":
+		change.EntityType = domain.AuditEntityProduct
+	case "product_images":
+		change.EntityType = domain.AuditEntityProductImage
+		change.ProductID = n.ProductID
+	case "product_categories":
+		change.EntityType = domain.AuditEntityProduct
+		change.Action = domain.AuditActionUpdate
+	default:
+		return domain.CatalogChange{}, fmt.Errorf("unknown table %q", n.Table)
+	}
+
+	if change.Action == "" {
+		return domain.CatalogChange{}, fmt.Errorf("unknown operation %q", n.Op)
+	}
+	return change, nil
+}
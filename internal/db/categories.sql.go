@@ -12,18 +12,19 @@ import (
 )
 
 const createCategory = `-- name: CreateCategory :one
-INSERT INTO categories (name, slug, created_at, updated_at)
-VALUES ($1, $2, NOW(), NOW())
-RETURNING id, name, slug, created_at, updated_at
+INSERT INTO categories (store_id, name, slug, created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING id, name, slug, created_at, updated_at, store_id
 `
 
 type CreateCategoryParams struct {
-	Name string
-	Slug string
+	StoreID uuid.UUID
+	Name    string
+	Slug    string
 }
 
 func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
-	row := q.db.QueryRow(ctx, createCategory, arg.Name, arg.Slug)
+	row := q.db.QueryRow(ctx, createCategory, arg.StoreID, arg.Name, arg.Slug)
 	var i Category
 	err := row.Scan(
 		&i.ID,
@@ -31,33 +32,77 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		&i.Slug,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StoreID,
 	)
 	return i, err
 }
 
 const deleteCategory = `-- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1
+DELETE FROM categories WHERE store_id = $1 AND id = $2
 `
 
-func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCategory, id)
+type DeleteCategoryParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) error {
+	_, err := q.db.Exec(ctx, deleteCategory, arg.StoreID, arg.ID)
 	return err
 }
 
 const getCategories = `-- name: GetCategories :many
-SELECT id, name, slug, created_at, updated_at
-FROM categories
+SELECT id, name, slug, created_at, updated_at, store_id FROM categories
+WHERE store_id = $1
 ORDER BY name
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type GetCategoriesParams struct {
-	Limit  int32
-	Offset int32
+	StoreID uuid.UUID
+	Limit   int32
+	Offset  int32
 }
 
 func (q *Queries) GetCategories(ctx context.Context, arg GetCategoriesParams) ([]Category, error) {
-	rows, err := q.db.Query(ctx, getCategories, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getCategories, arg.StoreID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategoriesByIDs = `-- name: GetCategoriesByIDs :many
+SELECT id, name, slug, created_at, updated_at, store_id FROM categories
+WHERE store_id = $1 AND id = ANY($2::uuid[])
+`
+
+type GetCategoriesByIDsParams struct {
+	StoreID uuid.UUID
+	Ids     []uuid.UUID
+}
+
+func (q *Queries) GetCategoriesByIDs(ctx context.Context, arg GetCategoriesByIDsParams) ([]Category, error) {
+	rows, err := q.db.Query(ctx, getCategoriesByIDs, arg.StoreID, arg.Ids)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +116,7 @@ func (q *Queries) GetCategories(ctx context.Context, arg GetCategoriesParams) ([
 			&i.Slug,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.StoreID,
 		); err != nil {
 			return nil, err
 		}
@@ -84,23 +130,28 @@ func (q *Queries) GetCategories(ctx context.Context, arg GetCategoriesParams) ([
 
 const getCategoriesCount = `-- name: GetCategoriesCount :one
 SELECT COUNT(*) FROM categories
+WHERE store_id = $1
 `
 
-func (q *Queries) GetCategoriesCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getCategoriesCount)
+func (q *Queries) GetCategoriesCount(ctx context.Context, storeID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getCategoriesCount, storeID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const getCategoryById = `-- name: GetCategoryById :one
-SELECT id, name, slug, created_at, updated_at
-FROM categories
-WHERE id = $1
+SELECT id, name, slug, created_at, updated_at, store_id FROM categories
+WHERE store_id = $1 AND id = $2
 `
 
-func (q *Queries) GetCategoryById(ctx context.Context, id uuid.UUID) (Category, error) {
-	row := q.db.QueryRow(ctx, getCategoryById, id)
+type GetCategoryByIdParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) GetCategoryById(ctx context.Context, arg GetCategoryByIdParams) (Category, error) {
+	row := q.db.QueryRow(ctx, getCategoryById, arg.StoreID, arg.ID)
 	var i Category
 	err := row.Scan(
 		&i.ID,
@@ -108,18 +159,23 @@ func (q *Queries) GetCategoryById(ctx context.Context, id uuid.UUID) (Category,
 		&i.Slug,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StoreID,
 	)
 	return i, err
 }
 
 const getCategoryBySlug = `-- name: GetCategoryBySlug :one
-SELECT id, name, slug, created_at, updated_at
-FROM categories
-WHERE slug = $1
+SELECT id, name, slug, created_at, updated_at, store_id FROM categories
+WHERE store_id = $1 AND slug = $2
 `
 
-func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (Category, error) {
-	row := q.db.QueryRow(ctx, getCategoryBySlug, slug)
+type GetCategoryBySlugParams struct {
+	StoreID uuid.UUID
+	Slug    string
+}
+
+func (q *Queries) GetCategoryBySlug(ctx context.Context, arg GetCategoryBySlugParams) (Category, error) {
+	row := q.db.QueryRow(ctx, getCategoryBySlug, arg.StoreID, arg.Slug)
 	var i Category
 	err := row.Scan(
 		&i.ID,
@@ -127,26 +183,68 @@ func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (Category,
 		&i.Slug,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StoreID,
 	)
 	return i, err
 }
 
+const getCategoryIDsBySlugs = `-- name: GetCategoryIDsBySlugs :many
+SELECT id, slug FROM categories
+WHERE store_id = $1 AND slug = ANY($2::text[])
+`
+
+type GetCategoryIDsBySlugsParams struct {
+	StoreID uuid.UUID
+	Slugs   []string
+}
+
+type GetCategoryIDsBySlugsRow struct {
+	ID   uuid.UUID
+	Slug string
+}
+
+func (q *Queries) GetCategoryIDsBySlugs(ctx context.Context, arg GetCategoryIDsBySlugsParams) ([]GetCategoryIDsBySlugsRow, error) {
+	rows, err := q.db.Query(ctx, getCategoryIDsBySlugs, arg.StoreID, arg.Slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoryIDsBySlugsRow
+	for rows.Next() {
+		var i GetCategoryIDsBySlugsRow
+		if err := rows.Scan(&i.ID, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateCategory = `-- name: UpdateCategory :exec
 UPDATE categories
 SET
-    name = COALESCE($2, name),
-    slug = COALESCE($3, slug),
+    name = COALESCE($3, name),
+    slug = COALESCE($4, slug),
     updated_at = NOW()
-WHERE id = $1
+WHERE store_id = $1 AND id = $2
 `
 
 type UpdateCategoryParams struct {
-	ID   uuid.UUID
-	Name string
-	Slug string
+	StoreID uuid.UUID
+	ID      uuid.UUID
+	Name    string
+	Slug    string
 }
 
 func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) error {
-	_, err := q.db.Exec(ctx, updateCategory, arg.ID, arg.Name, arg.Slug)
+	_, err := q.db.Exec(ctx, updateCategory,
+		arg.StoreID,
+		arg.ID,
+		arg.Name,
+		arg.Slug,
+	)
 	return err
 }
@@ -9,12 +9,74 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Role       string
+	CreatedAt  pgtype.Timestamp
+	LastUsedAt pgtype.Timestamp
+	RevokedAt  pgtype.Timestamp
+	StoreID    pgtype.UUID
+}
+
+type AuditLog struct {
+	ID          int64
+	StoreID     uuid.UUID
+	Actor       string
+	ActorMethod string
+	Action      string
+	EntityType  string
+	EntityID    string
+	Before      []byte
+	After       []byte
+	RequestID   string
+	Ip          string
+	CreatedAt   pgtype.Timestamp
+}
+
 type Category struct {
 	ID        uuid.UUID
 	Name      string
 	Slug      string
 	CreatedAt pgtype.Timestamp
 	UpdatedAt pgtype.Timestamp
+	StoreID   uuid.UUID
+}
+
+type IdempotencyKey struct {
+	Scope        string
+	Key          string
+	RequestHash  string
+	StatusCode   pgtype.Int4
+	ContentType  pgtype.Text
+	ResponseBody []byte
+	CreatedAt    pgtype.Timestamptz
+	ExpiresAt    pgtype.Timestamptz
+	Location     pgtype.Text
+	Claim        pgtype.UUID
+}
+
+type Outbox struct {
+	ID         int64
+	TxID       int64
+	EventID    uuid.UUID
+	StoreID    uuid.UUID
+	EventType  string
+	EntityType string
+	EntityID   string
+	Action     string
+	Data       []byte
+	OccurredAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type OutboxCursor struct {
+	Publisher string
+	TxID      int64
+	LastID    int64
+	UpdatedAt pgtype.Timestamptz
 }
 
 type Product struct {
@@ -25,17 +87,113 @@ type Product struct {
 	Price       float64
 	CreatedAt   pgtype.Timestamp
 	UpdatedAt   pgtype.Timestamp
+	StoreID     uuid.UUID
 }
 
 type ProductCategory struct {
 	ProductID  uuid.UUID
 	CategoryID uuid.UUID
+	StoreID    uuid.UUID
+}
+
+type ProductFeed struct {
+	StoreID     uuid.UUID
+	Fingerprint string
+	Stale       bool
+	Version     int64
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type ProductFeedItem struct {
+	StoreID   uuid.UUID
+	ProductID uuid.UUID
+	Xml       string
+	Tsv       string
 }
 
 type ProductImage struct {
+	ID         uuid.UUID
+	ProductID  uuid.UUID
+	Url        string
+	IsPrimary  pgtype.Bool
+	CreatedAt  pgtype.Timestamp
+	Visibility string
+	Position   int32
+	StoreID    uuid.UUID
+}
+
+type ProductImportJob struct {
+	ID            uuid.UUID
+	StoreID       uuid.UUID
+	Format        string
+	DryRun        bool
+	Status        string
+	TotalRows     int32
+	ProcessedRows int32
+	LastLine      int32
+	CreatedCount  int32
+	UpdatedCount  int32
+	FailedCount   int32
+	Error         pgtype.Text
+	Actor         string
+	ActorMethod   string
+	RequestID     string
+	Ip            string
+	LeaseUntil    pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	StartedAt     pgtype.Timestamptz
+	FinishedAt    pgtype.Timestamptz
+}
+
+type ProductImportRow struct {
+	JobID         uuid.UUID
+	Line          int32
+	Name          string
+	Slug          string
+	Description   string
+	Price         float64
+	CategorySlugs []string
+	ImageUrls     []string
+	Error         pgtype.Text
+}
+
+type RateLimitBucket struct {
+	Key       string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt pgtype.Timestamptz
+}
+
+type Store struct {
 	ID        uuid.UUID
-	ProductID uuid.UUID
+	Slug      string
+	Name      string
+	CreatedAt pgtype.Timestamp
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	StoreID        uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	NextAttemptAt  pgtype.Timestamptz
+	LastStatusCode pgtype.Int4
+	LastError      pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+}
+
+type WebhookSubscription struct {
+	ID        uuid.UUID
+	StoreID   uuid.UUID
 	Url       string
-	IsPrimary pgtype.Bool
+	Events    []string
+	Secret    string
+	Active    bool
 	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
 }
@@ -12,24 +12,112 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addImportedProductImages = `-- name: AddImportedProductImages :many
+INSERT INTO product_images (store_id, product_id, url, is_primary, position)
+SELECT $1, u.product_id, u.url,
+    u.ord = 1 AND NOT EXISTS (
+        SELECT 1 FROM product_images pi
+        WHERE pi.product_id = u.product_id AND pi.is_primary = true
+    ),
+    (
+        SELECT COALESCE(MAX(pi.position) + 1, 0) FROM product_images pi
+        WHERE pi.product_id = u.product_id
+    ) + u.ord - 1
+FROM unnest($2::uuid[], $3::text[], $4::int[]) AS u(product_id, url, ord)
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_images pi
+    WHERE pi.product_id = u.product_id AND pi.url = u.url
+)
+RETURNING id, product_id, url, is_primary, created_at, visibility, position, store_id
+`
+
+type AddImportedProductImagesParams struct {
+	StoreID    uuid.UUID
+	ProductIds []uuid.UUID
+	Urls       []string
+	Ords       []int32
+}
+
+func (q *Queries) AddImportedProductImages(ctx context.Context, arg AddImportedProductImagesParams) ([]ProductImage, error) {
+	rows, err := q.db.Query(ctx, addImportedProductImages,
+		arg.StoreID,
+		arg.ProductIds,
+		arg.Urls,
+		arg.Ords,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductImage
+	for rows.Next() {
+		var i ProductImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Url,
+			&i.IsPrimary,
+			&i.CreatedAt,
+			&i.Visibility,
+			&i.Position,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const clearProductPrimaryImage = `-- name: ClearProductPrimaryImage :exec
+UPDATE product_images
+SET is_primary = false
+WHERE store_id = $1 AND product_id = $2 AND is_primary = true
+`
+
+type ClearProductPrimaryImageParams struct {
+	StoreID   uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) ClearProductPrimaryImage(ctx context.Context, arg ClearProductPrimaryImageParams) error {
+	_, err := q.db.Exec(ctx, clearProductPrimaryImage, arg.StoreID, arg.ProductID)
+	return err
+}
+
 const createProductImage = `-- name: CreateProductImage :one
 INSERT INTO product_images (
+    store_id,
     product_id,
     url,
-    is_primary
+    is_primary,
+    visibility,
+    position
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, product_id, url, is_primary, created_at
+    $1, $2, $3, $4, $5,
+    (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE store_id = $1 AND product_id = $2)
+) RETURNING id, product_id, url, is_primary, created_at, visibility, position, store_id
 `
 
 type CreateProductImageParams struct {
-	ProductID uuid.UUID
-	Url       string
-	IsPrimary pgtype.Bool
+	StoreID    uuid.UUID
+	ProductID  uuid.UUID
+	Url        string
+	IsPrimary  pgtype.Bool
+	Visibility string
 }
 
 func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error) {
-	row := q.db.QueryRow(ctx, createProductImage, arg.ProductID, arg.Url, arg.IsPrimary)
+	row := q.db.QueryRow(ctx, createProductImage,
+		arg.StoreID,
+		arg.ProductID,
+		arg.Url,
+		arg.IsPrimary,
+		arg.Visibility,
+	)
 	var i ProductImage
 	err := row.Scan(
 		&i.ID,
@@ -37,28 +125,88 @@ func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImage
 		&i.Url,
 		&i.IsPrimary,
 		&i.CreatedAt,
+		&i.Visibility,
+		&i.Position,
+		&i.StoreID,
 	)
 	return i, err
 }
 
 const deleteProductImage = `-- name: DeleteProductImage :exec
 DELETE FROM product_images
-WHERE id = $1
+WHERE store_id = $1 AND id = $2
 `
 
-func (q *Queries) DeleteProductImage(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteProductImage, id)
+type DeleteProductImageParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) DeleteProductImage(ctx context.Context, arg DeleteProductImageParams) error {
+	_, err := q.db.Exec(ctx, deleteProductImage, arg.StoreID, arg.ID)
 	return err
 }
 
+const getMediaImageByID = `-- name: GetMediaImageByID :one
+SELECT id, product_id, url, is_primary, created_at, visibility, position, store_id FROM product_images
+WHERE id = $1
+`
+
+func (q *Queries) GetMediaImageByID(ctx context.Context, id uuid.UUID) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, getMediaImageByID, id)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Url,
+		&i.IsPrimary,
+		&i.CreatedAt,
+		&i.Visibility,
+		&i.Position,
+		&i.StoreID,
+	)
+	return i, err
+}
+
+const getProductImageByID = `-- name: GetProductImageByID :one
+SELECT id, product_id, url, is_primary, created_at, visibility, position, store_id FROM product_images
+WHERE store_id = $1 AND id = $2
+`
+
+type GetProductImageByIDParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) GetProductImageByID(ctx context.Context, arg GetProductImageByIDParams) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, getProductImageByID, arg.StoreID, arg.ID)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Url,
+		&i.IsPrimary,
+		&i.CreatedAt,
+		&i.Visibility,
+		&i.Position,
+		&i.StoreID,
+	)
+	return i, err
+}
+
 const getProductImages = `-- name: GetProductImages :many
-SELECT id, product_id, url, is_primary, created_at FROM product_images
-WHERE product_id = $1
-ORDER BY is_primary DESC, created_at ASC
+SELECT id, product_id, url, is_primary, created_at, visibility, position, store_id FROM product_images
+WHERE store_id = $1 AND product_id = $2
+ORDER BY is_primary DESC, position ASC, created_at ASC
 `
 
-func (q *Queries) GetProductImages(ctx context.Context, productID uuid.UUID) ([]ProductImage, error) {
-	rows, err := q.db.Query(ctx, getProductImages, productID)
+type GetProductImagesParams struct {
+	StoreID   uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) GetProductImages(ctx context.Context, arg GetProductImagesParams) ([]ProductImage, error) {
+	rows, err := q.db.Query(ctx, getProductImages, arg.StoreID, arg.ProductID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +220,49 @@ func (q *Queries) GetProductImages(ctx context.Context, productID uuid.UUID) ([]
 			&i.Url,
 			&i.IsPrimary,
 			&i.CreatedAt,
+			&i.Visibility,
+			&i.Position,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductImagesByProductIDs = `-- name: GetProductImagesByProductIDs :many
+SELECT id, product_id, url, is_primary, created_at, visibility, position, store_id FROM product_images
+WHERE store_id = $1 AND product_id = ANY($2::uuid[])
+ORDER BY product_id, is_primary DESC, position ASC, created_at ASC
+`
+
+type GetProductImagesByProductIDsParams struct {
+	StoreID    uuid.UUID
+	ProductIds []uuid.UUID
+}
+
+func (q *Queries) GetProductImagesByProductIDs(ctx context.Context, arg GetProductImagesByProductIDsParams) ([]ProductImage, error) {
+	rows, err := q.db.Query(ctx, getProductImagesByProductIDs, arg.StoreID, arg.ProductIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductImage
+	for rows.Next() {
+		var i ProductImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Url,
+			&i.IsPrimary,
+			&i.CreatedAt,
+			&i.Visibility,
+			&i.Position,
+			&i.StoreID,
 		); err != nil {
 			return nil, err
 		}
@@ -84,13 +275,18 @@ func (q *Queries) GetProductImages(ctx context.Context, productID uuid.UUID) ([]
 }
 
 const getProductPrimaryImage = `-- name: GetProductPrimaryImage :one
-SELECT id, product_id, url, is_primary, created_at FROM product_images
-WHERE product_id = $1 AND is_primary = true
+SELECT id, product_id, url, is_primary, created_at, visibility, position, store_id FROM product_images
+WHERE store_id = $1 AND product_id = $2 AND is_primary = true
 LIMIT 1
 `
 
-func (q *Queries) GetProductPrimaryImage(ctx context.Context, productID uuid.UUID) (ProductImage, error) {
-	row := q.db.QueryRow(ctx, getProductPrimaryImage, productID)
+type GetProductPrimaryImageParams struct {
+	StoreID   uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) GetProductPrimaryImage(ctx context.Context, arg GetProductPrimaryImageParams) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, getProductPrimaryImage, arg.StoreID, arg.ProductID)
 	var i ProductImage
 	err := row.Scan(
 		&i.ID,
@@ -98,22 +294,84 @@ func (q *Queries) GetProductPrimaryImage(ctx context.Context, productID uuid.UUI
 		&i.Url,
 		&i.IsPrimary,
 		&i.CreatedAt,
+		&i.Visibility,
+		&i.Position,
+		&i.StoreID,
 	)
 	return i, err
 }
 
-const setProductPrimaryImage = `-- name: SetProductPrimaryImage :exec
+const getReferencedImageURLs = `-- name: GetReferencedImageURLs :many
+SELECT DISTINCT url FROM product_images
+WHERE url = ANY($1::text[])
+`
+
+func (q *Queries) GetReferencedImageURLs(ctx context.Context, urls []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getReferencedImageURLs, urls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		items = append(items, url)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markProductImagePrimary = `-- name: MarkProductImagePrimary :exec
 UPDATE product_images
-SET is_primary = (id = $2)
-WHERE product_id = $1
+SET is_primary = true
+WHERE store_id = $1 AND id = $2
 `
 
-type SetProductPrimaryImageParams struct {
-	ProductID uuid.UUID
-	ID        uuid.UUID
+type MarkProductImagePrimaryParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) MarkProductImagePrimary(ctx context.Context, arg MarkProductImagePrimaryParams) error {
+	_, err := q.db.Exec(ctx, markProductImagePrimary, arg.StoreID, arg.ID)
+	return err
+}
+
+const updateProductImagePosition = `-- name: UpdateProductImagePosition :exec
+UPDATE product_images
+SET position = $3
+WHERE store_id = $1 AND id = $2
+`
+
+type UpdateProductImagePositionParams struct {
+	StoreID  uuid.UUID
+	ID       uuid.UUID
+	Position int32
+}
+
+func (q *Queries) UpdateProductImagePosition(ctx context.Context, arg UpdateProductImagePositionParams) error {
+	_, err := q.db.Exec(ctx, updateProductImagePosition, arg.StoreID, arg.ID, arg.Position)
+	return err
+}
+
+const updateProductImageVisibility = `-- name: UpdateProductImageVisibility :exec
+UPDATE product_images
+SET visibility = $3
+WHERE store_id = $1 AND id = $2
+`
+
+type UpdateProductImageVisibilityParams struct {
+	StoreID    uuid.UUID
+	ID         uuid.UUID
+	Visibility string
 }
 
-func (q *Queries) SetProductPrimaryImage(ctx context.Context, arg SetProductPrimaryImageParams) error {
-	_, err := q.db.Exec(ctx, setProductPrimaryImage, arg.ProductID, arg.ID)
+func (q *Queries) UpdateProductImageVisibility(ctx context.Context, arg UpdateProductImageVisibilityParams) error {
+	_, err := q.db.Exec(ctx, updateProductImageVisibility, arg.StoreID, arg.ID, arg.Visibility)
 	return err
 }
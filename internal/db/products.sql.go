@@ -12,38 +12,83 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const addProductCategory = `-- name: AddProductCategory :exec
-INSERT INTO product_categories (product_id, category_id)
-VALUES ($1, $2)
+const addProductCategories = `-- name: AddProductCategories :exec
+INSERT INTO product_categories (store_id, product_id, category_id)
+SELECT $1, u.product_id, u.category_id
+FROM unnest($2::uuid[], $3::uuid[]) AS u(product_id, category_id)
+ON CONFLICT DO NOTHING
+`
+
+type AddProductCategoriesParams struct {
+	StoreID     uuid.UUID
+	ProductIds  []uuid.UUID
+	CategoryIds []uuid.UUID
+}
+
+func (q *Queries) AddProductCategories(ctx context.Context, arg AddProductCategoriesParams) error {
+	_, err := q.db.Exec(ctx, addProductCategories, arg.StoreID, arg.ProductIds, arg.CategoryIds)
+	return err
+}
+
+const addProductCategory = `-- name: AddProductCategory :execrows
+INSERT INTO product_categories (store_id, product_id, category_id)
+SELECT $1, $2, c.id
+FROM categories c
+WHERE c.store_id = $1 AND c.id = $3
 `
 
 type AddProductCategoryParams struct {
+	StoreID    uuid.UUID
 	ProductID  uuid.UUID
 	CategoryID uuid.UUID
 }
 
-func (q *Queries) AddProductCategory(ctx context.Context, arg AddProductCategoryParams) error {
-	_, err := q.db.Exec(ctx, addProductCategory, arg.ProductID, arg.CategoryID)
-	return err
+func (q *Queries) AddProductCategory(ctx context.Context, arg AddProductCategoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addProductCategory, arg.StoreID, arg.ProductID, arg.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const clearProductCategories = `-- name: ClearProductCategories :exec
 DELETE FROM product_categories
-WHERE product_id = $1
+WHERE store_id = $1 AND product_id = $2
+`
+
+type ClearProductCategoriesParams struct {
+	StoreID   uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) ClearProductCategories(ctx context.Context, arg ClearProductCategoriesParams) error {
+	_, err := q.db.Exec(ctx, clearProductCategories, arg.StoreID, arg.ProductID)
+	return err
+}
+
+const clearProductCategoriesByProductIDs = `-- name: ClearProductCategoriesByProductIDs :exec
+DELETE FROM product_categories
+WHERE store_id = $1 AND product_id = ANY($2::uuid[])
 `
 
-func (q *Queries) ClearProductCategories(ctx context.Context, productID uuid.UUID) error {
-	_, err := q.db.Exec(ctx, clearProductCategories, productID)
+type ClearProductCategoriesByProductIDsParams struct {
+	StoreID    uuid.UUID
+	ProductIds []uuid.UUID
+}
+
+func (q *Queries) ClearProductCategoriesByProductIDs(ctx context.Context, arg ClearProductCategoriesByProductIDsParams) error {
+	_, err := q.db.Exec(ctx, clearProductCategoriesByProductIDs, arg.StoreID, arg.ProductIds)
 	return err
 }
 
 const createProduct = `-- name: CreateProduct :one
-INSERT INTO products(name, slug, description, price, created_at, updated_at)
-VALUES ($1, $2, $3, $4, NOW(), NOW())
-RETURNING id, name, slug, description, price, created_at, updated_at
+INSERT INTO products(store_id, name, slug, description, price, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING id, name, slug, description, price, created_at, updated_at, store_id
 `
 
 type CreateProductParams struct {
+	StoreID     uuid.UUID
 	Name        string
 	Slug        string
 	Description string
@@ -52,6 +97,7 @@ type CreateProductParams struct {
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
 	row := q.db.QueryRow(ctx, createProduct,
+		arg.StoreID,
 		arg.Name,
 		arg.Slug,
 		arg.Description,
@@ -66,17 +112,23 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.Price,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StoreID,
 	)
 	return i, err
 }
 
 const deleteProduct = `-- name: DeleteProduct :exec
 DELETE FROM products
-WHERE id = $1
+WHERE store_id = $1 AND id = $2
 `
 
-func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteProduct, id)
+type DeleteProductParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) error {
+	_, err := q.db.Exec(ctx, deleteProduct, arg.StoreID, arg.ID)
 	return err
 }
 
@@ -90,6 +142,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc
@@ -98,29 +152,33 @@ SELECT
     )::json as categories
 FROM products p
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = $1
 ORDER BY p.created_at DESC
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type GetAllProductsParams struct {
-	Limit  int32
-	Offset int32
+	StoreID uuid.UUID
+	Limit   int32
+	Offset  int32
 }
 
 type GetAllProductsRow struct {
-	ID              uuid.UUID
-	Name            string
-	Slug            string
-	Description     string
-	Price           float64
-	CreatedAt       pgtype.Timestamp
-	UpdatedAt       pgtype.Timestamp
-	PrimaryImageUrl pgtype.Text
-	Categories      []byte
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
 }
 
 func (q *Queries) GetAllProducts(ctx context.Context, arg GetAllProductsParams) ([]GetAllProductsRow, error) {
-	rows, err := q.db.Query(ctx, getAllProducts, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getAllProducts, arg.StoreID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +195,8 @@ func (q *Queries) GetAllProducts(ctx context.Context, arg GetAllProductsParams)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PrimaryImageUrl,
+			&i.PrimaryImageID,
+			&i.PrimaryImageVisibility,
 			&i.Categories,
 		); err != nil {
 			return nil, err
@@ -159,6 +219,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc
@@ -167,23 +229,30 @@ SELECT
     )::json as categories
 FROM products p
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
-WHERE p.id = $1
+WHERE p.store_id = $1 AND p.id = $2
 `
 
+type GetProductByIDParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
 type GetProductByIDRow struct {
-	ID              uuid.UUID
-	Name            string
-	Slug            string
-	Description     string
-	Price           float64
-	CreatedAt       pgtype.Timestamp
-	UpdatedAt       pgtype.Timestamp
-	PrimaryImageUrl pgtype.Text
-	Categories      []byte
-}
-
-func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductByIDRow, error) {
-	row := q.db.QueryRow(ctx, getProductByID, id)
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
+}
+
+func (q *Queries) GetProductByID(ctx context.Context, arg GetProductByIDParams) (GetProductByIDRow, error) {
+	row := q.db.QueryRow(ctx, getProductByID, arg.StoreID, arg.ID)
 	var i GetProductByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -194,11 +263,98 @@ func (q *Queries) GetProductByID(ctx context.Context, id uuid.UUID) (GetProductB
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PrimaryImageUrl,
+		&i.PrimaryImageID,
+		&i.PrimaryImageVisibility,
 		&i.Categories,
 	)
 	return i, err
 }
 
+const getProductsAfter = `-- name: GetProductsAfter :many
+SELECT 
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc
+        JOIN categories c ON c.id = pc.category_id
+        WHERE pc.product_id = p.id
+    )::json as categories
+FROM products p
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = $1
+    AND ($2::timestamp IS NULL
+        OR (p.created_at, p.id) < ($2, $3::uuid))
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $4
+`
+
+type GetProductsAfterParams struct {
+	StoreID        uuid.UUID
+	AfterCreatedAt pgtype.Timestamp
+	AfterID        pgtype.UUID
+	RowLimit       int32
+}
+
+type GetProductsAfterRow struct {
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
+}
+
+func (q *Queries) GetProductsAfter(ctx context.Context, arg GetProductsAfterParams) ([]GetProductsAfterRow, error) {
+	rows, err := q.db.Query(ctx, getProductsAfter,
+		arg.StoreID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductsAfterRow
+	for rows.Next() {
+		var i GetProductsAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PrimaryImageUrl,
+			&i.PrimaryImageID,
+			&i.PrimaryImageVisibility,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProductsByCategoryID = `-- name: GetProductsByCategoryID :many
 SELECT 
     p.id,
@@ -209,6 +365,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc_all
@@ -218,23 +376,30 @@ SELECT
 FROM products p
 JOIN product_categories pc ON p.id = pc.product_id
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
-WHERE pc.category_id = $1
+WHERE p.store_id = $1 AND pc.category_id = $2
 `
 
+type GetProductsByCategoryIDParams struct {
+	StoreID    uuid.UUID
+	CategoryID uuid.UUID
+}
+
 type GetProductsByCategoryIDRow struct {
-	ID              uuid.UUID
-	Name            string
-	Slug            string
-	Description     string
-	Price           float64
-	CreatedAt       pgtype.Timestamp
-	UpdatedAt       pgtype.Timestamp
-	PrimaryImageUrl pgtype.Text
-	Categories      []byte
-}
-
-func (q *Queries) GetProductsByCategoryID(ctx context.Context, categoryID uuid.UUID) ([]GetProductsByCategoryIDRow, error) {
-	rows, err := q.db.Query(ctx, getProductsByCategoryID, categoryID)
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
+}
+
+func (q *Queries) GetProductsByCategoryID(ctx context.Context, arg GetProductsByCategoryIDParams) ([]GetProductsByCategoryIDRow, error) {
+	rows, err := q.db.Query(ctx, getProductsByCategoryID, arg.StoreID, arg.CategoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -251,6 +416,171 @@ func (q *Queries) GetProductsByCategoryID(ctx context.Context, categoryID uuid.U
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PrimaryImageUrl,
+			&i.PrimaryImageID,
+			&i.PrimaryImageVisibility,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductsByCategoryIDs = `-- name: GetProductsByCategoryIDs :many
+WITH ranked AS (
+    SELECT
+        pc.category_id,
+        pc.product_id,
+        row_number() OVER (PARTITION BY pc.category_id ORDER BY p.created_at DESC, p.id) AS position
+    FROM products p
+    JOIN product_categories pc ON p.id = pc.product_id
+    WHERE p.store_id = $1 AND pc.category_id = ANY($2::uuid[])
+)
+SELECT 
+    ranked.category_id,
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc_all
+        JOIN categories c ON c.id = pc_all.category_id
+        WHERE pc_all.product_id = p.id
+    )::json as categories
+FROM ranked
+JOIN products p ON p.id = ranked.product_id
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE ranked.position <= $3::int
+ORDER BY ranked.category_id, ranked.position
+`
+
+type GetProductsByCategoryIDsParams struct {
+	StoreID     uuid.UUID
+	CategoryIds []uuid.UUID
+	PerCategory int32
+}
+
+type GetProductsByCategoryIDsRow struct {
+	CategoryID             uuid.UUID
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
+}
+
+func (q *Queries) GetProductsByCategoryIDs(ctx context.Context, arg GetProductsByCategoryIDsParams) ([]GetProductsByCategoryIDsRow, error) {
+	rows, err := q.db.Query(ctx, getProductsByCategoryIDs, arg.StoreID, arg.CategoryIds, arg.PerCategory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductsByCategoryIDsRow
+	for rows.Next() {
+		var i GetProductsByCategoryIDsRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PrimaryImageUrl,
+			&i.PrimaryImageID,
+			&i.PrimaryImageVisibility,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductsBySlugs = `-- name: GetProductsBySlugs :many
+SELECT 
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc
+        JOIN categories c ON c.id = pc.category_id
+        WHERE pc.product_id = p.id
+    )::json as categories
+FROM products p
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = $1 AND p.slug = ANY($2::text[])
+`
+
+type GetProductsBySlugsParams struct {
+	StoreID uuid.UUID
+	Slugs   []string
+}
+
+type GetProductsBySlugsRow struct {
+	ID                     uuid.UUID
+	Name                   string
+	Slug                   string
+	Description            string
+	Price                  float64
+	CreatedAt              pgtype.Timestamp
+	UpdatedAt              pgtype.Timestamp
+	PrimaryImageUrl        pgtype.Text
+	PrimaryImageID         pgtype.UUID
+	PrimaryImageVisibility pgtype.Text
+	Categories             []byte
+}
+
+func (q *Queries) GetProductsBySlugs(ctx context.Context, arg GetProductsBySlugsParams) ([]GetProductsBySlugsRow, error) {
+	rows, err := q.db.Query(ctx, getProductsBySlugs, arg.StoreID, arg.Slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductsBySlugsRow
+	for rows.Next() {
+		var i GetProductsBySlugsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PrimaryImageUrl,
+			&i.PrimaryImageID,
+			&i.PrimaryImageVisibility,
 			&i.Categories,
 		); err != nil {
 			return nil, err
@@ -265,26 +595,46 @@ func (q *Queries) GetProductsByCategoryID(ctx context.Context, categoryID uuid.U
 
 const getProductsCount = `-- name: GetProductsCount :one
 SELECT COUNT(*) FROM products
+WHERE store_id = $1
 `
 
-func (q *Queries) GetProductsCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getProductsCount)
+func (q *Queries) GetProductsCount(ctx context.Context, storeID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getProductsCount, storeID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const lockProduct = `-- name: LockProduct :one
+SELECT id FROM products
+WHERE store_id = $1 AND id = $2
+FOR UPDATE
+`
+
+type LockProductParams struct {
+	StoreID uuid.UUID
+	ID      uuid.UUID
+}
+
+func (q *Queries) LockProduct(ctx context.Context, arg LockProductParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, lockProduct, arg.StoreID, arg.ID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateProduct = `-- name: UpdateProduct :exec
 UPDATE products
 SET 
-    name = COALESCE($2, name),
-    description = COALESCE($3, description),
-    price = COALESCE($4, price),
+    name = COALESCE($3, name),
+    description = COALESCE($4, description),
+    price = COALESCE($5, price),
     updated_at = NOW()
-WHERE id = $1
+WHERE store_id = $1 AND id = $2
 `
 
 type UpdateProductParams struct {
+	StoreID     uuid.UUID
 	ID          uuid.UUID
 	Name        string
 	Description string
@@ -293,6 +643,7 @@ type UpdateProductParams struct {
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) error {
 	_, err := q.db.Exec(ctx, updateProduct,
+		arg.StoreID,
 		arg.ID,
 		arg.Name,
 		arg.Description,
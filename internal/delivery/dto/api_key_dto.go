@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"product-listing/internal/domain"
+	"time"
+)
+
+type APIKeyReq struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+	// Store is the slug of the store the key is bound to, empty for any store
+	Store string `json:"store"`
+}
+
+type APIKeyResp struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	StoreID    *string    `json:"store_id"`
+}
+
+// APIKeyCreatedResp carries the plain key, which is only ever returned once.
+type APIKeyCreatedResp struct {
+	APIKeyResp
+	Key string `json:"key"`
+}
+
+func ToAPIKeyDTO(k *domain.APIKey) APIKeyResp {
+	resp := APIKeyResp{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Role:       string(k.Role),
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+	if k.StoreID != nil {
+		storeID := k.StoreID.String()
+		resp.StoreID = &storeID
+	}
+	return resp
+}
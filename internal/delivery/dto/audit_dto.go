@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"product-listing/internal/domain"
+	"time"
+)
+
+type AuditEntryResp struct {
+	ID          int64           `json:"id"`
+	StoreID     string          `json:"store_id"`
+	Actor       string          `json:"actor"`
+	ActorMethod string          `json:"actor_method"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Before      json.RawMessage `json:"before"`
+	After       json.RawMessage `json:"after"`
+	RequestID   string          `json:"request_id"`
+	IP          string          `json:"ip"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func ToAuditEntryDTO(e *domain.AuditEntry) AuditEntryResp {
+	resp := AuditEntryResp{
+		ID:          e.ID,
+		StoreID:     e.StoreID.String(),
+		Actor:       e.Actor,
+		ActorMethod: e.ActorMethod,
+		Action:      e.Action,
+		EntityType:  e.EntityType,
+		EntityID:    e.EntityID,
+		Before:      e.Before,
+		After:       e.After,
+		RequestID:   e.RequestID,
+		IP:          e.IP,
+		CreatedAt:   e.CreatedAt,
+	}
+	// Keep absent snapshots as JSON null rather than dropping the field
+	if resp.Before == nil {
+		resp.Before = json.RawMessage("null")
+	}
+	if resp.After == nil {
+		resp.After = json.RawMessage("null")
+	}
+	return resp
+}
@@ -0,0 +1,9 @@
+package dto
+
+// GraphQLReq is a GraphQL request as sent over HTTP. OperationName keeps the
+// camelCase key that GraphQL clients send.
+type GraphQLReq struct {
+	Query         string         `json:"query" binding:"required"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
@@ -6,25 +6,57 @@ import (
 )
 
 type ProductImageReq struct {
-	ProductID string `json:"product_id"`
-	Url       string `json:"url"`
-	IsPrimary bool   `json:"is_primary"`
+	ProductID  string `json:"product_id"`
+	Url        string `json:"url"`
+	IsPrimary  bool   `json:"is_primary"`
+	Visibility string `json:"visibility"`
+}
+
+type ProductImageVisibilityReq struct {
+	Visibility string `json:"visibility"`
+}
+
+type ImageOperationReq struct {
+	Op         string   `json:"op"`
+	ProductID  string   `json:"product_id"`
+	ImageID    string   `json:"image_id"`
+	ImageIDs   []string `json:"image_ids"`
+	Url        string   `json:"url"`
+	IsPrimary  bool     `json:"is_primary"`
+	Visibility string   `json:"visibility"`
+}
+
+type ImageBatchReq struct {
+	Operations []ImageOperationReq `json:"operations"`
+}
+
+type ImageOperationResp struct {
+	Index   int               `json:"index"`
+	Op      string            `json:"op"`
+	Status  int               `json:"status"`
+	Code    string            `json:"code,omitempty"`
+	Message string            `json:"message"`
+	Data    *ProductImageResp `json:"data,omitempty"`
 }
 
 type ProductImageResp struct {
-	ID        string    `json:"id"`
-	ProductID string    `json:"product_id"`
-	Url       string    `json:"url"`
-	IsPrimary bool      `json:"is_primary"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         string    `json:"id"`
+	ProductID  string    `json:"product_id"`
+	Url        string    `json:"url"`
+	IsPrimary  bool      `json:"is_primary"`
+	Visibility string    `json:"visibility"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 func ToProductImageDTO(img *domain.ProductImage) ProductImageResp {
 	return ProductImageResp{
-		ID:        img.ID.String(),
-		ProductID: img.ProductID.String(),
-		Url:       img.Url,
-		IsPrimary: img.IsPrimary,
-		CreatedAt: img.CreatedAt,
+		ID:         img.ID.String(),
+		ProductID:  img.ProductID.String(),
+		Url:        img.Url,
+		IsPrimary:  img.IsPrimary,
+		Visibility: img.Visibility,
+		Position:   img.Position,
+		CreatedAt:  img.CreatedAt,
 	}
 }
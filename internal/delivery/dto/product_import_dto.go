@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"product-listing/internal/domain"
+	"time"
+)
+
+type ImportJobResp struct {
+	ID            string     `json:"id"`
+	Format        string     `json:"format"`
+	DryRun        bool       `json:"dry_run"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	CreatedCount  int        `json:"created_count"`
+	UpdatedCount  int        `json:"updated_count"`
+	FailedCount   int        `json:"failed_count"`
+	Error         string     `json:"error,omitempty"`
+	Actor         string     `json:"actor"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+}
+
+// ImportRowErrorResp is a rejected row of an import file.
+type ImportRowErrorResp struct {
+	Line  int    `json:"line"`
+	Slug  string `json:"slug"`
+	Error string `json:"error"`
+}
+
+func ToImportJobDTO(j *domain.ImportJob) ImportJobResp {
+	return ImportJobResp{
+		ID:            j.ID.String(),
+		Format:        j.Format,
+		DryRun:        j.DryRun,
+		Status:        j.Status,
+		TotalRows:     j.TotalRows,
+		ProcessedRows: j.ProcessedRows,
+		CreatedCount:  j.CreatedCount,
+		UpdatedCount:  j.UpdatedCount,
+		FailedCount:   j.FailedCount,
+		Error:         j.Error,
+		Actor:         j.Actor,
+		CreatedAt:     j.CreatedAt,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+	}
+}
+
+func ToImportRowErrorDTO(r *domain.ImportRow) ImportRowErrorResp {
+	return ImportRowErrorResp{
+		Line:  r.Line,
+		Slug:  r.Slug,
+		Error: r.Error,
+	}
+}
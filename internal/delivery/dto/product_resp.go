@@ -6,15 +6,16 @@ import (
 )
 
 type ProductResp struct {
-	ID              string         `json:"id"`
-	Name            string         `json:"name"`
-	Slug            string         `json:"slug"`
-	Description     string         `json:"Description"`
-	Price           float64        `json:"price"`
-	PrimaryImageURL string         `json:"primary_image_url"`
-	Categories      []CategoryResp `json:"categories"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Slug            string             `json:"slug"`
+	Description     string             `json:"Description"`
+	Price           float64            `json:"price"`
+	PrimaryImageURL string             `json:"primary_image_url"`
+	Categories      []CategoryResp     `json:"categories"`
+	Images          []ProductImageResp `json:"images,omitzero"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
 }
 
 type ProductReq struct {
@@ -33,6 +34,14 @@ func ToProductDTO(p *domain.Product) ProductResp {
 
 	}
 
+	var images []ProductImageResp
+	if p.Images != nil {
+		images = make([]ProductImageResp, 0, len(p.Images))
+		for _, img := range p.Images {
+			images = append(images, ToProductImageDTO(&img))
+		}
+	}
+
 	return ProductResp{
 		ID:              p.ID.String(),
 		Name:            p.Name,
@@ -41,6 +50,7 @@ func ToProductDTO(p *domain.Product) ProductResp {
 		Price:           p.Price,
 		PrimaryImageURL: p.PrimaryImageURL,
 		Categories:      categories,
+		Images:          images,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 	}
@@ -16,6 +16,15 @@ type PaginatedResponse struct {
 	TotalPages int    `json:"total_pages"`
 }
 
+// CursorResponse is a page of a cursor paginated list. NextCursor is passed
+// back as the cursor query parameter and is empty on the last page.
+type CursorResponse struct {
+	Status     int    `json:"status"`
+	Message    string `json:"message"`
+	Data       any    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+}
+
 type SuccessResp struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
@@ -24,4 +33,5 @@ type SuccessResp struct {
 type ErrorResp struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
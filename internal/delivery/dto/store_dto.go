@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"product-listing/internal/domain"
+	"time"
+)
+
+type StoreReq struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type StoreResp struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToStoreDTO(s *domain.Store) StoreResp {
+	return StoreResp{
+		ID:        s.ID.String(),
+		Slug:      s.Slug,
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt,
+	}
+}
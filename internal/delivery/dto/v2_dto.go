@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"product-listing/internal/domain"
+	"time"
+)
+
+// The v2 API uses snake_case keys throughout. Requests and responses whose v1
+// shape already did are shared between both versions.
+
+type CategoryRespV2 struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ProductRespV2 struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Slug            string             `json:"slug"`
+	Description     string             `json:"description"`
+	Price           float64            `json:"price"`
+	PrimaryImageURL string             `json:"primary_image_url"`
+	Categories      []CategoryRespV2   `json:"categories"`
+	Images          []ProductImageResp `json:"images,omitzero"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type ProductReqV2 struct {
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	Description string   `json:"description"`
+	CategoryIDs []string `json:"category_ids"`
+	Price       float64  `json:"price"`
+}
+
+// ProductImageReqV2 adds an image to the product named in the path.
+type ProductImageReqV2 struct {
+	Url        string `json:"url"`
+	IsPrimary  bool   `json:"is_primary"`
+	Visibility string `json:"visibility"`
+}
+
+func ToCategoryV2DTO(c *domain.Category) CategoryRespV2 {
+	return CategoryRespV2{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		Slug:      c.Slug,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func ToProductV2DTO(p *domain.Product) ProductRespV2 {
+	categories := make([]CategoryRespV2, 0, len(p.Categories))
+	for _, c := range p.Categories {
+		categories = append(categories, ToCategoryV2DTO(&c))
+	}
+
+	var images []ProductImageResp
+	if p.Images != nil {
+		images = make([]ProductImageResp, 0, len(p.Images))
+		for _, img := range p.Images {
+			images = append(images, ToProductImageDTO(&img))
+		}
+	}
+
+	return ProductRespV2{
+		ID:              p.ID.String(),
+		Name:            p.Name,
+		Slug:            p.Slug,
+		Description:     p.Description,
+		Price:           p.Price,
+		PrimaryImageURL: p.PrimaryImageURL,
+		Categories:      categories,
+		Images:          images,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"encoding/json"
+	"product-listing/internal/domain"
+	"time"
+)
+
+// WebhookSubscriptionReq creates or replaces a subscription. Active defaults
+// to true and an empty secret is generated on create and kept on update.
+type WebhookSubscriptionReq struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookSubscriptionResp never includes the secret.
+type WebhookSubscriptionResp struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookSubscriptionCreatedResp is returned once, on create, with the secret
+// receivers verify signatures with.
+type WebhookSubscriptionCreatedResp struct {
+	WebhookSubscriptionResp
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResp struct {
+	ID             int64           `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+func ToWebhookSubscriptionDTO(s *domain.WebhookSubscription) WebhookSubscriptionResp {
+	return WebhookSubscriptionResp{
+		ID:        s.ID.String(),
+		URL:       s.URL,
+		Events:    s.Events,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func ToWebhookDeliveryDTO(d *domain.WebhookDelivery) WebhookDeliveryResp {
+	resp := WebhookDeliveryResp{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID.String(),
+		EventID:        d.EventID.String(),
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	// Only a pending delivery is waiting for its next attempt
+	if d.Status == domain.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
@@ -0,0 +1,48 @@
+package gql
+
+import (
+	"errors"
+	"product-listing/internal/domain"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/op/go-logging"
+)
+
+var gqlLog = logging.MustGetLogger("graphql")
+
+// fieldError carries a stable code in the extensions of a GraphQL error, the
+// same code a REST error response would have.
+type fieldError struct {
+	message string
+	code    string
+}
+
+func (e *fieldError) Error() string {
+	return e.message
+}
+
+func (e *fieldError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+var _ gqlerrors.ExtendedError = (*fieldError)(nil)
+
+// resolveError keeps the message and code of business rule violations and
+// hides anything else behind an internal error.
+func resolveError(err error) error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return &fieldError{message: domainErr.Message, code: domainErr.Code}
+	}
+
+	gqlLog.Errorf("Resolving field failed: %v", err)
+	return &fieldError{message: "internal error", code: "internal"}
+}
+
+// requestError is an error that stops a request before it is executed.
+func requestError(message, code string) gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    message,
+		Extensions: map[string]any{"code": code},
+	}
+}
@@ -0,0 +1,218 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listMultiplier is the number of items a list without a limit argument is
+// assumed to return when estimating complexity.
+const listMultiplier = 10
+
+// limits measures the depth and complexity of an operation before it runs.
+// A field costs 1 plus the cost of its selections times the number of items
+// it is expected to return: its limit argument when it has one, otherwise
+// listMultiplier for lists. The items of a page are sized by the limit of the
+// field returning the page, so they count once. Introspection fields are free.
+type limits struct {
+	maxDepth      int
+	maxComplexity int
+	fragments     map[string]*ast.FragmentDefinition
+	variables     map[string]any
+	schema        *graphql.Schema
+}
+
+func checkLimits(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]any, maxDepth, maxComplexity int) error {
+	l := &limits{
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+		fragments:     map[string]*ast.FragmentDefinition{},
+		variables:     variables,
+		schema:        schema,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			l.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operation != nil && operationName == "" {
+				continue
+			}
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	if root == nil {
+		return nil
+	}
+
+	cost, err := l.selectionCost(root, operation.SelectionSet, 1, false)
+	if err != nil {
+		return err
+	}
+	if cost > maxComplexity {
+		return errQueryTooComplex(maxComplexity)
+	}
+	return nil
+}
+
+func errQueryTooDeep(limit int) error {
+	return requestError(fmt.Sprintf("Query is nested deeper than %d levels", limit), "query_too_deep")
+}
+
+func errQueryTooComplex(limit int) error {
+	return requestError(fmt.Sprintf("Query complexity exceeds %d", limit), "query_too_complex")
+}
+
+func (l *limits) selectionCost(parent graphql.Type, set *ast.SelectionSet, depth int, sized bool) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+
+	total := 0
+	for _, selection := range set.Selections {
+		var cost int
+		var err error
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			cost, err = l.fieldCost(parent, s, depth, sized)
+		case *ast.InlineFragment:
+			cost, err = l.selectionCost(l.conditionType(parent, s.TypeCondition), s.SelectionSet, depth, sized)
+		case *ast.FragmentSpread:
+			fragment, ok := l.fragments[s.Name.Value]
+			if !ok {
+				continue
+			}
+			cost, err = l.selectionCost(l.conditionType(parent, fragment.TypeCondition), fragment.SelectionSet, depth, sized)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		total = l.add(total, cost)
+	}
+	return total, nil
+}
+
+func (l *limits) fieldCost(parent graphql.Type, f *ast.Field, depth int, sized bool) (int, error) {
+	if strings.HasPrefix(f.Name.Value, "__") {
+		return 0, nil
+	}
+	if depth > l.maxDepth {
+		return 0, errQueryTooDeep(l.maxDepth)
+	}
+
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return 1, nil
+	}
+	definition, ok := object.Fields()[f.Name.Value]
+	if !ok {
+		return 1, nil
+	}
+
+	multiplier := 1
+	limited := false
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			multiplier = l.intArg(f, arg)
+			limited = true
+		}
+	}
+	if !limited && !(sized && isPageItems(object, f)) && isList(definition.Type) {
+		multiplier = listMultiplier
+	}
+
+	child, _ := graphql.GetNamed(definition.Type).(graphql.Type)
+	childCost, err := l.selectionCost(child, f.SelectionSet, depth+1, limited)
+	if err != nil {
+		return 0, err
+	}
+
+	return l.add(1, l.mul(multiplier, childCost)), nil
+}
+
+// intArg is the value of an integer argument, whether inline, passed as a
+// variable or left to its default.
+func (l *limits) intArg(f *ast.Field, arg *graphql.Argument) int {
+	var value any = arg.DefaultValue
+	for _, a := range f.Arguments {
+		if a.Name.Value != arg.Name() {
+			continue
+		}
+
+		switch v := a.Value.(type) {
+		case *ast.IntValue:
+			value, _ = strconv.Atoi(v.Value)
+		case *ast.Variable:
+			if given, ok := l.variables[v.Name.Value]; ok {
+				value = given
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case int:
+		return max(v, 1)
+	case float64:
+		return max(int(v), 1)
+	case json.Number:
+		n, _ := v.Int64()
+		return max(int(n), 1)
+	}
+	return 1
+}
+
+func (l *limits) conditionType(parent graphql.Type, condition *ast.Named) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	if t := l.schema.Type(condition.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+// add and mul saturate just above the limit so that huge estimates do not
+// overflow.
+func (l *limits) add(a, b int) int {
+	return min(a+b, l.maxComplexity+1)
+}
+
+func (l *limits) mul(a, b int) int {
+	if a != 0 && b > (l.maxComplexity+1)/a {
+		return l.maxComplexity + 1
+	}
+	return min(a*b, l.maxComplexity+1)
+}
+
+// isPageItems reports whether f is the items field of a ProductPage or
+// CategoryPage.
+func isPageItems(object *graphql.Object, f *ast.Field) bool {
+	return f.Name.Value == "items" && strings.HasSuffix(object.Name(), "Page")
+}
+
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
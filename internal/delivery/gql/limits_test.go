@@ -0,0 +1,74 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func testSchema(t *testing.T) *graphql.Schema {
+	t.Helper()
+	schema, err := newSchema(nil, nil)
+	if err != nil {
+		t.Fatalf("newSchema: %v", err)
+	}
+	return &schema
+}
+
+// checkCost asserts that query costs exactly want: it passes at that
+// complexity and fails one below.
+func checkCost(t *testing.T, schema *graphql.Schema, query string, variables map[string]any, want int) {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := checkLimits(schema, doc, "", variables, 10, want); err != nil {
+		t.Errorf("cost of %s exceeds %d: %v", query, want, err)
+	}
+	if err := checkLimits(schema, doc, "", variables, 10, want-1); err == nil {
+		t.Errorf("cost of %s is below %d", query, want)
+	}
+}
+
+func TestCheckLimitsCost(t *testing.T) {
+	schema := testSchema(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      int
+	}{
+		// products 1 + 5 × (items 1 + name 1 + total 1)
+		{"page items count once", `{ products(limit: 5) { items { name } total } }`, nil, 16},
+		{"limit from a variable", `query($n: Int) { products(limit: $n) { items { name } } }`, map[string]any{"n": 7}, 15},
+		// product 1 + categories (1 + 10 × name 1)
+		{"unlimited list", `{ product(id: "1") { categories { name } } }`, nil, 12},
+		// categories 1 + 2 × (items 1 + products (1 + 3 × (categories 1 + 10 × name 1)))
+		{"lists inside a limited list", `{ categories(limit: 2) { items { products(limit: 3) { categories { name } } } } }`, nil, 71},
+		{"fragments", `{ products(limit: 2) { items { ...f } } } fragment f on Product { images { url } }`, nil, 25},
+		{"introspection", `{ __typename products(limit: 1) { total } }`, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkCost(t, schema, tt.query, tt.variables, tt.want)
+		})
+	}
+}
+
+func TestCheckLimitsDepth(t *testing.T) {
+	schema := testSchema(t)
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ products { items { categories { name } } } }`})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if err := checkLimits(schema, doc, "", nil, 4, 10000); err != nil {
+		t.Errorf("depth 4 rejected at max depth 4: %v", err)
+	}
+	if err := checkLimits(schema, doc, "", nil, 3, 10000); err == nil {
+		t.Error("depth 4 accepted at max depth 3")
+	}
+}
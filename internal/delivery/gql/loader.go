@@ -0,0 +1,170 @@
+package gql
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// loader batches the keys asked for while one level of a query resolves and
+// fetches all of them with a single call once the first result is needed.
+// The executor completes a level before it resolves the thunks of the next,
+// so a list of N products costs one fetch per relation, not N. Results are
+// kept for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		queued:  make(map[K]bool),
+		results: make(map[K]V),
+		errs:    make(map[K]error),
+	}
+}
+
+// load queues key and returns a thunk that yields its value. A key without a
+// value yields the zero value.
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	l.enqueue(key)
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.dispatch(ctx)
+		return l.results[key], l.errs[key]
+	}
+}
+
+// loadMany is load for several keys, keeping their order.
+func (l *loader[K, V]) loadMany(ctx context.Context, keys []K) func() ([]V, error) {
+	l.mu.Lock()
+	for _, key := range keys {
+		l.enqueue(key)
+	}
+	l.mu.Unlock()
+
+	return func() ([]V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.dispatch(ctx)
+		values := make([]V, 0, len(keys))
+		for _, key := range keys {
+			if err := l.errs[key]; err != nil {
+				return nil, err
+			}
+			values = append(values, l.results[key])
+		}
+		return values, nil
+	}
+}
+
+func (l *loader[K, V]) enqueue(key K) {
+	if _, done := l.results[key]; done {
+		return
+	}
+	if _, failed := l.errs[key]; failed || l.queued[key] {
+		return
+	}
+	l.queued[key] = true
+	l.pending = append(l.pending, key)
+}
+
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	keys := l.pending
+	l.pending = nil
+	clear(l.queued)
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.results[key] = values[key]
+	}
+}
+
+// loaders holds the loaders of one request.
+type loaders struct {
+	categories       *loader[uuid.UUID, *domain.Category]
+	categoryProducts *loader[categoryProductsKey, []domain.Product]
+	productImages    *loader[uuid.UUID, []domain.ProductImage]
+}
+
+func newLoaders(categories usecase.CategoryUsecase, products usecase.ProductUsecase, images usecase.ProductImageUsecase) *loaders {
+	return &loaders{
+		categories: newLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Category, error) {
+			list, err := categories.GetCategoriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			byID := make(map[uuid.UUID]*domain.Category, len(list))
+			for i := range list {
+				byID[list[i].ID] = &list[i]
+			}
+			return byID, nil
+		}),
+		categoryProducts: newLoader(fetchCategoryProducts(products.GetProductsByCategories)),
+		productImages:    newLoader(images.GetImagesByProductIDs),
+	}
+}
+
+// categoryProductsKey asks for the newest limit products of a category.
+type categoryProductsKey struct {
+	categoryID uuid.UUID
+	limit      int
+}
+
+// fetchCategoryProducts makes one call per distinct limit, so the limit is
+// applied by the query instead of loading every product of the categories.
+func fetchCategoryProducts(get func(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error)) func(context.Context, []categoryProductsKey) (map[categoryProductsKey][]domain.Product, error) {
+	return func(ctx context.Context, keys []categoryProductsKey) (map[categoryProductsKey][]domain.Product, error) {
+		byLimit := make(map[int][]uuid.UUID)
+		for _, key := range keys {
+			byLimit[key.limit] = append(byLimit[key.limit], key.categoryID)
+		}
+
+		result := make(map[categoryProductsKey][]domain.Product, len(keys))
+		for limit, ids := range byLimit {
+			products, err := get(ctx, ids, limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				result[categoryProductsKey{categoryID: id, limit: limit}] = products[id]
+			}
+		}
+		return result, nil
+	}
+}
+
+type loadersKey struct{}
+
+func contextWithLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
@@ -0,0 +1,100 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLoaderBatchesAndCaches(t *testing.T) {
+	var calls [][]int
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls = append(calls, slices.Clone(keys))
+		values := make(map[int]string, len(keys))
+		for _, k := range keys {
+			if k != 3 {
+				values[k] = string(rune('a' + k))
+			}
+		}
+		return values, nil
+	})
+	ctx := context.Background()
+
+	first, second, many := l.load(ctx, 1), l.load(ctx, 2), l.loadMany(ctx, []int{2, 3})
+	if v, err := first(); v != "b" || err != nil {
+		t.Errorf("first = %q, %v", v, err)
+	}
+	if v, err := second(); v != "c" || err != nil {
+		t.Errorf("second = %q, %v", v, err)
+	}
+	if v, err := many(); !slices.Equal(v, []string{"c", ""}) || err != nil {
+		t.Errorf("many = %q, %v", v, err)
+	}
+	if v, _ := l.load(ctx, 1)(); v != "b" {
+		t.Errorf("cached = %q", v)
+	}
+	if len(calls) != 1 || !slices.Equal(calls[0], []int{1, 2, 3}) {
+		t.Errorf("fetches = %v, want one fetch of [1 2 3]", calls)
+	}
+}
+
+func TestLoaderKeepsErrors(t *testing.T) {
+	calls := 0
+	failure := errors.New("database down")
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls++
+		return nil, failure
+	})
+	ctx := context.Background()
+
+	if _, err := l.load(ctx, 1)(); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want %v", err, failure)
+	}
+	if _, err := l.loadMany(ctx, []int{1})(); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want %v", err, failure)
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want a failed key not to be fetched again", calls)
+	}
+}
+
+func TestFetchCategoryProductsPassesLimits(t *testing.T) {
+	shoes, hats := uuid.New(), uuid.New()
+	limits := make(map[int][]uuid.UUID)
+	fetch := fetchCategoryProducts(func(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error) {
+		limits[limit] = append(limits[limit], categoryIDs...)
+		products := make(map[uuid.UUID][]domain.Product)
+		for _, id := range categoryIDs {
+			for range limit {
+				products[id] = append(products[id], domain.Product{ID: uuid.New()})
+			}
+		}
+		return products, nil
+	})
+
+	l := newLoader(fetch)
+	ctx := context.Background()
+	keys := []categoryProductsKey{
+		{categoryID: shoes, limit: 2},
+		{categoryID: hats, limit: 2},
+		{categoryID: shoes, limit: 5},
+	}
+	thunks := make([]func() ([]domain.Product, error), len(keys))
+	for i, key := range keys {
+		thunks[i] = l.load(ctx, key)
+	}
+
+	for i, thunk := range thunks {
+		products, err := thunk()
+		if err != nil || len(products) != keys[i].limit {
+			t.Errorf("%v: got %d products, %v", keys[i], len(products), err)
+		}
+	}
+	if len(limits) != 2 || len(limits[2]) != 2 || !slices.Equal(limits[5], []uuid.UUID{shoes}) {
+		t.Errorf("fetched by limit %v, want one call per limit", limits)
+	}
+}
@@ -0,0 +1,332 @@
+package gql
+
+import (
+	"errors"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	defaultPageLimit     = 10
+	defaultRelationLimit = 20
+	maxLimit             = 100
+)
+
+// page is the source of the ProductPage and CategoryPage types.
+type page struct {
+	items any
+	total int
+	page  int
+	limit int
+}
+
+type resolver struct {
+	categories usecase.CategoryUsecase
+	products   usecase.ProductUsecase
+}
+
+func newSchema(categories usecase.CategoryUsecase, products usecase.ProductUsecase) (graphql.Schema, error) {
+	r := &resolver{categories: categories, products: products}
+
+	imageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Image",
+		Fields: graphql.Fields{
+			"id":         field(graphql.NewNonNull(graphql.ID), func(i domain.ProductImage) any { return i.ID.String() }),
+			"productId":  field(graphql.NewNonNull(graphql.ID), func(i domain.ProductImage) any { return i.ProductID.String() }),
+			"url":        field(graphql.NewNonNull(graphql.String), func(i domain.ProductImage) any { return i.Url }),
+			"isPrimary":  field(graphql.NewNonNull(graphql.Boolean), func(i domain.ProductImage) any { return i.IsPrimary }),
+			"visibility": field(graphql.NewNonNull(graphql.String), func(i domain.ProductImage) any { return i.Visibility }),
+			"position":   field(graphql.NewNonNull(graphql.Int), func(i domain.ProductImage) any { return i.Position }),
+			"createdAt":  field(graphql.NewNonNull(graphql.DateTime), func(i domain.ProductImage) any { return i.CreatedAt }),
+		},
+	})
+
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":        field(graphql.NewNonNull(graphql.ID), func(c domain.Category) any { return c.ID.String() }),
+			"name":      field(graphql.NewNonNull(graphql.String), func(c domain.Category) any { return c.Name }),
+			"slug":      field(graphql.NewNonNull(graphql.String), func(c domain.Category) any { return c.Slug }),
+			"createdAt": field(graphql.NewNonNull(graphql.DateTime), func(c domain.Category) any { return c.CreatedAt }),
+			"updatedAt": field(graphql.NewNonNull(graphql.DateTime), func(c domain.Category) any { return c.UpdatedAt }),
+		},
+	})
+
+	productType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.Fields{
+			"id":          field(graphql.NewNonNull(graphql.ID), func(p domain.Product) any { return p.ID.String() }),
+			"name":        field(graphql.NewNonNull(graphql.String), func(p domain.Product) any { return p.Name }),
+			"slug":        field(graphql.NewNonNull(graphql.String), func(p domain.Product) any { return p.Slug }),
+			"description": field(graphql.NewNonNull(graphql.String), func(p domain.Product) any { return p.Description }),
+			"price":       field(graphql.NewNonNull(graphql.Float), func(p domain.Product) any { return p.Price }),
+			"primaryImageUrl": field(graphql.String, func(p domain.Product) any {
+				if p.PrimaryImageURL == "" {
+					return nil
+				}
+				return p.PrimaryImageURL
+			}),
+			"categories": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Resolve: r.productCategories,
+			},
+			"images": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(imageType))),
+				Resolve: r.productImages,
+			},
+			"createdAt": field(graphql.NewNonNull(graphql.DateTime), func(p domain.Product) any { return p.CreatedAt }),
+			"updatedAt": field(graphql.NewNonNull(graphql.DateTime), func(p domain.Product) any { return p.UpdatedAt }),
+		},
+	})
+
+	categoryType.AddFieldConfig("products", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+		Args: graphql.FieldConfigArgument{
+			"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultRelationLimit},
+		},
+		Resolve: r.categoryProducts,
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"product": &graphql.Field{
+				Type: productType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.product,
+			},
+			"products": &graphql.Field{
+				Type:    graphql.NewNonNull(pageType("ProductPage", productType)),
+				Args:    pageArgs(),
+				Resolve: r.productPage,
+			},
+			"category": &graphql.Field{
+				Type: categoryType,
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.ID},
+					"slug": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: r.category,
+			},
+			"categories": &graphql.Field{
+				Type:    graphql.NewNonNull(pageType("CategoryPage", categoryType)),
+				Args:    pageArgs(),
+				Resolve: r.categoryPage,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// field declares a field computed from a source of type T.
+func field[T any](typ graphql.Output, get func(T) any) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			source, _ := p.Source.(T)
+			return get(source), nil
+		},
+	}
+}
+
+func pageType(name string, item *graphql.Object) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.Fields{
+			"items":      field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item))), func(p page) any { return p.items }),
+			"total":      field(graphql.NewNonNull(graphql.Int), func(p page) any { return p.total }),
+			"page":       field(graphql.NewNonNull(graphql.Int), func(p page) any { return p.page }),
+			"limit":      field(graphql.NewNonNull(graphql.Int), func(p page) any { return p.limit }),
+			"totalPages": field(graphql.NewNonNull(graphql.Int), func(p page) any { return (p.total + p.limit - 1) / p.limit }),
+		},
+	})
+}
+
+func pageArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+		"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageLimit},
+	}
+}
+
+func (r *resolver) product(p graphql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.products.GetProductsById(p.Context, id.String())
+	if err != nil {
+		return nil, nullIfNotFound(err)
+	}
+	return *product, nil
+}
+
+func (r *resolver) productPage(p graphql.ResolveParams) (any, error) {
+	pageNum, limit, err := pageArgValues(p)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := r.products.GetProductCount(p.Context)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+
+	products, err := r.products.GetProducts(p.Context, pageNum, limit)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+
+	return page{items: products, total: total, page: pageNum, limit: limit}, nil
+}
+
+func (r *resolver) category(p graphql.ResolveParams) (any, error) {
+	_, hasID := p.Args["id"]
+	slug, hasSlug := p.Args["slug"].(string)
+	if hasID == hasSlug {
+		return nil, resolveError(domain.NewInvalidError("invalid_arguments", "Exactly one of id and slug is required"))
+	}
+
+	if hasSlug {
+		category, err := r.categories.GetCategoryBySlug(p.Context, slug)
+		if err != nil {
+			return nil, nullIfNotFound(err)
+		}
+		return *category, nil
+	}
+
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	thunk := loadersFromContext(p.Context).categories.load(p.Context, id)
+	return func() (any, error) {
+		category, err := thunk()
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		if category == nil {
+			return nil, nil
+		}
+		return *category, nil
+	}, nil
+}
+
+func (r *resolver) categoryPage(p graphql.ResolveParams) (any, error) {
+	pageNum, limit, err := pageArgValues(p)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := r.categories.GetCategoryCount(p.Context)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+
+	categories, err := r.categories.GetCategories(p.Context, pageNum, limit)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+
+	return page{items: categories, total: total, page: pageNum, limit: limit}, nil
+}
+
+// productCategories loads full categories, products only carry their IDs,
+// names and slugs.
+func (r *resolver) productCategories(p graphql.ResolveParams) (any, error) {
+	product, _ := p.Source.(domain.Product)
+	ids := make([]uuid.UUID, 0, len(product.Categories))
+	for _, c := range product.Categories {
+		ids = append(ids, c.ID)
+	}
+
+	thunk := loadersFromContext(p.Context).categories.loadMany(p.Context, ids)
+	return func() (any, error) {
+		loaded, err := thunk()
+		if err != nil {
+			return nil, resolveError(err)
+		}
+
+		categories := make([]domain.Category, 0, len(loaded))
+		for _, c := range loaded {
+			if c != nil {
+				categories = append(categories, *c)
+			}
+		}
+		return categories, nil
+	}, nil
+}
+
+func (r *resolver) productImages(p graphql.ResolveParams) (any, error) {
+	product, _ := p.Source.(domain.Product)
+
+	thunk := loadersFromContext(p.Context).productImages.load(p.Context, product.ID)
+	return func() (any, error) {
+		images, err := thunk()
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		if images == nil {
+			images = []domain.ProductImage{}
+		}
+		return images, nil
+	}, nil
+}
+
+func (r *resolver) categoryProducts(p graphql.ResolveParams) (any, error) {
+	category, _ := p.Source.(domain.Category)
+	limit, _ := p.Args["limit"].(int)
+	if limit < 1 || limit > maxLimit {
+		return nil, resolveError(domain.NewInvalidError("invalid_limit", "limit must be between 1 and 100"))
+	}
+
+	thunk := loadersFromContext(p.Context).categoryProducts.load(p.Context, categoryProductsKey{categoryID: category.ID, limit: limit})
+	return func() (any, error) {
+		products, err := thunk()
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		if products == nil {
+			products = []domain.Product{}
+		}
+		return products, nil
+	}, nil
+}
+
+func idArg(p graphql.ResolveParams, name string) (uuid.UUID, error) {
+	value, _ := p.Args[name].(string)
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, resolveError(domain.NewInvalidError("invalid_id", name+" must be a UUID"))
+	}
+	return id, nil
+}
+
+func pageArgValues(p graphql.ResolveParams) (int, int, error) {
+	pageNum, _ := p.Args["page"].(int)
+	limit, _ := p.Args["limit"].(int)
+	if pageNum < 1 {
+		return 0, 0, resolveError(domain.NewInvalidError("invalid_page", "page must be at least 1"))
+	}
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, resolveError(domain.NewInvalidError("invalid_limit", "limit must be between 1 and 100"))
+	}
+	return pageNum, limit, nil
+}
+
+// nullIfNotFound resolves a missing entity to null rather than an error.
+func nullIfNotFound(err error) error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) && domainErr.Kind == domain.ErrorKindNotFound {
+		return nil
+	}
+	return resolveError(err)
+}
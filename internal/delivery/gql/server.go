@@ -0,0 +1,69 @@
+package gql
+
+import (
+	"context"
+	"product-listing/internal/usecase"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Server executes read-only GraphQL queries over the catalog.
+type Server struct {
+	schema        graphql.Schema
+	categories    usecase.CategoryUsecase
+	products      usecase.ProductUsecase
+	images        usecase.ProductImageUsecase
+	maxDepth      int
+	maxComplexity int
+}
+
+// NewServer panics if the schema does not build, which it does unless the
+// schema definition itself is broken.
+func NewServer(categories usecase.CategoryUsecase, products usecase.ProductUsecase, images usecase.ProductImageUsecase, maxDepth, maxComplexity int) *Server {
+	schema, err := newSchema(categories, products)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Server{
+		schema:        schema,
+		categories:    categories,
+		products:      products,
+		images:        images,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}
+}
+
+// Execute runs a query. The returned flag is false when the query was rejected
+// before execution: it does not parse, is invalid against the schema, or is
+// too deep or too complex.
+func (s *Server) Execute(ctx context.Context, query string, variables map[string]any, operationName string) (*graphql.Result, bool) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, false
+	}
+
+	if err := checkLimits(&s.schema, doc, operationName, variables, s.maxDepth, s.maxComplexity); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	ctx = contextWithLoaders(ctx, newLoaders(s.categories, s.products, s.images))
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: operationName,
+		Args:          variables,
+		Context:       ctx,
+	}), true
+}
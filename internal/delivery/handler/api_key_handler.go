@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	usecase usecase.AuthUsecase
+}
+
+func NewAPIKeyHandler(u usecase.AuthUsecase) *APIKeyHandler {
+	return &APIKeyHandler{usecase: u}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.APIKeyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	apiKey, key, err := h.usecase.CreateAPIKey(c.Request.Context(), req.Name, domain.Role(req.Role), req.Store)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "API key created, store the key now as it will not be shown again",
+		Data: dto.APIKeyCreatedResp{
+			APIKeyResp: dto.ToAPIKeyDTO(apiKey),
+			Key:        key,
+		},
+	})
+}
+
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.usecase.GetAPIKeys(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.APIKeyResp, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, dto.ToAPIKeyDTO(&k))
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get api keys",
+		Data:    resp,
+	})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.usecase.RevokeAPIKey(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "API key revoked",
+	})
+}
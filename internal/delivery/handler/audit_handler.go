@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	usecase usecase.AuditUsecase
+}
+
+func NewAuditHandler(u usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{usecase: u}
+}
+
+func (h *AuditHandler) GetEntries(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	entries, next, err := h.usecase.GetEntries(c.Request.Context(), filter, c.Query("cursor"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.AuditEntryResp, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, dto.ToAuditEntryDTO(&e))
+	}
+
+	c.JSON(http.StatusOK, dto.CursorResponse{
+		Status:     http.StatusOK,
+		Message:    "Success get audit entries",
+		Data:       resp,
+		NextCursor: next,
+	})
+}
+
+// Export streams every matching entry as newline delimited JSON, newest
+// first. Once the first entry is out, a failure can only cut the stream short.
+func (h *AuditHandler) Export(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	started := false
+	start := func() {
+		started = true
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="audit-`+time.Now().UTC().Format("20060102T150405Z")+`.ndjson"`)
+		c.Status(http.StatusOK)
+	}
+
+	enc := json.NewEncoder(c.Writer)
+	err = h.usecase.Export(c.Request.Context(), filter, func(e domain.AuditEntry) error {
+		if !started {
+			start()
+		}
+		return enc.Encode(dto.ToAuditEntryDTO(&e))
+	})
+	switch {
+	case err != nil && !started:
+		writeError(c, err)
+	case err != nil:
+		_ = c.Error(err)
+		c.Abort()
+	case !started:
+		start()
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+	}
+
+	var err error
+	if filter.Since, err = parseAuditTime(c, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseAuditTime(c, "until"); err != nil {
+		return filter, err
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit < 1 {
+			return filter, domain.NewInvalidError("invalid_limit", "limit must be a positive number")
+		}
+	}
+
+	return filter, nil
+}
+
+func parseAuditTime(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, domain.NewInvalidError("invalid_"+name, name+" must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
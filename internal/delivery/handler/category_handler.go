@@ -34,7 +34,8 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	if err := h.usecase.CreateCategory(ctx, input); err != nil {
+	category, err := h.usecase.CreateCategory(ctx, input)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
 			Status:  http.StatusInternalServerError,
 			Message: err.Error(),
@@ -42,14 +43,16 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.SuccessResp{
+	setLocation(c, category.ID.String())
+	c.JSON(http.StatusCreated, dto.Response{
 		Status:  http.StatusCreated,
 		Message: "Category created",
+		Data:    dto.ToCategoryDTO(category),
 	})
 }
 
 func (h *CategoryHandler) GetCategories(c *gin.Context) {
-	total, err := h.usecase.GetCategoryCount(c)
+	total, err := h.usecase.GetCategoryCount(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
 			Status:  http.StatusInternalServerError,
@@ -76,6 +79,7 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 		result = append(result, item)
 	}
 
+	setCategoryCacheHeaders(c, categories, domain.CategoryListSurrogateKey(requestStoreID(c)))
 	c.JSON(http.StatusOK, dto.PaginatedResponse{
 		Status:     http.StatusOK,
 		Message:    "Success get categories",
@@ -100,6 +104,7 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
+	setCategoryCacheHeaders(c, []domain.Category{*category})
 	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success get category",
@@ -120,6 +125,7 @@ func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
 		return
 	}
 
+	setCategoryCacheHeaders(c, []domain.Category{*category})
 	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success get category",
@@ -144,17 +150,16 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	if err := h.usecase.UpdateCategory(ctx, id, input); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+	category, err := h.usecase.UpdateCategory(ctx, id, input)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SuccessResp{
+	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Category updated",
+		Data:    dto.ToCategoryDTO(category),
 	})
 }
 
@@ -163,10 +168,7 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	if err := h.usecase.DeleteCategory(ctx, id); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 
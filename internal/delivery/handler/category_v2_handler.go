@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryV2Handler serves categories under /api/v2. Unlike v1 it reports
+// usecase errors with their own status and code.
+type CategoryV2Handler struct {
+	usecase        usecase.CategoryUsecase
+	productUsecase usecase.ProductUsecase
+}
+
+func NewCategoryV2Handler(u usecase.CategoryUsecase, p usecase.ProductUsecase) *CategoryV2Handler {
+	return &CategoryV2Handler{usecase: u, productUsecase: p}
+}
+
+func (h *CategoryV2Handler) CreateCategory(c *gin.Context) {
+	var req dto.CategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	category, err := h.usecase.CreateCategory(c.Request.Context(), domain.CategoryInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setLocation(c, category.ID.String())
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "Category created",
+		Data:    dto.ToCategoryV2DTO(category),
+	})
+}
+
+func (h *CategoryV2Handler) GetCategories(c *gin.Context) {
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	total, err := h.usecase.GetCategoryCount(ctx)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	categories, err := h.usecase.GetCategories(ctx, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result := make([]dto.CategoryRespV2, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, dto.ToCategoryV2DTO(&category))
+	}
+
+	setCategoryCacheHeaders(c, categories, domain.CategoryListSurrogateKey(requestStoreID(c)))
+	c.JSON(http.StatusOK, dto.PaginatedResponse{
+		Status:     http.StatusOK,
+		Message:    "Success get categories",
+		Data:       result,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages(total, limit),
+	})
+}
+
+func (h *CategoryV2Handler) GetCategoryByID(c *gin.Context) {
+	category, err := h.usecase.GetCategoryById(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setCategoryCacheHeaders(c, []domain.Category{*category})
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get category",
+		Data:    dto.ToCategoryV2DTO(category),
+	})
+}
+
+func (h *CategoryV2Handler) GetCategoryBySlug(c *gin.Context) {
+	category, err := h.usecase.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setCategoryCacheHeaders(c, []domain.Category{*category})
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get category",
+		Data:    dto.ToCategoryV2DTO(category),
+	})
+}
+
+func (h *CategoryV2Handler) UpdateCategory(c *gin.Context) {
+	var req dto.CategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	category, err := h.usecase.UpdateCategory(c.Request.Context(), c.Param("id"), domain.CategoryInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Category updated",
+		Data:    dto.ToCategoryV2DTO(category),
+	})
+}
+
+func (h *CategoryV2Handler) DeleteCategory(c *gin.Context) {
+	if err := h.usecase.DeleteCategory(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Category deleted",
+	})
+}
+
+func (h *CategoryV2Handler) GetCategoryProducts(c *gin.Context) {
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	products, err := h.productUsecase.GetProductsByCategory(ctx, c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if err := h.productUsecase.ExpandProducts(ctx, products, include); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result := make([]dto.ProductRespV2, 0, len(products))
+	for _, p := range products {
+		result = append(result, dto.ToProductV2DTO(&p))
+	}
+
+	setProductCacheHeaders(c, products, domain.ProductListSurrogateKey(requestStoreID(c)), domain.CategorySurrogateKey(c.Param("id")))
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get products by category",
+		Data:    result,
+	})
+}
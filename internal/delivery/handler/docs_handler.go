@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsHandler serves the OpenAPI document and a page rendering it. The page
+// loads no external assets, so it also works offline.
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+func (h *DocsHandler) GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openapi.Spec)
+}
+
+func (h *DocsHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
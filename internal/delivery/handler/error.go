@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeError maps usecase errors to a response. Business rule violations keep
+// their code, anything else is reported as an internal error.
+func writeError(c *gin.Context, err error) {
+	resp := toErrorResp(err)
+	c.JSON(resp.Status, resp)
+}
+
+func toErrorResp(err error) dto.ErrorResp {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return dto.ErrorResp{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+
+	status := http.StatusInternalServerError
+	switch domainErr.Kind {
+	case domain.ErrorKindInvalid:
+		status = http.StatusBadRequest
+	case domain.ErrorKindNotFound:
+		status = http.StatusNotFound
+	case domain.ErrorKindConflict:
+		status = http.StatusConflict
+	case domain.ErrorKindForbidden:
+		status = http.StatusForbidden
+	case domain.ErrorKindUnauthorized:
+		status = http.StatusUnauthorized
+	case domain.ErrorKindUnprocessable:
+		status = http.StatusUnprocessableEntity
+	}
+
+	return dto.ErrorResp{
+		Status:  status,
+		Message: domainErr.Message,
+		Code:    domainErr.Code,
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamRetry is how long EventSource clients wait before reconnecting
+// to a stream that ended.
+const eventStreamRetry = 3 * time.Second
+
+// EventStreamHandler serves the change feed of a store as Server-Sent Events.
+type EventStreamHandler struct {
+	usecase   usecase.ChangeFeedUsecase
+	changes   domain.ChangeBus
+	poll      time.Duration
+	heartbeat time.Duration
+	shutdown  <-chan struct{}
+}
+
+// NewEventStreamHandler returns a handler that reads new events as soon as
+// changes announces a change of the store, and every poll in case the events
+// were not visible yet. It sends a comment every heartbeat without events.
+// Streams end when shutdown is closed, so the server can finish shutting down.
+func NewEventStreamHandler(u usecase.ChangeFeedUsecase, changes domain.ChangeBus, poll, heartbeat time.Duration, shutdown <-chan struct{}) *EventStreamHandler {
+	return &EventStreamHandler{usecase: u, changes: changes, poll: poll, heartbeat: heartbeat, shutdown: shutdown}
+}
+
+// Stream sends every change committed after the stream started, or after the
+// event named by the Last-Event-ID header or last_event_id parameter, as an
+// SSE event of the change's type with the change as data. Events can be
+// limited to entity types with entity_type, repeated or comma separated.
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var entityTypes []string
+	for _, value := range c.QueryArray("entity_type") {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				entityTypes = append(entityTypes, t)
+			}
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	position, err := h.usecase.Start(ctx, lastEventID, entityTypes)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventStreamRetry.Milliseconds())
+	c.Writer.Flush()
+
+	changes, unsubscribe := h.changes.Subscribe()
+	defer unsubscribe()
+	store := domain.StoreFromContext(ctx)
+
+	poll := time.NewTicker(h.poll)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		events, err := h.usecase.Next(ctx, position, entityTypes)
+		if err != nil {
+			if ctx.Err() == nil {
+				_ = c.Error(err)
+			}
+			return
+		}
+
+		for _, e := range events {
+			c.Render(-1, sse.Event{Id: e.Position.String(), Event: e.Event.Type, Data: e.Event})
+			if c.IsAborted() {
+				return
+			}
+			position = e.Position
+		}
+		if len(events) > 0 {
+			c.Writer.Flush()
+			heartbeat.Reset(h.heartbeat)
+		}
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.shutdown:
+				return
+			case change := <-changes:
+				if change.Missed() || change.StoreID == store.ID {
+					break wait
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			case <-poll.C:
+				break wait
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/delivery/gql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler serves GraphQL queries. Responses use the GraphQL shape
+// rather than the API envelope: a query that cannot run is answered with 400,
+// one that ran is answered with 200 even when some fields failed.
+type GraphQLHandler struct {
+	server *gql.Server
+}
+
+func NewGraphQLHandler(s *gql.Server) *GraphQLHandler {
+	return &GraphQLHandler{server: s}
+}
+
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req dto.GraphQLReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, ok := h.server.Execute(c.Request.Context(), req.Query, req.Variables, req.OperationName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/domain"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// setProductCacheHeaders describes a response of products to HTTP caches:
+// the products and their categories as surrogate keys, next to extraKeys
+// such as the store's listing key for listings, and the last update as
+// Last-Modified. Responses with signed media URLs
+// are kept out of shared caches, since the URLs expire.
+func setProductCacheHeaders(c *gin.Context, products []domain.Product, extraKeys ...string) {
+	keys := extraKeys
+	var lastModified time.Time
+	signed := false
+	for _, p := range products {
+		keys = append(keys, domain.ProductSurrogateKey(p.ID.String()))
+		for _, category := range p.Categories {
+			keys = append(keys, domain.CategorySurrogateKey(category.ID.String()))
+		}
+		if p.UpdatedAt.After(lastModified) {
+			lastModified = p.UpdatedAt
+		}
+		signed = signed || p.PrimaryImagePrivate || hasPrivateImage(p.Images)
+	}
+	setCacheHeaders(c, keys, lastModified, signed)
+}
+
+// setCategoryCacheHeaders is setProductCacheHeaders for categories.
+func setCategoryCacheHeaders(c *gin.Context, categories []domain.Category, extraKeys ...string) {
+	keys := extraKeys
+	var lastModified time.Time
+	for _, category := range categories {
+		keys = append(keys, domain.CategorySurrogateKey(category.ID.String()))
+		if category.UpdatedAt.After(lastModified) {
+			lastModified = category.UpdatedAt
+		}
+	}
+	setCacheHeaders(c, keys, lastModified, false)
+}
+
+// setImageCacheHeaders is setProductCacheHeaders for the images of a product.
+func setImageCacheHeaders(c *gin.Context, images []domain.ProductImage, productID string) {
+	keys := []string{domain.ProductSurrogateKey(productID)}
+	var lastModified time.Time
+	for _, img := range images {
+		if img.CreatedAt.After(lastModified) {
+			lastModified = img.CreatedAt
+		}
+	}
+	setCacheHeaders(c, keys, lastModified, hasPrivateImage(images))
+}
+
+func setCacheHeaders(c *gin.Context, keys []string, lastModified time.Time, signed bool) {
+	slices.Sort(keys)
+	c.Header("Surrogate-Key", strings.Join(slices.Compact(keys), " "))
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if signed {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+}
+
+// requestStoreID is the store ResolveStore bound the request to.
+func requestStoreID(c *gin.Context) uuid.UUID {
+	return domain.StoreFromContext(c.Request.Context()).ID
+}
+
+func hasPrivateImage(images []domain.ProductImage) bool {
+	for _, img := range images {
+		if img.Visibility == domain.ImageVisibilityPrivate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"product-listing/internal/domain"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestSurrogateKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storeID := uuid.New()
+	kitchen := domain.Category{ID: uuid.New(), UpdatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	mug := domain.Product{ID: uuid.New(), Categories: []domain.Category{kitchen}, UpdatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}
+	lamp := domain.Product{ID: uuid.New(), UpdatedAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name         string
+		set          func(c *gin.Context)
+		keys         []string
+		lastModified string
+	}{
+		{"product", func(c *gin.Context) {
+			setProductCacheHeaders(c, []domain.Product{mug})
+		}, []string{"category/" + kitchen.ID.String(), "product/" + mug.ID.String()}, "Sun, 01 Feb 2026 00:00:00 GMT"},
+		{"product listing", func(c *gin.Context) {
+			setProductCacheHeaders(c, []domain.Product{mug, lamp}, domain.ProductListSurrogateKey(storeID))
+		}, []string{"category/" + kitchen.ID.String(), "product/" + lamp.ID.String(), "product/" + mug.ID.String(), "store/" + storeID.String() + "/products"}, "Wed, 01 Apr 2026 00:00:00 GMT"},
+		{"category", func(c *gin.Context) {
+			setCategoryCacheHeaders(c, []domain.Category{kitchen})
+		}, []string{"category/" + kitchen.ID.String()}, "Sun, 01 Mar 2026 00:00:00 GMT"},
+		{"category listing", func(c *gin.Context) {
+			setCategoryCacheHeaders(c, []domain.Category{kitchen}, domain.CategoryListSurrogateKey(storeID))
+		}, []string{"category/" + kitchen.ID.String(), "store/" + storeID.String() + "/categories"}, "Sun, 01 Mar 2026 00:00:00 GMT"},
+		{"images", func(c *gin.Context) {
+			setImageCacheHeaders(c, nil, mug.ID.String())
+		}, []string{"product/" + mug.ID.String()}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			tt.set(c)
+
+			want := strings.Join(slices.Sorted(slices.Values(tt.keys)), " ")
+			if got := rec.Header().Get("Surrogate-Key"); got != want {
+				t.Errorf("Surrogate-Key = %q, want %q", got, want)
+			}
+			if got := rec.Header().Get("Last-Modified"); got != tt.lastModified {
+				t.Errorf("Last-Modified = %q, want %q", got, tt.lastModified)
+			}
+		})
+	}
+}
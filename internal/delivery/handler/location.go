@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setLocation points the Location header at a resource created by a POST to
+// a collection route, e.g. /api/products/ gives /api/products/<id>.
+func setLocation(c *gin.Context, id string) {
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+id)
+}
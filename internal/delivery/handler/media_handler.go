@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/netguard"
+	"product-listing/internal/usecase"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaHandler serves images behind signed URLs by streaming them from their
+// origin, so the origin URL of a private image never reaches the client.
+// Images under baseURL are read from store. Any other image is fetched only
+// when its origin is listed in origins, and never from a private, loopback or
+// link-local address, so an image URL cannot reach into the internal network.
+type MediaHandler struct {
+	usecase usecase.ProductImageUsecase
+	store   domain.BlobStore
+	baseURL string
+	origins map[string]bool
+	client  *http.Client
+}
+
+func NewMediaHandler(u usecase.ProductImageUsecase, store domain.BlobStore, baseURL string, origins []string) *MediaHandler {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+		}
+	}
+
+	return &MediaHandler{
+		usecase: u,
+		store:   store,
+		baseURL: baseURL,
+		origins: allowed,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: netguard.Transport(10 * time.Second),
+			// A redirect could leave the allowed origins
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (h *MediaHandler) ServeImage(c *gin.Context) {
+	ctx := c.Request.Context()
+	img, err := h.usecase.ResolveMedia(ctx, c.Param("id"), c.Request.URL.Query())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if h.baseURL != "" && strings.HasPrefix(img.Url, h.baseURL) {
+		h.serveStored(c, strings.TrimPrefix(img.Url, h.baseURL))
+		return
+	}
+
+	origin, err := url.Parse(img.Url)
+	if err != nil || !h.origins[strings.ToLower(origin.Scheme+"://"+origin.Host)] {
+		c.JSON(http.StatusBadGateway, dto.ErrorResp{
+			Status:  http.StatusBadGateway,
+			Message: "image origin not allowed",
+		})
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, img.Url, nil)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, dto.ErrorResp{
+			Status:  http.StatusBadGateway,
+			Message: "invalid image origin",
+		})
+		return
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, dto.ErrorResp{
+			Status:  http.StatusBadGateway,
+			Message: "failed to fetch image",
+		})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.JSON(http.StatusBadGateway, dto.ErrorResp{
+			Status:  http.StatusBadGateway,
+			Message: "image origin returned " + resp.Status,
+		})
+		return
+	}
+
+	c.Header("Content-Type", resp.Header.Get("Content-Type"))
+	if length := resp.Header.Get("Content-Length"); length != "" {
+		c.Header("Content-Length", length)
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, resp.Body)
+}
+
+// serveStored streams a file of the local media store.
+func (h *MediaHandler) serveStored(c *gin.Context, key string) {
+	key, _, _ = strings.Cut(key, "?")
+	f, err := h.store.Open(c.Request.Context(), key)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer f.Close()
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, f)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"product-listing/internal/usecase"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler serves metrics in the Prometheus text format.
+type MetricsHandler struct {
+	outbox usecase.OutboxUsecase
+}
+
+func NewMetricsHandler(outbox usecase.OutboxUsecase) *MetricsHandler {
+	return &MetricsHandler{outbox: outbox}
+}
+
+// GetMetrics reports the outbox relay lag per publisher. The numbers come
+// from the database, so every instance reports the same.
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	lags, err := h.outbox.GetLag(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	now := time.Now()
+	var pending, lag, relayed strings.Builder
+	for _, l := range lags {
+		label := fmt.Sprintf("{publisher=%q}", l.Publisher)
+
+		seconds := 0.0
+		if l.OldestPendingAt != nil {
+			seconds = now.Sub(*l.OldestPendingAt).Seconds()
+		}
+
+		fmt.Fprintf(&pending, "outbox_pending_events%s %d\n", label, l.Pending)
+		fmt.Fprintf(&lag, "outbox_lag_seconds%s %.3f\n", label, seconds)
+		fmt.Fprintf(&relayed, "outbox_last_relay_timestamp_seconds%s %d\n", label, l.RelayedAt.Unix())
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP outbox_pending_events Events the publisher has not relayed yet.\n")
+	b.WriteString("# TYPE outbox_pending_events gauge\n")
+	b.WriteString(pending.String())
+	b.WriteString("# HELP outbox_lag_seconds Age of the oldest event the publisher has not relayed yet, 0 when it has caught up.\n")
+	b.WriteString("# TYPE outbox_lag_seconds gauge\n")
+	b.WriteString(lag.String())
+	b.WriteString("# HELP outbox_last_relay_timestamp_seconds When the publisher last relayed an event.\n")
+	b.WriteString("# TYPE outbox_last_relay_timestamp_seconds gauge\n")
+	b.WriteString(relayed.String())
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"product-listing/internal/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination reads the page and limit query parameters of v2 list
+// routes, defaulting to the first page of 10.
+func parsePagination(c *gin.Context) (int, int, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, domain.NewInvalidError("invalid_page", "page must be a positive number")
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		return 0, 0, domain.NewInvalidError("invalid_limit", "limit must be a positive number")
+	}
+
+	return page, limit, nil
+}
+
+func totalPages(total, limit int) int {
+	return (total + limit - 1) / limit
+}
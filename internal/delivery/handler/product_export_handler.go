@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/domain"
+	"product-listing/internal/exporter"
+	"product-listing/internal/usecase"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ProductExportHandler struct {
+	usecase usecase.ProductExportUsecase
+}
+
+func NewProductExportHandler(u usecase.ProductExportUsecase) *ProductExportHandler {
+	return &ProductExportHandler{usecase: u}
+}
+
+// Export streams the products of the store as a file in the requested
+// format, csv by default. Once the first product is out, a failure can only
+// cut the file short.
+func (h *ProductExportHandler) Export(c *gin.Context) {
+	var filter domain.ProductExportFilter
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			writeError(c, domain.NewInvalidError("invalid_id", "invalid category id: "+raw))
+			return
+		}
+		filter.CategoryID = &id
+	}
+
+	format := c.DefaultQuery("format", domain.ExportFormatCSV)
+	writer, err := exporter.NewWriter(c.Writer, format)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	started := false
+	start := func() {
+		started = true
+		c.Header("Content-Type", exporter.ContentType(format))
+		c.Header("Content-Disposition", `attachment; filename="products-`+time.Now().UTC().Format("20060102T150405Z")+`.`+format+`"`)
+		c.Status(http.StatusOK)
+	}
+
+	err = h.usecase.Export(c.Request.Context(), filter, func(p *domain.Product) error {
+		if !started {
+			start()
+		}
+		return writer.Write(p)
+	})
+	if err == nil {
+		if !started {
+			start()
+		}
+		err = writer.Close()
+	}
+	switch {
+	case err != nil && !started:
+		writeError(c, err)
+	case err != nil:
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var feedContentTypes = map[string]string{
+	domain.FeedFormatXML: "application/xml; charset=utf-8",
+	domain.FeedFormatTSV: "text/tab-separated-values; charset=utf-8",
+}
+
+type ProductFeedHandler struct {
+	usecase usecase.ProductFeedUsecase
+	maxAge  time.Duration
+}
+
+func NewProductFeedHandler(u usecase.ProductFeedUsecase, maxAge time.Duration) *ProductFeedHandler {
+	return &ProductFeedHandler{usecase: u, maxAge: maxAge}
+}
+
+func (h *ProductFeedHandler) GoogleXML(c *gin.Context) {
+	h.serve(c, domain.FeedFormatXML)
+}
+
+func (h *ProductFeedHandler) GoogleTSV(c *gin.Context) {
+	h.serve(c, domain.FeedFormatTSV)
+}
+
+// serve streams a feed that anyone may cache for maxAge. The ETag changes
+// with every change to the feed, so shopping engines polling it can
+// revalidate instead of downloading it again.
+func (h *ProductFeedHandler) serve(c *gin.Context, format string) {
+	feed, err := h.usecase.Feed(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%d-%s"`, feed.Version, feed.Fingerprint)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", feed.UpdatedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	c.Header("Vary", "X-Store")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	w := bufio.NewWriter(c.Writer)
+	started := false
+	err = h.usecase.Write(c.Request.Context(), format, func(s string) error {
+		if !started {
+			started = true
+			c.Header("Content-Type", feedContentTypes[format])
+			c.Status(http.StatusOK)
+		}
+		_, err := w.WriteString(s)
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	switch {
+	case err != nil && !started:
+		// The error must not be cached in place of the feed
+		c.Writer.Header().Del("ETag")
+		c.Header("Cache-Control", "no-store")
+		writeError(c, err)
+	case err != nil:
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, weak
+// comparison as RFC 9110 asks for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
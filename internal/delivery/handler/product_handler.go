@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"product-listing/internal/delivery/dto"
 	"product-listing/internal/domain"
 	"product-listing/internal/usecase"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -52,7 +54,8 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		Price:       req.Price,
 	}
 
-	if err := h.usecase.CreateProduct(ctx, input); err != nil {
+	product, err := h.usecase.CreateProduct(ctx, input)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
 			Status:  http.StatusInternalServerError,
 			Message: "failed to create product",
@@ -60,14 +63,25 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	setLocation(c, product.ID.String())
 	c.JSON(http.StatusCreated, dto.Response{
 		Status:  http.StatusCreated,
 		Message: "Product created",
+		Data:    dto.ToProductDTO(product),
 	})
 }
 
 func (h *ProductHandler) GetProducts(c *gin.Context) {
 	ctx := c.Request.Context()
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	total, err := h.usecase.GetProductCount(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
@@ -88,10 +102,19 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		return
 	}
 
+	if err := h.usecase.ExpandProducts(ctx, products, include); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	productResp := make([]dto.ProductResp, 0, len(products))
 	for _, p := range products {
 		productResp = append(productResp, dto.ToProductDTO(&p))
 	}
+	setProductCacheHeaders(c, products, domain.ProductListSurrogateKey(requestStoreID(c)))
 	c.JSON(http.StatusOK, dto.PaginatedResponse{
 		Status:     http.StatusOK,
 		Message:    "Success get categories",
@@ -106,6 +129,15 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 func (h *ProductHandler) GetProductById(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	product, err := h.usecase.GetProductsById(ctx, id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
@@ -115,7 +147,17 @@ func (h *ProductHandler) GetProductById(c *gin.Context) {
 		return
 	}
 
-	result := dto.ToProductDTO(product)
+	products := []domain.Product{*product}
+	if err := h.usecase.ExpandProducts(ctx, products, include); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := dto.ToProductDTO(&products[0])
+	setProductCacheHeaders(c, products)
 	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success get product",
@@ -126,6 +168,14 @@ func (h *ProductHandler) GetProductById(c *gin.Context) {
 func (h *ProductHandler) GetProductByCategory(c *gin.Context) {
 	ctx := c.Request.Context()
 	categoryID := c.Param("category_id")
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
 
 	products, err := h.usecase.GetProductsByCategory(ctx, categoryID)
 	if err != nil {
@@ -136,11 +186,20 @@ func (h *ProductHandler) GetProductByCategory(c *gin.Context) {
 		return
 	}
 
+	if err := h.usecase.ExpandProducts(ctx, products, include); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	productResp := make([]dto.ProductResp, 0, len(products))
 	for _, p := range products {
 		productResp = append(productResp, dto.ToProductDTO(&p))
 	}
 
+	setProductCacheHeaders(c, products, domain.ProductListSurrogateKey(requestStoreID(c)), domain.CategorySurrogateKey(categoryID))
 	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success get products by category",
@@ -181,17 +240,16 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		Price:       req.Price,
 	}
 
-	if err := h.usecase.UpdateProduct(ctx, id, input); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: "failed to update product",
-		})
+	product, err := h.usecase.UpdateProduct(ctx, id, input)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SuccessResp{
+	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success update product",
+		Data:    dto.ToProductDTO(product),
 	})
 }
 
@@ -200,10 +258,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 	id := c.Param("id")
 	if err := h.usecase.DeleteProduct(ctx, id); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: "Failed delete product",
-		})
+		writeError(c, err)
 		return
 	}
 
@@ -212,3 +267,24 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		Message: "Success delete product",
 	})
 }
+
+// parseProductInclude reads the comma separated include query parameter,
+// e.g. ?include=images,categories. Categories are embedded by default, so
+// asking for them is accepted but changes nothing. Products have no variants
+// yet, variants is accepted the same way so clients can ask for it today.
+func parseProductInclude(c *gin.Context) (domain.ProductInclude, error) {
+	var include domain.ProductInclude
+
+	for _, name := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "images":
+			include.Images = true
+		case "categories", "variants":
+		default:
+			return include, fmt.Errorf("unsupported include: %s", name)
+		}
+	}
+
+	return include, nil
+}
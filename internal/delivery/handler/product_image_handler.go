@@ -38,17 +38,15 @@ func (h *ProductImageHandler) AddImage(c *gin.Context) {
 	}
 
 	input := domain.ProductImageInput{
-		ProductID: puid,
-		Url:       req.Url,
-		IsPrimary: req.IsPrimary,
+		ProductID:  puid,
+		Url:        req.Url,
+		IsPrimary:  req.IsPrimary,
+		Visibility: req.Visibility,
 	}
 
 	img, err := h.usecase.AddImage(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 
@@ -63,10 +61,7 @@ func (h *ProductImageHandler) GetProductImages(c *gin.Context) {
 	productID := c.Param("product_id")
 	images, err := h.usecase.GetProductImages(c.Request.Context(), productID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 
@@ -75,6 +70,7 @@ func (h *ProductImageHandler) GetProductImages(c *gin.Context) {
 		resp = append(resp, dto.ToProductImageDTO(&img))
 	}
 
+	setImageCacheHeaders(c, images, productID)
 	c.JSON(http.StatusOK, dto.Response{
 		Status:  http.StatusOK,
 		Message: "Success",
@@ -85,10 +81,7 @@ func (h *ProductImageHandler) GetProductImages(c *gin.Context) {
 func (h *ProductImageHandler) DeleteImage(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.usecase.DeleteImage(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 
@@ -103,15 +96,98 @@ func (h *ProductImageHandler) SetPrimary(c *gin.Context) {
 	imageID := c.Param("image_id")
 
 	if err := h.usecase.SetPrimary(c.Request.Context(), productID, imageID); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResp{
-			Status:  http.StatusInternalServerError,
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Primary image set",
+	})
+}
+
+func (h *ProductImageHandler) SetVisibility(c *gin.Context) {
+	id := c.Param("id")
+	var req dto.ProductImageVisibilityReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
 
+	if err := h.usecase.SetVisibility(c.Request.Context(), id, req.Visibility); err != nil {
+		writeError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.SuccessResp{
 		Status:  http.StatusOK,
-		Message: "Primary image set",
+		Message: "Image visibility updated",
+	})
+}
+
+func (h *ProductImageHandler) ApplyBatch(c *gin.Context) {
+	var req dto.ImageBatchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ops := make([]domain.ImageOperation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		ops = append(ops, domain.ImageOperation{
+			Op:         op.Op,
+			ProductID:  op.ProductID,
+			ImageID:    op.ImageID,
+			ImageIDs:   op.ImageIDs,
+			Url:        op.Url,
+			IsPrimary:  op.IsPrimary,
+			Visibility: op.Visibility,
+		})
+	}
+
+	results, err := h.usecase.ApplyOperations(c.Request.Context(), ops)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.ImageOperationResp, 0, len(results))
+	for _, result := range results {
+		item := dto.ImageOperationResp{
+			Index:   result.Index,
+			Op:      result.Op,
+			Status:  http.StatusOK,
+			Message: "Success",
+		}
+
+		switch {
+		case result.Err != nil:
+			errResp := toErrorResp(result.Err)
+			item.Status = errResp.Status
+			item.Code = errResp.Code
+			item.Message = errResp.Message
+		case result.Aborted:
+			item.Status = http.StatusFailedDependency
+			item.Code = "aborted"
+			item.Message = "not applied, another operation for this product failed"
+		case result.Image != nil:
+			img := dto.ToProductImageDTO(result.Image)
+			item.Status = http.StatusCreated
+			item.Data = &img
+		}
+
+		resp = append(resp, item)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Batch processed",
+		Data:    resp,
 	})
 }
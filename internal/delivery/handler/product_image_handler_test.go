@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// batchUsecase answers a batch with fixed results and records the operations.
+type batchUsecase struct {
+	usecase.ProductImageUsecase
+	results []domain.ImageOperationResult
+	err     error
+	ops     []domain.ImageOperation
+}
+
+func (u *batchUsecase) ApplyOperations(ctx context.Context, ops []domain.ImageOperation) ([]domain.ImageOperationResult, error) {
+	u.ops = ops
+	return u.results, u.err
+}
+
+func postBatch(t *testing.T, u usecase.ProductImageUsecase, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/api/product-images/batch", NewProductImageHandler(u).ApplyBatch)
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/product-images/batch", strings.NewReader(body)))
+	return rec
+}
+
+func TestApplyBatchReportsEachOperation(t *testing.T) {
+	added := &domain.ProductImage{ID: uuid.New(), ProductID: uuid.New(), Url: "https://cdn.example.com/a.jpg"}
+	u := &batchUsecase{results: []domain.ImageOperationResult{
+		{Index: 0, Op: domain.ImageOpAdd, Image: added},
+		{Index: 1, Op: domain.ImageOpAdd, Aborted: true},
+		{Index: 2, Op: domain.ImageOpSetPrimary, Err: domain.NewConflictError("image_product_mismatch", "image does not belong to product")},
+		{Index: 3, Op: domain.ImageOpDelete, Err: domain.NewNotFoundError("image_not_found", "image not found")},
+		{Index: 4, Op: domain.ImageOpReorder},
+		{Index: 5, Op: domain.ImageOpDelete, Err: errors.New("connection reset")},
+	}}
+
+	rec := postBatch(t, u, `{"operations": [
+		{"op": "add", "product_id": "p1", "url": "https://cdn.example.com/a.jpg", "is_primary": true},
+		{"op": "add", "product_id": "p2", "url": "https://cdn.example.com/b.jpg"},
+		{"op": "set_primary", "product_id": "p2", "image_id": "i1"},
+		{"op": "delete", "image_id": "i2"},
+		{"op": "reorder", "product_id": "p1", "image_ids": ["i3", "i4"]},
+		{"op": "delete", "image_id": "i5"}
+	]}`)
+
+	// A batch with failed operations still succeeds as a whole
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(u.ops) != 6 || !u.ops[0].IsPrimary || u.ops[4].ImageIDs[1] != "i4" {
+		t.Errorf("operations = %+v, want the request's", u.ops)
+	}
+
+	var resp struct {
+		Data []dto.ImageOperationResp `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusCreated, ""},
+		{http.StatusFailedDependency, "aborted"},
+		{http.StatusConflict, "image_product_mismatch"},
+		{http.StatusNotFound, "image_not_found"},
+		{http.StatusOK, ""},
+		{http.StatusInternalServerError, ""},
+	}
+	if len(resp.Data) != len(want) {
+		t.Fatalf("got %d results, want %d", len(resp.Data), len(want))
+	}
+	for i, w := range want {
+		got := resp.Data[i]
+		if got.Index != i || got.Status != w.status || got.Code != w.code {
+			t.Errorf("result %d = %+v, want status %d code %q", i, got, w.status, w.code)
+		}
+	}
+	if resp.Data[0].Data == nil || resp.Data[0].Data.ID != added.ID.String() {
+		t.Errorf("added image = %+v, want %s", resp.Data[0].Data, added.ID)
+	}
+	if resp.Data[1].Data != nil {
+		t.Errorf("aborted add returned image %+v", resp.Data[1].Data)
+	}
+}
+
+func TestApplyBatchRejectsWholeBatch(t *testing.T) {
+	u := &batchUsecase{err: domain.NewInvalidError("operations_required", "at least one operation is required")}
+
+	rec := postBatch(t, u, `{"operations": []}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "operations_required") {
+		t.Errorf("response = %d %s, want 400 operations_required", rec.Code, rec.Body)
+	}
+
+	rec = postBatch(t, u, `{"operations": `)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body status = %d, want 400", rec.Code)
+	}
+}
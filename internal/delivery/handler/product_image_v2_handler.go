@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProductImageV2Handler serves the image gallery of a product under
+// /api/v2/products/:id/images. Images of other products are not found.
+type ProductImageV2Handler struct {
+	usecase usecase.ProductImageUsecase
+}
+
+func NewProductImageV2Handler(u usecase.ProductImageUsecase) *ProductImageV2Handler {
+	return &ProductImageV2Handler{usecase: u}
+}
+
+func (h *ProductImageV2Handler) AddImage(c *gin.Context) {
+	var req dto.ProductImageReqV2
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, domain.NewInvalidError("invalid_product_id", "invalid id: "+c.Param("id")))
+		return
+	}
+
+	img, err := h.usecase.AddImage(c.Request.Context(), domain.ProductImageInput{
+		ProductID:  productID,
+		Url:        req.Url,
+		IsPrimary:  req.IsPrimary,
+		Visibility: req.Visibility,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setLocation(c, img.ID.String())
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "Image added",
+		Data:    dto.ToProductImageDTO(img),
+	})
+}
+
+func (h *ProductImageV2Handler) GetImages(c *gin.Context) {
+	images, err := h.usecase.GetProductImages(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.ProductImageResp, 0, len(images))
+	for _, img := range images {
+		resp = append(resp, dto.ToProductImageDTO(&img))
+	}
+
+	setImageCacheHeaders(c, images, c.Param("id"))
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get images",
+		Data:    resp,
+	})
+}
+
+func (h *ProductImageV2Handler) DeleteImage(c *gin.Context) {
+	if err := h.usecase.DeleteProductImage(c.Request.Context(), c.Param("id"), c.Param("image_id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Image deleted",
+	})
+}
+
+func (h *ProductImageV2Handler) SetPrimary(c *gin.Context) {
+	if err := h.usecase.SetPrimary(c.Request.Context(), c.Param("id"), c.Param("image_id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Primary image set",
+	})
+}
+
+func (h *ProductImageV2Handler) SetVisibility(c *gin.Context) {
+	var req dto.ProductImageVisibilityReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := h.usecase.SetProductImageVisibility(c.Request.Context(), c.Param("id"), c.Param("image_id"), req.Visibility)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Image visibility updated",
+	})
+}
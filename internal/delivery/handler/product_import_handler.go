@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/importer"
+	"product-listing/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importContentTypes maps the content types of import files to their format.
+var importContentTypes = map[string]string{
+	"text/csv":             domain.ImportFormatCSV,
+	"application/x-ndjson": domain.ImportFormatNDJSON,
+	"application/jsonl":    domain.ImportFormatNDJSON,
+}
+
+type ProductImportHandler struct {
+	usecase  usecase.ProductImportUsecase
+	maxBytes int64
+}
+
+func NewProductImportHandler(u usecase.ProductImportUsecase, maxBytes int64) *ProductImportHandler {
+	return &ProductImportHandler{usecase: u, maxBytes: maxBytes}
+}
+
+// Import reads the file from the request body, as sent; the format query
+// parameter overrides its Content-Type. The job is queued before the response
+// and runs in the background.
+func (h *ProductImportHandler) Import(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		format = importContentTypes[mediaType]
+	}
+
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			writeError(c, domain.NewInvalidError("invalid_dry_run", "dry_run must be true or false"))
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes)
+	rows, err := importer.NewReader(body, format)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	job, err := h.usecase.Import(c.Request.Context(), rows, format, dryRun)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	// Jobs live next to the upload route, under /api/import/jobs
+	c.Header("Location", path.Join(path.Dir(c.Request.URL.Path), "jobs", job.ID.String()))
+	c.JSON(http.StatusAccepted, dto.Response{
+		Status:  http.StatusAccepted,
+		Message: "Import queued",
+		Data:    dto.ToImportJobDTO(job),
+	})
+}
+
+// writeImportError reports a file over the size limit as such, however far
+// into it reading stopped.
+func writeImportError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResp{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("import files are limited to %d bytes", tooLarge.Limit),
+			Code:    "request_too_large",
+		})
+		return
+	}
+	writeError(c, err)
+}
+
+func (h *ProductImportHandler) GetJobs(c *gin.Context) {
+	jobs, err := h.usecase.GetJobs(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.ImportJobResp, 0, len(jobs))
+	for _, j := range jobs {
+		resp = append(resp, dto.ToImportJobDTO(&j))
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get import jobs",
+		Data:    resp,
+	})
+}
+
+func (h *ProductImportHandler) GetJobByID(c *gin.Context) {
+	job, err := h.usecase.GetJobByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get import job",
+		Data:    dto.ToImportJobDTO(job),
+	})
+}
+
+// GetErrors is the error report of a job: its rejected rows in order of line.
+func (h *ProductImportHandler) GetErrors(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			writeError(c, domain.NewInvalidError("invalid_limit", "limit must be a positive number"))
+			return
+		}
+	}
+
+	rows, next, err := h.usecase.GetRejectedRows(c.Request.Context(), c.Param("id"), c.Query("cursor"), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.ImportRowErrorResp, 0, len(rows))
+	for _, r := range rows {
+		resp = append(resp, dto.ToImportRowErrorDTO(&r))
+	}
+
+	c.JSON(http.StatusOK, dto.CursorResponse{
+		Status:     http.StatusOK,
+		Message:    "Success get import errors",
+		Data:       resp,
+		NextCursor: next,
+	})
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProductV2Handler serves products under /api/v2. Unlike v1 it reports
+// usecase errors with their own status and code.
+type ProductV2Handler struct {
+	usecase usecase.ProductUsecase
+}
+
+func NewProductV2Handler(u usecase.ProductUsecase) *ProductV2Handler {
+	return &ProductV2Handler{usecase: u}
+}
+
+func (h *ProductV2Handler) CreateProduct(c *gin.Context) {
+	input, ok := bindProductV2(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.usecase.CreateProduct(c.Request.Context(), input)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setLocation(c, product.ID.String())
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "Product created",
+		Data:    dto.ToProductV2DTO(product),
+	})
+}
+
+func (h *ProductV2Handler) GetProducts(c *gin.Context) {
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	total, err := h.usecase.GetProductCount(ctx)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	products, err := h.usecase.GetProducts(ctx, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if err := h.usecase.ExpandProducts(ctx, products, include); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result := make([]dto.ProductRespV2, 0, len(products))
+	for _, p := range products {
+		result = append(result, dto.ToProductV2DTO(&p))
+	}
+
+	setProductCacheHeaders(c, products, domain.ProductListSurrogateKey(requestStoreID(c)))
+	c.JSON(http.StatusOK, dto.PaginatedResponse{
+		Status:     http.StatusOK,
+		Message:    "Success get products",
+		Data:       result,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages(total, limit),
+	})
+}
+
+func (h *ProductV2Handler) GetProductByID(c *gin.Context) {
+	include, err := parseProductInclude(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	product, err := h.usecase.GetProductsById(ctx, c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	products := []domain.Product{*product}
+	if err := h.usecase.ExpandProducts(ctx, products, include); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setProductCacheHeaders(c, products)
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get product",
+		Data:    dto.ToProductV2DTO(&products[0]),
+	})
+}
+
+func (h *ProductV2Handler) UpdateProduct(c *gin.Context) {
+	input, ok := bindProductV2(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.usecase.UpdateProduct(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Product updated",
+		Data:    dto.ToProductV2DTO(product),
+	})
+}
+
+func (h *ProductV2Handler) DeleteProduct(c *gin.Context) {
+	if err := h.usecase.DeleteProduct(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Product deleted",
+	})
+}
+
+// bindProductV2 reads the request body into a product input, writing a 400
+// response and returning false when it is malformed.
+func bindProductV2(c *gin.Context) (domain.ProductInput, bool) {
+	var req dto.ProductReqV2
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: "invalid request body",
+		})
+		return domain.ProductInput{}, false
+	}
+
+	categoryIDs := make([]uuid.UUID, 0, len(req.CategoryIDs))
+	for _, id := range req.CategoryIDs {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResp{
+				Status:  http.StatusBadRequest,
+				Message: "invalid category_id: " + id,
+			})
+			return domain.ProductInput{}, false
+		}
+		categoryIDs = append(categoryIDs, uid)
+	}
+
+	return domain.ProductInput{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		CategoryIDs: categoryIDs,
+		Price:       req.Price,
+	}, true
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"product-listing/internal/domain"
+	"product-listing/internal/sitemap"
+	"product-listing/internal/storefront"
+	"product-listing/internal/usecase"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapFile matches the file names of sitemaps, "products-2.xml" or
+// "products-2.xml.gz".
+var sitemapFile = regexp.MustCompile(`^([a-z]+)-([0-9]+)\.xml(\.gz)?$`)
+
+type SitemapHandler struct {
+	usecase usecase.SitemapUsecase
+	links   storefront.Links
+	maxAge  time.Duration
+}
+
+// NewSitemapHandler serves sitemaps that are reached through the storefront,
+// at the base URL of links.
+func NewSitemapHandler(u usecase.SitemapUsecase, links storefront.Links, maxAge time.Duration) *SitemapHandler {
+	return &SitemapHandler{usecase: u, links: links, maxAge: maxAge}
+}
+
+// Index lists the sitemaps of the store. Requested as sitemap.xml.gz, it
+// points at gzipped sitemaps too.
+func (h *SitemapHandler) Index(c *gin.Context) {
+	ctx := c.Request.Context()
+	pages, err := h.usecase.Index(ctx)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	gzipped := strings.HasSuffix(c.Request.URL.Path, ".gz")
+	ext := ".xml"
+	if gzipped {
+		ext += ".gz"
+	}
+
+	home := h.links.Home(domain.StoreFromContext(ctx))
+	sitemaps := make([]sitemap.Sitemap, 0, len(pages))
+	for _, p := range pages {
+		sitemaps = append(sitemaps, sitemap.Sitemap{
+			Loc:          fmt.Sprintf("%s/sitemaps/%s-%d%s", home, p.Kind, p.Number, ext),
+			LastModified: p.LastModified,
+		})
+	}
+
+	h.write(c, gzipped, func(w io.Writer) error {
+		return sitemap.WriteIndex(w, sitemaps)
+	})
+}
+
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	match := sitemapFile.FindStringSubmatch(c.Param("file"))
+	if match == nil {
+		writeError(c, domain.NewNotFoundError("sitemap_not_found", "sitemap not found"))
+		return
+	}
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		writeError(c, domain.NewNotFoundError("sitemap_not_found", "sitemap not found"))
+		return
+	}
+
+	urls, err := h.usecase.Sitemap(c.Request.Context(), match[1], number)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	h.write(c, match[3] != "", func(w io.Writer) error {
+		return sitemap.WriteURLSet(w, urls)
+	})
+}
+
+// write sends a sitemap anyone may cache for maxAge. A .gz file is gzipped
+// itself, plain XML is compressed in transit for clients that accept gzip.
+func (h *SitemapHandler) write(c *gin.Context, gzipped bool, body func(w io.Writer) error) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	c.Header("Vary", "X-Store, Accept-Encoding")
+
+	compress := gzipped || acceptsGzip(c.GetHeader("Accept-Encoding"))
+	switch {
+	case gzipped:
+		c.Header("Content-Type", "application/gzip")
+	case compress:
+		c.Header("Content-Type", "application/xml; charset=utf-8")
+		c.Header("Content-Encoding", "gzip")
+	default:
+		c.Header("Content-Type", "application/xml; charset=utf-8")
+	}
+	c.Status(http.StatusOK)
+
+	var w io.Writer = c.Writer
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(c.Writer)
+		w = zw
+	}
+
+	err := body(w)
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, which
+// it does unless its weight is 0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		weight, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		q, err := strconv.ParseFloat(weight, 64)
+		return err == nil && q > 0
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StoreHandler struct {
+	usecase usecase.StoreUsecase
+}
+
+func NewStoreHandler(u usecase.StoreUsecase) *StoreHandler {
+	return &StoreHandler{usecase: u}
+}
+
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	var req dto.StoreReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	store, err := h.usecase.CreateStore(c.Request.Context(), domain.StoreInput{
+		Slug: req.Slug,
+		Name: req.Name,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "Store created",
+		Data:    dto.ToStoreDTO(store),
+	})
+}
+
+func (h *StoreHandler) GetStores(c *gin.Context) {
+	stores, err := h.usecase.GetStores(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.StoreResp, 0, len(stores))
+	for _, s := range stores {
+		resp = append(resp, dto.ToStoreDTO(&s))
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get stores",
+		Data:    resp,
+	})
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	usecase usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(u usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{usecase: u}
+}
+
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	input, ok := bindWebhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.usecase.CreateSubscription(c.Request.Context(), input)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	setLocation(c, subscription.ID.String())
+	c.JSON(http.StatusCreated, dto.Response{
+		Status:  http.StatusCreated,
+		Message: "Webhook created",
+		Data: dto.WebhookSubscriptionCreatedResp{
+			WebhookSubscriptionResp: dto.ToWebhookSubscriptionDTO(subscription),
+			Secret:                  subscription.Secret,
+		},
+	})
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.usecase.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.WebhookSubscriptionResp, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		resp = append(resp, dto.ToWebhookSubscriptionDTO(&s))
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get webhooks",
+		Data:    resp,
+	})
+}
+
+func (h *WebhookHandler) GetSubscriptionByID(c *gin.Context) {
+	subscription, err := h.usecase.GetSubscriptionByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Success get webhook",
+		Data:    dto.ToWebhookSubscriptionDTO(subscription),
+	})
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	input, ok := bindWebhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.usecase.UpdateSubscription(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Status:  http.StatusOK,
+		Message: "Webhook updated",
+		Data:    dto.ToWebhookSubscriptionDTO(subscription),
+	})
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.usecase.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResp{
+		Status:  http.StatusOK,
+		Message: "Webhook deleted",
+	})
+}
+
+// GetSubscriptionDeliveries is the delivery log of one subscription,
+// optionally narrowed to a status.
+func (h *WebhookHandler) GetSubscriptionDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, domain.NewInvalidError("invalid_id", "invalid webhook id: "+c.Param("id")))
+		return
+	}
+
+	h.getDeliveries(c, domain.WebhookDeliveryFilter{SubscriptionID: &id, Status: c.Query("status")})
+}
+
+// GetDeadLetters lists deliveries of every subscription that ran out of
+// attempts.
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	h.getDeliveries(c, domain.WebhookDeliveryFilter{Status: domain.WebhookDeliveryDead})
+}
+
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	if err := h.usecase.RetryDelivery(c.Request.Context(), c.Param("delivery_id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResp{
+		Status:  http.StatusAccepted,
+		Message: "Delivery queued for retry",
+	})
+}
+
+func (h *WebhookHandler) getDeliveries(c *gin.Context, filter domain.WebhookDeliveryFilter) {
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit < 1 {
+			writeError(c, domain.NewInvalidError("invalid_limit", "limit must be a positive number"))
+			return
+		}
+	}
+
+	deliveries, next, err := h.usecase.GetDeliveries(c.Request.Context(), filter, c.Query("cursor"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]dto.WebhookDeliveryResp, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, dto.ToWebhookDeliveryDTO(&d))
+	}
+
+	c.JSON(http.StatusOK, dto.CursorResponse{
+		Status:     http.StatusOK,
+		Message:    "Success get webhook deliveries",
+		Data:       resp,
+		NextCursor: next,
+	})
+}
+
+func bindWebhookSubscription(c *gin.Context) (domain.WebhookSubscriptionInput, bool) {
+	var req dto.WebhookSubscriptionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResp{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return domain.WebhookSubscriptionInput{}, false
+	}
+
+	input := domain.WebhookSubscriptionInput{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+		Active: true,
+	}
+	if req.Active != nil {
+		input.Active = *req.Active
+	}
+	return input, true
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticate resolves the caller from an X-API-Key header or an
+// "Authorization: Bearer <jwt>" header. Requests without credentials pass
+// through anonymously; invalid credentials are rejected.
+func Authenticate(u usecase.AuthUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var principal *domain.Principal
+		var err error
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			principal, err = u.AuthenticateAPIKey(ctx, key)
+		} else if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			principal, err = u.AuthenticateToken(ctx, strings.TrimSpace(token))
+		}
+
+		if err != nil {
+			resp := dto.ErrorResp{
+				Status:  http.StatusInternalServerError,
+				Message: err.Error(),
+			}
+			var domainErr *domain.Error
+			if errors.As(err, &domainErr) {
+				resp.Status = http.StatusUnauthorized
+				resp.Code = domainErr.Code
+			}
+			c.AbortWithStatusJSON(resp.Status, resp)
+			return
+		}
+
+		if principal != nil {
+			c.Request = c.Request.WithContext(domain.ContextWithPrincipal(ctx, principal))
+		}
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose caller does not have at least role.
+func RequireRole(role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := domain.PrincipalFromContext(c.Request.Context())
+		if principal == nil {
+			c.Header("WWW-Authenticate", `Bearer realm="product-listing"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResp{
+				Status:  http.StatusUnauthorized,
+				Message: "authentication required",
+				Code:    "unauthenticated",
+			})
+			return
+		}
+
+		if !principal.Role.Allows(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResp{
+				Status:  http.StatusForbidden,
+				Message: string(role) + " role required",
+				Code:    "insufficient_role",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// apiKeys holds active keys by the hash of their secret.
+type apiKeys struct {
+	domain.APIKeyRepository
+	keys map[string]domain.APIKey
+}
+
+func (r apiKeys) FetchActiveByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	if key, ok := r.keys[keyHash]; ok {
+		return &key, nil
+	}
+	return nil, nil
+}
+
+func (r apiKeys) Touch(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+// tokens accepts tokens named after the principal they stand for.
+type tokens map[string]domain.Principal
+
+func (v tokens) Verify(token string) (*domain.Principal, error) {
+	if principal, ok := v[token]; ok {
+		return &principal, nil
+	}
+	return nil, errors.New("token is malformed")
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storeID := uuid.New()
+	editorID := uuid.New()
+	keys := apiKeys{keys: map[string]domain.APIKey{
+		hashKey("pl_editor"): {ID: editorID, Role: domain.RoleEditor, StoreID: &storeID},
+	}}
+	verifier := tokens{"viewer-token": {Subject: "alice", Role: domain.RoleViewer, Method: "jwt", Store: "acme"}}
+
+	tests := []struct {
+		name      string
+		bootstrap string
+		verifier  domain.TokenVerifier
+		header    string
+		value     string
+		role      domain.Role
+		status    int
+		code      string
+		want      *domain.Principal
+	}{
+		{"anonymous", "", verifier, "", "", domain.RoleViewer, http.StatusUnauthorized, "unauthenticated", nil},
+		{"bootstrap key", "pl_bootstrap", verifier, "X-API-Key", "pl_bootstrap", domain.RoleAdmin, http.StatusOK, "",
+			&domain.Principal{Subject: "bootstrap", Role: domain.RoleAdmin, Method: "api_key"}},
+		{"bootstrap key when unset", "", verifier, "X-API-Key", "pl_bootstrap", domain.RoleViewer, http.StatusUnauthorized, "invalid_api_key", nil},
+		{"wrong bootstrap key", "pl_bootstrap", verifier, "X-API-Key", "pl_bootstrap2", domain.RoleViewer, http.StatusUnauthorized, "invalid_api_key", nil},
+		{"stored key bound to a store", "pl_bootstrap", verifier, "X-API-Key", "pl_editor", domain.RoleEditor, http.StatusOK, "",
+			&domain.Principal{Subject: editorID.String(), Role: domain.RoleEditor, Method: "api_key", Store: storeID.String()}},
+		{"stored key below the role", "", verifier, "X-API-Key", "pl_editor", domain.RoleAdmin, http.StatusForbidden, "insufficient_role", nil},
+		{"bearer token", "", verifier, "Authorization", "Bearer viewer-token", domain.RoleViewer, http.StatusOK, "",
+			&domain.Principal{Subject: "alice", Role: domain.RoleViewer, Method: "jwt", Store: "acme"}},
+		{"invalid bearer token", "", verifier, "Authorization", "Bearer forged", domain.RoleViewer, http.StatusUnauthorized, "invalid_token", nil},
+		{"bearer tokens disabled", "", nil, "Authorization", "Bearer viewer-token", domain.RoleViewer, http.StatusUnauthorized, "jwt_disabled", nil},
+		{"other scheme", "", verifier, "Authorization", "Basic YWxpY2U6c2VjcmV0", domain.RoleViewer, http.StatusUnauthorized, "unauthenticated", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := usecase.NewAuthUsecase(keys, nil, tt.verifier, tt.bootstrap)
+
+			var got *domain.Principal
+			engine := gin.New()
+			engine.GET("/", Authenticate(auth), RequireRole(tt.role), func(c *gin.Context) {
+				got = domain.PrincipalFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.value != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.code != "" {
+				var body struct{ Code string }
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != tt.code {
+					t.Errorf("error code = %q, want %q", body.Code, tt.code)
+				}
+			}
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("principal = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
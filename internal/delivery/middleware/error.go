@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errorKindStatus = map[domain.ErrorKind]int{
+	domain.ErrorKindInvalid:       http.StatusBadRequest,
+	domain.ErrorKindNotFound:      http.StatusNotFound,
+	domain.ErrorKindConflict:      http.StatusConflict,
+	domain.ErrorKindForbidden:     http.StatusForbidden,
+	domain.ErrorKindUnauthorized:  http.StatusUnauthorized,
+	domain.ErrorKindUnprocessable: http.StatusUnprocessableEntity,
+}
+
+// abortWithError stops the chain with a response for err, keeping the code
+// of business rule violations.
+func abortWithError(c *gin.Context, err error) {
+	resp := dto.ErrorResp{
+		Status:  http.StatusInternalServerError,
+		Message: err.Error(),
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		if status, ok := errorKindStatus[domainErr.Kind]; ok {
+			resp.Status = status
+		}
+		resp.Code = domainErr.Code
+	}
+
+	c.AbortWithStatusJSON(resp.Status, resp)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPCache sets the Cache-Control of reads. Successful responses that carry
+// a Surrogate-Key may be cached by browsers for maxAge and by shared caches
+// for sharedMaxAge, when the caller is anonymous. Callers with credentials
+// get them revalidated and everything else is not stored. A handler that set
+// Cache-Control itself keeps it. Responses vary on the headers that pick the
+// caller and store. It must run after Authenticate.
+func HTTPCache(maxAge, sharedMaxAge time.Duration) gin.HandlerFunc {
+	public := fmt.Sprintf("public, max-age=%d, s-maxage=%d", int(maxAge.Seconds()), int(sharedMaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Authorization, X-API-Key, X-Store")
+		anonymous := domain.PrincipalFromContext(c.Request.Context()) == nil
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, policy: func(w gin.ResponseWriter) string {
+			switch {
+			case w.Status() != http.StatusOK || w.Header().Get("Surrogate-Key") == "":
+				return "no-store"
+			case anonymous:
+				return public
+			default:
+				return "private, no-cache"
+			}
+		}}
+		c.Next()
+	}
+}
+
+// cacheControlWriter sets Cache-Control from the status and headers of the
+// response just before they are sent.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	policy  func(w gin.ResponseWriter) string
+	applied bool
+}
+
+func (w *cacheControlWriter) apply() {
+	if w.applied {
+		return
+	}
+	w.applied = true
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", w.policy(w.ResponseWriter))
+	}
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.apply()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.apply()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheControlWriter) Flush() {
+	w.apply()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize caps the request bodies hashed for comparison
+	maxIdempotentBodySize = 10 << 20
+	// idempotencyFinishTimeout bounds storing the response once the client
+	// may have gone away
+	idempotencyFinishTimeout = 5 * time.Second
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to
+// retry. The first response is stored and replayed to retries with the same
+// method, path and body, with its Location header; reusing the key for
+// anything else is rejected.
+// Server errors are not stored, so a request that failed that way can be
+// retried for real. Keys are scoped to the caller and store, so it must run
+// after Authenticate and ResolveStore. Anonymous callers cannot create
+// anything and are passed through.
+func Idempotency(u usecase.IdempotencyUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		principal := domain.PrincipalFromContext(c.Request.Context())
+		if c.Request.Method != http.MethodPost || key == "" || principal == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResp{
+				Status:  http.StatusBadRequest,
+				Message: "Idempotency-Key must be at most 255 characters",
+				Code:    "invalid_idempotency_key",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResp{
+				Status:  http.StatusRequestEntityTooLarge,
+				Message: "request body too large for an idempotent request",
+				Code:    "request_too_large",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := principal.Method + ":" + principal.Subject
+		if store := domain.StoreFromContext(ctx); store != nil {
+			scope += "@" + store.ID.String()
+		}
+		stored, claim, err := u.Begin(ctx, scope, key, requestHash(c.Request, body))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			if stored.Location != "" {
+				c.Header("Location", stored.Location)
+			}
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// Runs after a panic too, so the key does not stay claimed
+			finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyFinishTimeout)
+			defer cancel()
+
+			status := recorder.Status()
+			if completed && status < http.StatusInternalServerError {
+				err = u.Complete(finishCtx, scope, key, claim, domain.IdempotentResponse{
+					StatusCode:  status,
+					ContentType: recorder.Header().Get("Content-Type"),
+					Location:    recorder.Header().Get("Location"),
+					Body:        recorder.body.Bytes(),
+				})
+			} else {
+				err = u.Release(finishCtx, scope, key, claim)
+			}
+			if err != nil {
+				_ = c.Error(err)
+			}
+		}()
+
+		c.Next()
+		completed = true
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body on its way out.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
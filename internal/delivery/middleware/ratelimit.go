@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit applies separate token buckets to reads and writes, per caller.
+// Authenticated callers are keyed by their credentials, anonymous ones by IP,
+// so clients behind one NAT share a bucket until they authenticate. It must
+// run after Authenticate. A zero Limit leaves that kind of request unlimited,
+// and a failing limiter lets requests through rather than taking the API
+// down with it. Routes in readOnly count as reads whatever their method, for
+// endpoints such as GraphQL that read through POST.
+func RateLimit(limiter domain.RateLimiter, read, write domain.RateLimit, readOnly ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, class := write, "write"
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			limit, class = read, "read"
+		}
+		if slices.Contains(readOnly, c.FullPath()) {
+			limit, class = read, "read"
+		}
+		if limit.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := class + ":ip:" + c.ClientIP()
+		if principal := domain.PrincipalFromContext(ctx); principal != nil {
+			key = class + ":" + principal.Method + ":" + principal.Subject
+		}
+
+		res, err := limiter.Allow(ctx, key, limit)
+		if err != nil {
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Limit)+";w="+strconv.Itoa(ceilSeconds(limit.Window)))
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(res.RetryAfter))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResp{
+				Status:  http.StatusTooManyRequests,
+				Message: "rate limit exceeded, retry later",
+				Code:    "rate_limited",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
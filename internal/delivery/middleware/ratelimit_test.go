@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cannedLimiter answers every request with res and records the bucket keys.
+type cannedLimiter struct {
+	res  domain.RateLimitResult
+	err  error
+	keys []string
+}
+
+func (l *cannedLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	l.keys = append(l.keys, key+"/"+limit.Window.String())
+	return l.res, l.err
+}
+
+func TestRateLimitClasses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	read := domain.RateLimit{Limit: 100, Window: time.Minute}
+	write := domain.RateLimit{Limit: 10, Window: time.Hour}
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		principal *domain.Principal
+		write     domain.RateLimit
+		want      string
+	}{
+		{"anonymous read", http.MethodGet, "/api/products", nil, write, "read:ip:192.0.2.1/1m0s"},
+		{"head is a read", http.MethodHead, "/api/products", nil, write, "read:ip:192.0.2.1/1m0s"},
+		{"options is a read", http.MethodOptions, "/api/products", nil, write, "read:ip:192.0.2.1/1m0s"},
+		{"anonymous write", http.MethodPost, "/api/products", nil, write, "write:ip:192.0.2.1/1h0m0s"},
+		{"graphql post is a read", http.MethodPost, "/api/graphql", nil, write, "read:ip:192.0.2.1/1m0s"},
+		{"authenticated read", http.MethodGet, "/api/products", &domain.Principal{Subject: "alice", Method: "jwt"}, write, "read:jwt:alice/1m0s"},
+		{"authenticated write", http.MethodDelete, "/api/products", &domain.Principal{Subject: "key-1", Method: "api_key"}, write, "write:api_key:key-1/1h0m0s"},
+		{"writes unlimited", http.MethodPost, "/api/products", nil, domain.RateLimit{Window: time.Hour}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &cannedLimiter{res: domain.RateLimitResult{Allowed: true}}
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), tt.principal))
+				}
+			}, RateLimit(limiter, read, tt.write, "/api/graphql"))
+			engine.Handle(tt.method, tt.path, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			got := ""
+			if len(limiter.keys) > 0 {
+				got = limiter.keys[0]
+			}
+			if got != tt.want {
+				t.Errorf("bucket = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limit := domain.RateLimit{Limit: 100, Window: 90 * time.Second}
+
+	tests := []struct {
+		name    string
+		res     domain.RateLimitResult
+		err     error
+		status  int
+		headers map[string]string
+	}{
+		{"allowed", domain.RateLimitResult{Allowed: true, Remaining: 42, Reset: 1500 * time.Millisecond}, nil, http.StatusOK, map[string]string{
+			"RateLimit-Policy":    "100;w=90",
+			"RateLimit-Limit":     "100",
+			"RateLimit-Remaining": "42",
+			"RateLimit-Reset":     "2",
+			"Retry-After":         "",
+		}},
+		{"refused", domain.RateLimitResult{Reset: 90 * time.Second, RetryAfter: 2100 * time.Millisecond}, nil, http.StatusTooManyRequests, map[string]string{
+			"RateLimit-Remaining": "0",
+			"RateLimit-Reset":     "90",
+			"Retry-After":         "3",
+		}},
+		{"refused for under a second", domain.RateLimitResult{Reset: time.Second, RetryAfter: time.Millisecond}, nil, http.StatusTooManyRequests, map[string]string{
+			"Retry-After": "1",
+		}},
+		{"limiter down", domain.RateLimitResult{}, errors.New("connection refused"), http.StatusOK, map[string]string{
+			"RateLimit-Limit": "",
+			"Retry-After":     "",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(RateLimit(&cannedLimiter{res: tt.res, err: tt.err}, limit, limit))
+			engine.GET("/api/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			for name, want := range tt.headers {
+				if got := rec.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
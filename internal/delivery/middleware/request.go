@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"product-listing/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds client supplied request IDs.
+const maxRequestIDLength = 128
+
+// RequestContext tags the request with an ID and the client IP for the audit
+// log. A client supplied X-Request-ID is kept so calls can be traced across
+// services, and the ID is echoed back in the response.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+		c.Header("X-Request-ID", id)
+
+		meta := domain.RequestMeta{ID: id, IP: c.ClientIP()}
+		c.Request = c.Request.WithContext(domain.ContextWithRequestMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
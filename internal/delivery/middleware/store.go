@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveStore binds the request to a store. The store comes from the
+// caller's credentials, the X-Store header or the subdomain of baseDomain, in
+// that order, falling back to the configured default store. It must run
+// after Authenticate.
+func ResolveStore(u usecase.StoreUsecase, baseDomain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		requested := c.GetHeader("X-Store")
+		if requested == "" {
+			requested = subdomain(c.Request.Host, baseDomain)
+		}
+
+		store, err := u.ResolveStore(ctx, domain.PrincipalFromContext(ctx), requested)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithStore(ctx, store))
+		c.Next()
+	}
+}
+
+// RequireUnbound rejects callers whose credentials are bound to a single
+// store, keeping cross-store administration to platform operators.
+func RequireUnbound() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := domain.PrincipalFromContext(c.Request.Context())
+		if principal != nil && principal.Store != "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResp{
+				Status:  http.StatusForbidden,
+				Message: "credentials bound to a store cannot manage other stores",
+				Code:    "store_bound_credentials",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// subdomain returns "acme" for host "acme.shop.example.com" and base domain
+// "shop.example.com", and "" when host is not directly below the base domain.
+func subdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
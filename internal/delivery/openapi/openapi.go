@@ -0,0 +1,12 @@
+// Package openapi holds the hand-maintained OpenAPI document of the API and
+// the page that renders it. Update openapi.json together with the routes and
+// DTOs it describes; the router tests fail when they drift apart.
+package openapi
+
+import _ "embed"
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var DocsPage []byte
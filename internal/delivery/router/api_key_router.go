@@ -0,0 +1,16 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func APIKeyRoutes(r *gin.RouterGroup, h *handler.APIKeyHandler, requireAdmin gin.HandlerFunc) {
+	route := r.Group("/admin/api-keys", requireAdmin)
+	{
+		route.GET("", h.GetAPIKeys)
+		route.POST("", h.CreateAPIKey)
+		route.DELETE("/:id", h.RevokeAPIKey)
+	}
+}
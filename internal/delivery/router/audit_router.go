@@ -0,0 +1,15 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AuditRoutes(r *gin.RouterGroup, h *handler.AuditHandler, requireAdmin gin.HandlerFunc) {
+	route := r.Group("/audit", requireAdmin)
+	{
+		route.GET("", h.GetEntries)
+		route.GET("/export", h.Export)
+	}
+}
@@ -6,14 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CategoriesRoute(r *gin.RouterGroup, h *handler.CategoryHandler) {
+func CategoriesRoute(r *gin.RouterGroup, h *handler.CategoryHandler, requireEditor gin.HandlerFunc) {
 	router := r.Group("/category")
 	{
 		router.GET("", h.GetCategories)
 		router.GET("/:id", h.GetCategoryByID)
 		router.GET("/slug/:slug", h.GetCategoryBySlug)
-		router.POST("", h.CreateCategory)
-		router.PUT("/:id", h.UpdateCategory)
-		router.DELETE("/:id", h.DeleteCategory)
+		router.POST("", requireEditor, h.CreateCategory)
+		router.PUT("/:id", requireEditor, h.UpdateCategory)
+		router.DELETE("/:id", requireEditor, h.DeleteCategory)
 	}
 }
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CategoriesV2Routes(r *gin.RouterGroup, h *handler.CategoryV2Handler, requireEditor gin.HandlerFunc) {
+	route := r.Group("/categories")
+	{
+		route.GET("", h.GetCategories)
+		route.POST("", requireEditor, h.CreateCategory)
+		route.GET("/:id", h.GetCategoryByID)
+		route.PUT("/:id", requireEditor, h.UpdateCategory)
+		route.DELETE("/:id", requireEditor, h.DeleteCategory)
+		route.GET("/:id/products", h.GetCategoryProducts)
+		route.GET("/slug/:slug", h.GetCategoryBySlug)
+	}
+}
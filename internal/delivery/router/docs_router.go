@@ -0,0 +1,12 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DocsRoutes(r *gin.RouterGroup, h *handler.DocsHandler) {
+	r.GET("/openapi.json", h.GetSpec)
+	r.GET("/docs", h.GetDocs)
+}
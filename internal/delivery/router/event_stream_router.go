@@ -0,0 +1,11 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EventStreamRoutes(r *gin.RouterGroup, h *handler.EventStreamHandler, requireEditor gin.HandlerFunc) {
+	r.GET("/events/stream", requireEditor, h.Stream)
+}
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GraphQLRoutes(r *gin.RouterGroup, h *handler.GraphQLHandler) {
+	r.POST("/graphql", h.Query)
+}
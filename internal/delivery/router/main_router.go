@@ -2,32 +2,104 @@ package router
 
 import (
 	"product-listing/config"
+	"product-listing/internal/delivery/gql"
 	"product-listing/internal/delivery/handler"
-	"product-listing/internal/repository"
+	"product-listing/internal/delivery/middleware"
+	"product-listing/internal/domain"
+	"product-listing/internal/storage"
+	"product-listing/internal/storefront"
 	"product-listing/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(db *config.Database) *gin.Engine {
+// SetupRouter wires the API. Reads are open to anonymous callers, writes need
+// the editor role and API key and store management need an admin that is not
+// bound to a store. Catalog routes act on the store resolved per request.
+// The deprecated v1 routes, /api/v2 and /api/graphql share the usecases in u.
+// changes announces catalog changes from every replica. Event streams end
+// when shutdown is closed.
+func SetupRouter(cfg *config.Config, u *usecase.Usecases, limiter domain.RateLimiter, changes domain.ChangeBus, shutdown <-chan struct{}) *gin.Engine {
 	route := gin.Default()
+	route.Use(middleware.RequestContext())
 
 	api := route.Group("/api")
 
-	categoryRepo := repository.NewCategoryRepository(db)
-	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
-	categoryHandler := handler.NewCategoryHandler(categoryUsecase)
-	CategoriesRoute(api, categoryHandler)
+	api.Use(middleware.Authenticate(u.Auth))
+	api.Use(middleware.RateLimit(limiter,
+		domain.RateLimit{Limit: cfg.RateLimitRead, Window: cfg.RateLimitWindow},
+		domain.RateLimit{Limit: cfg.RateLimitWrite, Window: cfg.RateLimitWindow},
+		"/api/graphql",
+	))
+	requireEditor := middleware.RequireRole(domain.RoleEditor)
+	requireAdmin := middleware.RequireRole(domain.RoleAdmin)
 
-	productRepo := repository.NewProductRepository(db)
-	productUsecase := usecase.NewProductUsecase(productRepo)
-	productHandler := handler.NewProductHandler(productUsecase)
-	ProductRoutes(api, productHandler)
+	idempotency := middleware.Idempotency(u.Idempotency)
 
-	productImageRepo := repository.NewProductImageRepository(db)
-	productImageUsecase := usecase.NewProductImageUsecase(productImageRepo)
-	productImageHandler := handler.NewProductImageHandler(productImageUsecase)
-	ProductImageRoutes(api, productImageHandler)
+	resolveStore := middleware.ResolveStore(u.Store, cfg.StoreBaseDomain)
+
+	apiKeyHandler := handler.NewAPIKeyHandler(u.Auth)
+	storeHandler := handler.NewStoreHandler(u.Store)
+	auditHandler := handler.NewAuditHandler(u.Audit)
+	productImageHandler := handler.NewProductImageHandler(u.ProductImage)
+	httpCache := middleware.HTTPCache(cfg.HTTPCacheMaxAge, cfg.HTTPCacheSharedMaxAge)
+
+	// v1 keeps its original routes and JSON keys until its sunset
+	v1 := api.Group("", middleware.Deprecated(cfg.APIV1DeprecatedAt, cfg.APIV1Sunset, "/api/v2"))
+	{
+		admin := v1.Group("", middleware.RequireUnbound(), idempotency)
+		APIKeyRoutes(admin, apiKeyHandler, requireAdmin)
+		StoreRoutes(admin, storeHandler, requireAdmin)
+
+		catalog := v1.Group("", resolveStore, idempotency, httpCache)
+		AuditRoutes(catalog, auditHandler, requireAdmin)
+		CategoriesRoute(catalog, handler.NewCategoryHandler(u.Category), requireEditor)
+		ProductRoutes(catalog, handler.NewProductHandler(u.Product), requireEditor)
+		ProductImageRoutes(catalog, productImageHandler, requireEditor)
+	}
+
+	v2 := api.Group("/v2")
+	{
+		admin := v2.Group("", middleware.RequireUnbound(), idempotency)
+		APIKeyRoutes(admin, apiKeyHandler, requireAdmin)
+		StoreRoutes(admin, storeHandler, requireAdmin)
+
+		catalog := v2.Group("", resolveStore, idempotency, httpCache)
+		AuditRoutes(catalog, auditHandler, requireAdmin)
+		CategoriesV2Routes(catalog, handler.NewCategoryV2Handler(u.Category, u.Product), requireEditor)
+		ProductsV2Routes(catalog, handler.NewProductV2Handler(u.Product), handler.NewProductImageV2Handler(u.ProductImage), requireEditor)
+		ImagesV2Routes(catalog, productImageHandler, requireEditor)
+		WebhookRoutes(catalog, handler.NewWebhookHandler(u.Webhook), requireAdmin)
+	}
+
+	// GraphQL only reads, so it needs neither a version nor idempotency
+	graphQLServer := gql.NewServer(u.Category, u.Product, u.ProductImage, cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity)
+	GraphQLRoutes(api.Group("", resolveStore), handler.NewGraphQLHandler(graphQLServer))
+
+	// The change feed is read from the outbox of the resolved store
+	eventStreamHandler := handler.NewEventStreamHandler(u.ChangeFeed, changes, cfg.EventStreamPollInterval, cfg.EventStreamHeartbeat, shutdown)
+	EventStreamRoutes(api.Group("", resolveStore), eventStreamHandler, requireEditor)
+
+	// Imports are jobs of the resolved store, applied in the background
+	ProductImportRoutes(api.Group("", resolveStore, idempotency), handler.NewProductImportHandler(u.ProductImport, cfg.ImportMaxBytes), requireEditor)
+
+	ProductExportRoutes(api.Group("", resolveStore), handler.NewProductExportHandler(u.ProductExport), requireEditor)
+
+	// Feeds are kept up to date by the feed outbox publisher
+	ProductFeedRoutes(api.Group("", resolveStore), handler.NewProductFeedHandler(u.ProductFeed, cfg.FeedMaxAge))
+
+	// Sitemaps sit at the root, where the storefront proxies them from
+	links := storefront.Links{BaseURL: cfg.StorefrontBaseURL, ProductPath: cfg.StorefrontProductPath, CategoryPath: cfg.StorefrontCategoryPath}
+	SitemapRoutes(route.Group("", resolveStore), handler.NewSitemapHandler(u.Sitemap, links, cfg.SitemapMaxAge))
+
+	mediaHandler := handler.NewMediaHandler(u.ProductImage, storage.NewLocalStore(cfg.MediaStorageDir), cfg.MediaBaseURL, cfg.MediaOrigins)
+	MediaRoutes(&route.RouterGroup, mediaHandler)
+
+	docsHandler := handler.NewDocsHandler()
+	DocsRoutes(api, docsHandler)
+
+	metricsHandler := handler.NewMetricsHandler(u.Outbox)
+	MetricsRoutes(&route.RouterGroup, metricsHandler)
 
 	return route
 }
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MediaRoutes(r *gin.RouterGroup, h *handler.MediaHandler) {
+	route := r.Group("/media")
+	{
+		route.GET("/images/:id", h.ServeImage)
+	}
+}
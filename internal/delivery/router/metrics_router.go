@@ -0,0 +1,11 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MetricsRoutes(r *gin.RouterGroup, h *handler.MetricsHandler) {
+	r.GET("/metrics", h.GetMetrics)
+}
@@ -0,0 +1,269 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-listing/config"
+	"product-listing/internal/changebus"
+	"product-listing/internal/delivery/dto"
+	"product-listing/internal/delivery/openapi"
+	"product-listing/internal/ratelimit"
+	"product-listing/internal/usecase"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       any                `json:"type"`
+	Items      *schema            `json:"items"`
+	Properties map[string]*schema `json:"properties"`
+}
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// documentedSchemas maps each schema of the document to the DTO it describes.
+var documentedSchemas = map[string]any{
+	"Response":                       dto.Response{},
+	"PaginatedResponse":              dto.PaginatedResponse{},
+	"CursorResponse":                 dto.CursorResponse{},
+	"SuccessResp":                    dto.SuccessResp{},
+	"ErrorResp":                      dto.ErrorResp{},
+	"APIKeyReq":                      dto.APIKeyReq{},
+	"APIKeyResp":                     dto.APIKeyResp{},
+	"APIKeyCreatedResp":              dto.APIKeyCreatedResp{},
+	"StoreReq":                       dto.StoreReq{},
+	"StoreResp":                      dto.StoreResp{},
+	"AuditEntryResp":                 dto.AuditEntryResp{},
+	"CategoryReq":                    dto.CategoryReq{},
+	"CategoryResp":                   dto.CategoryResp{},
+	"ProductReq":                     dto.ProductReq{},
+	"ProductResp":                    dto.ProductResp{},
+	"ProductImageReq":                dto.ProductImageReq{},
+	"ProductImageVisibilityReq":      dto.ProductImageVisibilityReq{},
+	"ProductImageResp":               dto.ProductImageResp{},
+	"ImageOperationReq":              dto.ImageOperationReq{},
+	"ImageBatchReq":                  dto.ImageBatchReq{},
+	"ImageOperationResp":             dto.ImageOperationResp{},
+	"CategoryRespV2":                 dto.CategoryRespV2{},
+	"ProductReqV2":                   dto.ProductReqV2{},
+	"ProductRespV2":                  dto.ProductRespV2{},
+	"ProductImageReqV2":              dto.ProductImageReqV2{},
+	"GraphQLReq":                     dto.GraphQLReq{},
+	"WebhookSubscriptionReq":         dto.WebhookSubscriptionReq{},
+	"WebhookSubscriptionResp":        dto.WebhookSubscriptionResp{},
+	"WebhookSubscriptionCreatedResp": dto.WebhookSubscriptionCreatedResp{},
+	"WebhookDeliveryResp":            dto.WebhookDeliveryResp{},
+	"ImportJobResp":                  dto.ImportJobResp{},
+	"ImportRowErrorResp":             dto.ImportRowErrorResp{},
+}
+
+var (
+	// pathParam matches gin path parameters to rewrite them as {name}
+	pathParam = regexp.MustCompile(`[:*]([^/]+)`)
+	// operationMethods are the keys of a path item that are operations
+	operationMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+)
+
+func loadDocument(t *testing.T) document {
+	t.Helper()
+
+	var doc document
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := SetupRouter(&config.Config{}, &usecase.Usecases{}, ratelimit.NewMemoryLimiter(), changebus.New(), nil)
+	doc := loadDocument(t)
+
+	registered := map[string]bool{}
+	for _, route := range engine.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		registered[route.Method+" "+path] = true
+
+		if _, ok := doc.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is registered but not documented in openapi.json", route.Method, path)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			method = strings.ToUpper(method)
+			if !slices.Contains(operationMethods, method) {
+				continue
+			}
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is documented in openapi.json but not registered", method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPISchemasMatchDTOs(t *testing.T) {
+	doc := loadDocument(t)
+
+	for name, value := range documentedSchemas {
+		s, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing from openapi.json", name)
+			continue
+		}
+
+		fields := jsonFields(reflect.TypeOf(value))
+		for field, f := range fields {
+			prop, ok := s.Properties[field]
+			if !ok {
+				t.Errorf("%s.%s is not in schema %s", reflect.TypeOf(value).Name(), field, name)
+				continue
+			}
+			checkType(t, name+"."+field, f.typ, !f.omitEmpty, prop)
+		}
+
+		for prop := range s.Properties {
+			if _, ok := fields[prop]; !ok {
+				t.Errorf("schema %s has property %s that %s does not have", name, prop, reflect.TypeOf(value).Name())
+			}
+		}
+	}
+
+	var undocumented []string
+	for name := range doc.Components.Schemas {
+		if _, ok := documentedSchemas[name]; !ok {
+			undocumented = append(undocumented, name)
+		}
+	}
+	sort.Strings(undocumented)
+	for _, name := range undocumented {
+		t.Errorf("schema %s is not checked against a DTO, add it to documentedSchemas", name)
+	}
+}
+
+type jsonField struct {
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// jsonFields lists the JSON properties of a struct the way encoding/json
+// marshals it, including fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = jsonField{
+			typ:       f.Type,
+			omitEmpty: strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero"),
+		}
+	}
+	return fields
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// checkType reports a property whose schema type cannot hold the values the
+// Go type marshals to. A pointer or map that is always marshaled must allow
+// null.
+func checkType(t *testing.T, where string, typ reflect.Type, always bool, prop *schema) {
+	t.Helper()
+
+	types := schemaTypes(prop)
+	if typ.Kind() == reflect.Pointer {
+		if always && !slices.Contains(types, "null") {
+			t.Errorf("%s can be null but the schema does not allow it", where)
+		}
+		typ = typ.Elem()
+	}
+
+	var want string
+	switch {
+	case typ == timeType:
+		want = "string"
+	case typ == rawMessageType, typ.Kind() == reflect.Interface:
+		return
+	case typ.Kind() == reflect.Struct:
+		if prop.Ref != "#/components/schemas/"+typ.Name() {
+			t.Errorf("%s should reference %s, got %q", where, typ.Name(), prop.Ref)
+		}
+		return
+	case typ.Kind() == reflect.Slice:
+		if !slices.Contains(types, "array") || prop.Items == nil {
+			t.Errorf("%s should be an array, got %v", where, types)
+			return
+		}
+		checkType(t, where+"[]", typ.Elem(), true, prop.Items)
+		return
+	case typ.Kind() == reflect.Map:
+		if always && !slices.Contains(types, "null") {
+			t.Errorf("%s can be null but the schema does not allow it", where)
+		}
+		want = "object"
+	case typ.Kind() == reflect.String:
+		want = "string"
+	case typ.Kind() == reflect.Bool:
+		want = "boolean"
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		want = "integer"
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		want = "number"
+	default:
+		t.Errorf("%s has Go type %s that the test does not know how to check", where, typ)
+		return
+	}
+
+	if !slices.Contains(types, want) {
+		t.Errorf("%s should be of type %s, got %v", where, want, types)
+	}
+}
+
+func schemaTypes(s *schema) []string {
+	switch v := s.Type.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ProductExportRoutes(r *gin.RouterGroup, h *handler.ProductExportHandler, requireEditor gin.HandlerFunc) {
+	r.GET("/export/products", requireEditor, h.Export)
+}
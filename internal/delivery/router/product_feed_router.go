@@ -0,0 +1,17 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductFeedRoutes are public, shopping engines fetch feeds without
+// credentials.
+func ProductFeedRoutes(r *gin.RouterGroup, h *handler.ProductFeedHandler) {
+	route := r.Group("/feeds")
+	{
+		route.GET("/google.xml", h.GoogleXML)
+		route.GET("/google.tsv", h.GoogleTSV)
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ProductImageRoutes(r *gin.RouterGroup, h *handler.ProductImageHandler) {
+func ProductImageRoutes(r *gin.RouterGroup, h *handler.ProductImageHandler, requireEditor gin.HandlerFunc) {
 	route := r.Group("/product-images")
 	{
-		route.POST("", h.AddImage)
+		route.POST("", requireEditor, h.AddImage)
+		route.POST("/batch", requireEditor, h.ApplyBatch)
 		route.GET("/product/:product_id", h.GetProductImages)
-		route.DELETE("/:id", h.DeleteImage)
-		route.PUT("/primary/:product_id/:image_id", h.SetPrimary)
+		route.DELETE("/:id", requireEditor, h.DeleteImage)
+		route.PUT("/primary/:product_id/:image_id", requireEditor, h.SetPrimary)
+		route.PUT("/:id/visibility", requireEditor, h.SetVisibility)
 	}
 }
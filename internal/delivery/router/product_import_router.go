@@ -0,0 +1,17 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ProductImportRoutes(r *gin.RouterGroup, h *handler.ProductImportHandler, requireEditor gin.HandlerFunc) {
+	route := r.Group("/import", requireEditor)
+	{
+		route.POST("/products", h.Import)
+		route.GET("/jobs", h.GetJobs)
+		route.GET("/jobs/:id", h.GetJobByID)
+		route.GET("/jobs/:id/errors", h.GetErrors)
+	}
+}
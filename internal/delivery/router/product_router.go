@@ -6,15 +6,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ProductRoutes(r *gin.RouterGroup, h *handler.ProductHandler) {
+func ProductRoutes(r *gin.RouterGroup, h *handler.ProductHandler, requireEditor gin.HandlerFunc) {
 	route := r.Group("/products")
 	{
 		route.GET("/", h.GetProducts)
 		route.GET("/:id", h.GetProductById)
 		route.GET("/category/:category_id", h.GetProductByCategory)
-		route.POST("/", h.CreateProduct)
-		route.PUT("/:id", h.UpdateProduct)
-		route.DELETE("/:id", h.DeleteProduct)
+		route.POST("/", requireEditor, h.CreateProduct)
+		route.PUT("/:id", requireEditor, h.UpdateProduct)
+		route.DELETE("/:id", requireEditor, h.DeleteProduct)
 	}
 
 }
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ProductsV2Routes(r *gin.RouterGroup, h *handler.ProductV2Handler, images *handler.ProductImageV2Handler, requireEditor gin.HandlerFunc) {
+	route := r.Group("/products")
+	{
+		route.GET("", h.GetProducts)
+		route.POST("", requireEditor, h.CreateProduct)
+		route.GET("/:id", h.GetProductByID)
+		route.PUT("/:id", requireEditor, h.UpdateProduct)
+		route.DELETE("/:id", requireEditor, h.DeleteProduct)
+		route.GET("/:id/images", images.GetImages)
+		route.POST("/:id/images", requireEditor, images.AddImage)
+		route.DELETE("/:id/images/:image_id", requireEditor, images.DeleteImage)
+		route.PUT("/:id/images/:image_id/primary", requireEditor, images.SetPrimary)
+		route.PUT("/:id/images/:image_id/visibility", requireEditor, images.SetVisibility)
+	}
+}
+
+// ImagesV2Routes holds image operations that span products.
+func ImagesV2Routes(r *gin.RouterGroup, h *handler.ProductImageHandler, requireEditor gin.HandlerFunc) {
+	route := r.Group("/images")
+	{
+		route.POST("/batch", requireEditor, h.ApplyBatch)
+	}
+}
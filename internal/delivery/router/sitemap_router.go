@@ -0,0 +1,13 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SitemapRoutes(r *gin.RouterGroup, h *handler.SitemapHandler) {
+	r.GET("/sitemap.xml", h.Index)
+	r.GET("/sitemap.xml.gz", h.Index)
+	r.GET("/sitemaps/:file", h.Sitemap)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StoreRoutes(r *gin.RouterGroup, h *handler.StoreHandler, requireAdmin gin.HandlerFunc) {
+	route := r.Group("/admin/stores", requireAdmin)
+	{
+		route.GET("", h.GetStores)
+		route.POST("", h.CreateStore)
+	}
+}
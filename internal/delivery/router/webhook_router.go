@@ -0,0 +1,21 @@
+package router
+
+import (
+	"product-listing/internal/delivery/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WebhookRoutes(r *gin.RouterGroup, h *handler.WebhookHandler, requireAdmin gin.HandlerFunc) {
+	route := r.Group("/webhooks", requireAdmin)
+	{
+		route.GET("", h.GetSubscriptions)
+		route.POST("", h.CreateSubscription)
+		route.GET("/dead-letters", h.GetDeadLetters)
+		route.POST("/deliveries/:delivery_id/retry", h.RetryDelivery)
+		route.GET("/:id", h.GetSubscriptionByID)
+		route.PUT("/:id", h.UpdateSubscription)
+		route.DELETE("/:id", h.DeleteSubscription)
+		route.GET("/:id/deliveries", h.GetSubscriptionDeliveries)
+	}
+}
@@ -0,0 +1,960 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: catalog/v1/product_image.proto
+
+package catalogpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProductImage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Url       string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	IsPrimary bool                   `protobuf:"varint,4,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	// "public" or "private"
+	Visibility    string                 `protobuf:"bytes,5,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	Position      int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductImage) Reset() {
+	*x = ProductImage{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductImage) ProtoMessage() {}
+
+func (x *ProductImage) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductImage.ProtoReflect.Descriptor instead.
+func (*ProductImage) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProductImage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductImage) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductImage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ProductImage) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *ProductImage) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *ProductImage) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ProductImage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type AddImageRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Url       string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	IsPrimary bool                   `protobuf:"varint,3,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	// Defaults to "public"
+	Visibility    string `protobuf:"bytes,4,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddImageRequest) Reset() {
+	*x = AddImageRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddImageRequest) ProtoMessage() {}
+
+func (x *AddImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddImageRequest.ProtoReflect.Descriptor instead.
+func (*AddImageRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddImageRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddImageRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AddImageRequest) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *AddImageRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type ListProductImagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductImagesRequest) Reset() {
+	*x = ListProductImagesRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductImagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductImagesRequest) ProtoMessage() {}
+
+func (x *ListProductImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductImagesRequest.ProtoReflect.Descriptor instead.
+func (*ListProductImagesRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListProductImagesRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type ListProductImagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Images        []*ProductImage        `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductImagesResponse) Reset() {
+	*x = ListProductImagesResponse{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductImagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductImagesResponse) ProtoMessage() {}
+
+func (x *ListProductImagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductImagesResponse.ProtoReflect.Descriptor instead.
+func (*ListProductImagesResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListProductImagesResponse) GetImages() []*ProductImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type DeleteImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteImageRequest) Reset() {
+	*x = DeleteImageRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteImageRequest) ProtoMessage() {}
+
+func (x *DeleteImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteImageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteImageRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteImageRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteImageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteImageResponse) Reset() {
+	*x = DeleteImageResponse{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteImageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteImageResponse) ProtoMessage() {}
+
+func (x *DeleteImageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteImageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteImageResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{5}
+}
+
+type SetPrimaryImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ImageId       string                 `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPrimaryImageRequest) Reset() {
+	*x = SetPrimaryImageRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPrimaryImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPrimaryImageRequest) ProtoMessage() {}
+
+func (x *SetPrimaryImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPrimaryImageRequest.ProtoReflect.Descriptor instead.
+func (*SetPrimaryImageRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetPrimaryImageRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SetPrimaryImageRequest) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+type SetPrimaryImageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPrimaryImageResponse) Reset() {
+	*x = SetPrimaryImageResponse{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPrimaryImageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPrimaryImageResponse) ProtoMessage() {}
+
+func (x *SetPrimaryImageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPrimaryImageResponse.ProtoReflect.Descriptor instead.
+func (*SetPrimaryImageResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{7}
+}
+
+type SetImageVisibilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Visibility    string                 `protobuf:"bytes,2,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetImageVisibilityRequest) Reset() {
+	*x = SetImageVisibilityRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetImageVisibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetImageVisibilityRequest) ProtoMessage() {}
+
+func (x *SetImageVisibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetImageVisibilityRequest.ProtoReflect.Descriptor instead.
+func (*SetImageVisibilityRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetImageVisibilityRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetImageVisibilityRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type SetImageVisibilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetImageVisibilityResponse) Reset() {
+	*x = SetImageVisibilityResponse{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetImageVisibilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetImageVisibilityResponse) ProtoMessage() {}
+
+func (x *SetImageVisibilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetImageVisibilityResponse.ProtoReflect.Descriptor instead.
+func (*SetImageVisibilityResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{9}
+}
+
+// ImageOperation is one step of a batch. Which fields are used depends on op:
+// "add" uses product_id, url, is_primary and visibility, "delete" uses
+// image_id, "set_primary" uses product_id and image_id, "reorder" uses
+// product_id and image_ids.
+type ImageOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ImageId       string                 `protobuf:"bytes,3,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	ImageIds      []string               `protobuf:"bytes,4,rep,name=image_ids,json=imageIds,proto3" json:"image_ids,omitempty"`
+	Url           string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,6,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	Visibility    string                 `protobuf:"bytes,7,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageOperation) Reset() {
+	*x = ImageOperation{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageOperation) ProtoMessage() {}
+
+func (x *ImageOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageOperation.ProtoReflect.Descriptor instead.
+func (*ImageOperation) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ImageOperation) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *ImageOperation) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ImageOperation) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *ImageOperation) GetImageIds() []string {
+	if x != nil {
+		return x.ImageIds
+	}
+	return nil
+}
+
+func (x *ImageOperation) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImageOperation) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *ImageOperation) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type ApplyOperationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operations    []*ImageOperation      `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyOperationsRequest) Reset() {
+	*x = ApplyOperationsRequest{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyOperationsRequest) ProtoMessage() {}
+
+func (x *ApplyOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyOperationsRequest.ProtoReflect.Descriptor instead.
+func (*ApplyOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ApplyOperationsRequest) GetOperations() []*ImageOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+// ImageOperationResult reports the operation at index. status is a gRPC status
+// code, reason the error code of a failed operation, "aborted" when it was not
+// applied because another operation for the same product failed.
+type ImageOperationResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Index   int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Op      string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	Status  int32                  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason  string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// Set for successful adds
+	Image         *ProductImage `protobuf:"bytes,6,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageOperationResult) Reset() {
+	*x = ImageOperationResult{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageOperationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageOperationResult) ProtoMessage() {}
+
+func (x *ImageOperationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageOperationResult.ProtoReflect.Descriptor instead.
+func (*ImageOperationResult) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ImageOperationResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImageOperationResult) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *ImageOperationResult) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ImageOperationResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ImageOperationResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ImageOperationResult) GetImage() *ProductImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+type ApplyOperationsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Results       []*ImageOperationResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyOperationsResponse) Reset() {
+	*x = ApplyOperationsResponse{}
+	mi := &file_catalog_v1_product_image_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyOperationsResponse) ProtoMessage() {}
+
+func (x *ApplyOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_v1_product_image_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyOperationsResponse.ProtoReflect.Descriptor instead.
+func (*ApplyOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_v1_product_image_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ApplyOperationsResponse) GetResults() []*ImageOperationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_catalog_v1_product_image_proto protoreflect.FileDescriptor
+
+const file_catalog_v1_product_image_proto_rawDesc = "" +
+	"\n" +
+	"\x1ecatalog/v1/product_image.proto\x12\n" +
+	"catalog.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe5\x01\n" +
+	"\fProductImage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x04 \x01(\bR\tisPrimary\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x05 \x01(\tR\n" +
+	"visibility\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x81\x01\n" +
+	"\x0fAddImageRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x03 \x01(\bR\tisPrimary\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x04 \x01(\tR\n" +
+	"visibility\"9\n" +
+	"\x18ListProductImagesRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"M\n" +
+	"\x19ListProductImagesResponse\x120\n" +
+	"\x06images\x18\x01 \x03(\v2\x18.catalog.v1.ProductImageR\x06images\"$\n" +
+	"\x12DeleteImageRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13DeleteImageResponse\"R\n" +
+	"\x16SetPrimaryImageRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x19\n" +
+	"\bimage_id\x18\x02 \x01(\tR\aimageId\"\x19\n" +
+	"\x17SetPrimaryImageResponse\"K\n" +
+	"\x19SetImageVisibilityRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x02 \x01(\tR\n" +
+	"visibility\"\x1c\n" +
+	"\x1aSetImageVisibilityResponse\"\xc8\x01\n" +
+	"\x0eImageOperation\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
+	"\bimage_id\x18\x03 \x01(\tR\aimageId\x12\x1b\n" +
+	"\timage_ids\x18\x04 \x03(\tR\bimageIds\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x06 \x01(\bR\tisPrimary\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\a \x01(\tR\n" +
+	"visibility\"T\n" +
+	"\x16ApplyOperationsRequest\x12:\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x1a.catalog.v1.ImageOperationR\n" +
+	"operations\"\xb6\x01\n" +
+	"\x14ImageOperationResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12.\n" +
+	"\x05image\x18\x06 \x01(\v2\x18.catalog.v1.ProductImageR\x05image\"U\n" +
+	"\x17ApplyOperationsResponse\x12:\n" +
+	"\aresults\x18\x01 \x03(\v2 .catalog.v1.ImageOperationResultR\aresults2\xa7\x04\n" +
+	"\x13ProductImageService\x12A\n" +
+	"\bAddImage\x12\x1b.catalog.v1.AddImageRequest\x1a\x18.catalog.v1.ProductImage\x12`\n" +
+	"\x11ListProductImages\x12$.catalog.v1.ListProductImagesRequest\x1a%.catalog.v1.ListProductImagesResponse\x12N\n" +
+	"\vDeleteImage\x12\x1e.catalog.v1.DeleteImageRequest\x1a\x1f.catalog.v1.DeleteImageResponse\x12Z\n" +
+	"\x0fSetPrimaryImage\x12\".catalog.v1.SetPrimaryImageRequest\x1a#.catalog.v1.SetPrimaryImageResponse\x12c\n" +
+	"\x12SetImageVisibility\x12%.catalog.v1.SetImageVisibilityRequest\x1a&.catalog.v1.SetImageVisibilityResponse\x12Z\n" +
+	"\x0fApplyOperations\x12\".catalog.v1.ApplyOperationsRequest\x1a#.catalog.v1.ApplyOperationsResponseB1Z/product-listing/internal/delivery/rpc/catalogpbb\x06proto3"
+
+var (
+	file_catalog_v1_product_image_proto_rawDescOnce sync.Once
+	file_catalog_v1_product_image_proto_rawDescData []byte
+)
+
+func file_catalog_v1_product_image_proto_rawDescGZIP() []byte {
+	file_catalog_v1_product_image_proto_rawDescOnce.Do(func() {
+		file_catalog_v1_product_image_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_catalog_v1_product_image_proto_rawDesc), len(file_catalog_v1_product_image_proto_rawDesc)))
+	})
+	return file_catalog_v1_product_image_proto_rawDescData
+}
+
+var file_catalog_v1_product_image_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_catalog_v1_product_image_proto_goTypes = []any{
+	(*ProductImage)(nil),               // 0: catalog.v1.ProductImage
+	(*AddImageRequest)(nil),            // 1: catalog.v1.AddImageRequest
+	(*ListProductImagesRequest)(nil),   // 2: catalog.v1.ListProductImagesRequest
+	(*ListProductImagesResponse)(nil),  // 3: catalog.v1.ListProductImagesResponse
+	(*DeleteImageRequest)(nil),         // 4: catalog.v1.DeleteImageRequest
+	(*DeleteImageResponse)(nil),        // 5: catalog.v1.DeleteImageResponse
+	(*SetPrimaryImageRequest)(nil),     // 6: catalog.v1.SetPrimaryImageRequest
+	(*SetPrimaryImageResponse)(nil),    // 7: catalog.v1.SetPrimaryImageResponse
+	(*SetImageVisibilityRequest)(nil),  // 8: catalog.v1.SetImageVisibilityRequest
+	(*SetImageVisibilityResponse)(nil), // 9: catalog.v1.SetImageVisibilityResponse
+	(*ImageOperation)(nil),             // 10: catalog.v1.ImageOperation
+	(*ApplyOperationsRequest)(nil),     // 11: catalog.v1.ApplyOperationsRequest
+	(*ImageOperationResult)(nil),       // 12: catalog.v1.ImageOperationResult
+	(*ApplyOperationsResponse)(nil),    // 13: catalog.v1.ApplyOperationsResponse
+	(*timestamppb.Timestamp)(nil),      // 14: google.protobuf.Timestamp
+}
+var file_catalog_v1_product_image_proto_depIdxs = []int32{
+	14, // 0: catalog.v1.ProductImage.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: catalog.v1.ListProductImagesResponse.images:type_name -> catalog.v1.ProductImage
+	10, // 2: catalog.v1.ApplyOperationsRequest.operations:type_name -> catalog.v1.ImageOperation
+	0,  // 3: catalog.v1.ImageOperationResult.image:type_name -> catalog.v1.ProductImage
+	12, // 4: catalog.v1.ApplyOperationsResponse.results:type_name -> catalog.v1.ImageOperationResult
+	1,  // 5: catalog.v1.ProductImageService.AddImage:input_type -> catalog.v1.AddImageRequest
+	2,  // 6: catalog.v1.ProductImageService.ListProductImages:input_type -> catalog.v1.ListProductImagesRequest
+	4,  // 7: catalog.v1.ProductImageService.DeleteImage:input_type -> catalog.v1.DeleteImageRequest
+	6,  // 8: catalog.v1.ProductImageService.SetPrimaryImage:input_type -> catalog.v1.SetPrimaryImageRequest
+	8,  // 9: catalog.v1.ProductImageService.SetImageVisibility:input_type -> catalog.v1.SetImageVisibilityRequest
+	11, // 10: catalog.v1.ProductImageService.ApplyOperations:input_type -> catalog.v1.ApplyOperationsRequest
+	0,  // 11: catalog.v1.ProductImageService.AddImage:output_type -> catalog.v1.ProductImage
+	3,  // 12: catalog.v1.ProductImageService.ListProductImages:output_type -> catalog.v1.ListProductImagesResponse
+	5,  // 13: catalog.v1.ProductImageService.DeleteImage:output_type -> catalog.v1.DeleteImageResponse
+	7,  // 14: catalog.v1.ProductImageService.SetPrimaryImage:output_type -> catalog.v1.SetPrimaryImageResponse
+	9,  // 15: catalog.v1.ProductImageService.SetImageVisibility:output_type -> catalog.v1.SetImageVisibilityResponse
+	13, // 16: catalog.v1.ProductImageService.ApplyOperations:output_type -> catalog.v1.ApplyOperationsResponse
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_catalog_v1_product_image_proto_init() }
+func file_catalog_v1_product_image_proto_init() {
+	if File_catalog_v1_product_image_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_catalog_v1_product_image_proto_rawDesc), len(file_catalog_v1_product_image_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_catalog_v1_product_image_proto_goTypes,
+		DependencyIndexes: file_catalog_v1_product_image_proto_depIdxs,
+		MessageInfos:      file_catalog_v1_product_image_proto_msgTypes,
+	}.Build()
+	File_catalog_v1_product_image_proto = out.File
+	file_catalog_v1_product_image_proto_goTypes = nil
+	file_catalog_v1_product_image_proto_depIdxs = nil
+}
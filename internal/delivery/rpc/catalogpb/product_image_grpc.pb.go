@@ -0,0 +1,317 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: catalog/v1/product_image.proto
+
+package catalogpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ProductImageService_AddImage_FullMethodName           = "/catalog.v1.ProductImageService/AddImage"
+	ProductImageService_ListProductImages_FullMethodName  = "/catalog.v1.ProductImageService/ListProductImages"
+	ProductImageService_DeleteImage_FullMethodName        = "/catalog.v1.ProductImageService/DeleteImage"
+	ProductImageService_SetPrimaryImage_FullMethodName    = "/catalog.v1.ProductImageService/SetPrimaryImage"
+	ProductImageService_SetImageVisibility_FullMethodName = "/catalog.v1.ProductImageService/SetImageVisibility"
+	ProductImageService_ApplyOperations_FullMethodName    = "/catalog.v1.ProductImageService/ApplyOperations"
+)
+
+// ProductImageServiceClient is the client API for ProductImageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ProductImageService mirrors usecase.ProductImageUsecase. Writes need the
+// editor role. Private images are returned with signed media URLs.
+type ProductImageServiceClient interface {
+	AddImage(ctx context.Context, in *AddImageRequest, opts ...grpc.CallOption) (*ProductImage, error)
+	ListProductImages(ctx context.Context, in *ListProductImagesRequest, opts ...grpc.CallOption) (*ListProductImagesResponse, error)
+	DeleteImage(ctx context.Context, in *DeleteImageRequest, opts ...grpc.CallOption) (*DeleteImageResponse, error)
+	SetPrimaryImage(ctx context.Context, in *SetPrimaryImageRequest, opts ...grpc.CallOption) (*SetPrimaryImageResponse, error)
+	SetImageVisibility(ctx context.Context, in *SetImageVisibilityRequest, opts ...grpc.CallOption) (*SetImageVisibilityResponse, error)
+	ApplyOperations(ctx context.Context, in *ApplyOperationsRequest, opts ...grpc.CallOption) (*ApplyOperationsResponse, error)
+}
+
+type productImageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProductImageServiceClient(cc grpc.ClientConnInterface) ProductImageServiceClient {
+	return &productImageServiceClient{cc}
+}
+
+func (c *productImageServiceClient) AddImage(ctx context.Context, in *AddImageRequest, opts ...grpc.CallOption) (*ProductImage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductImage)
+	err := c.cc.Invoke(ctx, ProductImageService_AddImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productImageServiceClient) ListProductImages(ctx context.Context, in *ListProductImagesRequest, opts ...grpc.CallOption) (*ListProductImagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductImagesResponse)
+	err := c.cc.Invoke(ctx, ProductImageService_ListProductImages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productImageServiceClient) DeleteImage(ctx context.Context, in *DeleteImageRequest, opts ...grpc.CallOption) (*DeleteImageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteImageResponse)
+	err := c.cc.Invoke(ctx, ProductImageService_DeleteImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productImageServiceClient) SetPrimaryImage(ctx context.Context, in *SetPrimaryImageRequest, opts ...grpc.CallOption) (*SetPrimaryImageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPrimaryImageResponse)
+	err := c.cc.Invoke(ctx, ProductImageService_SetPrimaryImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productImageServiceClient) SetImageVisibility(ctx context.Context, in *SetImageVisibilityRequest, opts ...grpc.CallOption) (*SetImageVisibilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetImageVisibilityResponse)
+	err := c.cc.Invoke(ctx, ProductImageService_SetImageVisibility_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productImageServiceClient) ApplyOperations(ctx context.Context, in *ApplyOperationsRequest, opts ...grpc.CallOption) (*ApplyOperationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyOperationsResponse)
+	err := c.cc.Invoke(ctx, ProductImageService_ApplyOperations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProductImageServiceServer is the server API for ProductImageService service.
+// All implementations must embed UnimplementedProductImageServiceServer
+// for forward compatibility.
+//
+// ProductImageService mirrors usecase.ProductImageUsecase. Writes need the
+// editor role. Private images are returned with signed media URLs.
+type ProductImageServiceServer interface {
+	AddImage(context.Context, *AddImageRequest) (*ProductImage, error)
+	ListProductImages(context.Context, *ListProductImagesRequest) (*ListProductImagesResponse, error)
+	DeleteImage(context.Context, *DeleteImageRequest) (*DeleteImageResponse, error)
+	SetPrimaryImage(context.Context, *SetPrimaryImageRequest) (*SetPrimaryImageResponse, error)
+	SetImageVisibility(context.Context, *SetImageVisibilityRequest) (*SetImageVisibilityResponse, error)
+	ApplyOperations(context.Context, *ApplyOperationsRequest) (*ApplyOperationsResponse, error)
+	mustEmbedUnimplementedProductImageServiceServer()
+}
+
+// UnimplementedProductImageServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProductImageServiceServer struct{}
+
+func (UnimplementedProductImageServiceServer) AddImage(context.Context, *AddImageRequest) (*ProductImage, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddImage not implemented")
+}
+func (UnimplementedProductImageServiceServer) ListProductImages(context.Context, *ListProductImagesRequest) (*ListProductImagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProductImages not implemented")
+}
+func (UnimplementedProductImageServiceServer) DeleteImage(context.Context, *DeleteImageRequest) (*DeleteImageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteImage not implemented")
+}
+func (UnimplementedProductImageServiceServer) SetPrimaryImage(context.Context, *SetPrimaryImageRequest) (*SetPrimaryImageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetPrimaryImage not implemented")
+}
+func (UnimplementedProductImageServiceServer) SetImageVisibility(context.Context, *SetImageVisibilityRequest) (*SetImageVisibilityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetImageVisibility not implemented")
+}
+func (UnimplementedProductImageServiceServer) ApplyOperations(context.Context, *ApplyOperationsRequest) (*ApplyOperationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ApplyOperations not implemented")
+}
+func (UnimplementedProductImageServiceServer) mustEmbedUnimplementedProductImageServiceServer() {}
+func (UnimplementedProductImageServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeProductImageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductImageServiceServer will
+// result in compilation errors.
+type UnsafeProductImageServiceServer interface {
+	mustEmbedUnimplementedProductImageServiceServer()
+}
+
+func RegisterProductImageServiceServer(s grpc.ServiceRegistrar, srv ProductImageServiceServer) {
+	// If the following call panics, it indicates UnimplementedProductImageServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProductImageService_ServiceDesc, srv)
+}
+
+func _ProductImageService_AddImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).AddImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_AddImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).AddImage(ctx, req.(*AddImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductImageService_ListProductImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).ListProductImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_ListProductImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).ListProductImages(ctx, req.(*ListProductImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductImageService_DeleteImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).DeleteImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_DeleteImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).DeleteImage(ctx, req.(*DeleteImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductImageService_SetPrimaryImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPrimaryImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).SetPrimaryImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_SetPrimaryImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).SetPrimaryImage(ctx, req.(*SetPrimaryImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductImageService_SetImageVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetImageVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).SetImageVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_SetImageVisibility_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).SetImageVisibility(ctx, req.(*SetImageVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductImageService_ApplyOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductImageServiceServer).ApplyOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductImageService_ApplyOperations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductImageServiceServer).ApplyOperations(ctx, req.(*ApplyOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProductImageService_ServiceDesc is the grpc.ServiceDesc for ProductImageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProductImageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "catalog.v1.ProductImageService",
+	HandlerType: (*ProductImageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddImage",
+			Handler:    _ProductImageService_AddImage_Handler,
+		},
+		{
+			MethodName: "ListProductImages",
+			Handler:    _ProductImageService_ListProductImages_Handler,
+		},
+		{
+			MethodName: "DeleteImage",
+			Handler:    _ProductImageService_DeleteImage_Handler,
+		},
+		{
+			MethodName: "SetPrimaryImage",
+			Handler:    _ProductImageService_SetPrimaryImage_Handler,
+		},
+		{
+			MethodName: "SetImageVisibility",
+			Handler:    _ProductImageService_SetImageVisibility_Handler,
+		},
+		{
+			MethodName: "ApplyOperations",
+			Handler:    _ProductImageService_ApplyOperations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "catalog/v1/product_image.proto",
+}
@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+)
+
+type categoryService struct {
+	catalogpb.UnimplementedCategoryServiceServer
+	usecase usecase.CategoryUsecase
+}
+
+func newCategoryService(u usecase.CategoryUsecase) *categoryService {
+	return &categoryService{usecase: u}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, req *catalogpb.CreateCategoryRequest) (*catalogpb.Category, error) {
+	category, err := s.usecase.CreateCategory(ctx, domain.CategoryInput{
+		Name: req.GetName(),
+		Slug: req.GetSlug(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCategoryPB(category), nil
+}
+
+func (s *categoryService) ListCategories(ctx context.Context, req *catalogpb.ListCategoriesRequest) (*catalogpb.ListCategoriesResponse, error) {
+	page, limit, err := pagination(req.GetPage(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.usecase.GetCategoryCount(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	categories, err := s.usecase.GetCategories(ctx, page, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &catalogpb.ListCategoriesResponse{
+		Categories: make([]*catalogpb.Category, 0, len(categories)),
+		Total:      int32(total),
+	}
+	for i := range categories {
+		resp.Categories = append(resp.Categories, toCategoryPB(&categories[i]))
+	}
+	return resp, nil
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, req *catalogpb.GetCategoryRequest) (*catalogpb.Category, error) {
+	category, err := s.usecase.GetCategoryById(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCategoryPB(category), nil
+}
+
+func (s *categoryService) GetCategoryBySlug(ctx context.Context, req *catalogpb.GetCategoryBySlugRequest) (*catalogpb.Category, error) {
+	category, err := s.usecase.GetCategoryBySlug(ctx, req.GetSlug())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCategoryPB(category), nil
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, req *catalogpb.UpdateCategoryRequest) (*catalogpb.Category, error) {
+	category, err := s.usecase.UpdateCategory(ctx, req.GetId(), domain.CategoryInput{
+		Name: req.GetName(),
+		Slug: req.GetSlug(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCategoryPB(category), nil
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, req *catalogpb.DeleteCategoryRequest) (*catalogpb.DeleteCategoryResponse, error) {
+	if err := s.usecase.DeleteCategory(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.DeleteCategoryResponse{}, nil
+}
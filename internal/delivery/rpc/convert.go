@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toCategoryPB(c *domain.Category) *catalogpb.Category {
+	return &catalogpb.Category{
+		Id:        c.ID.String(),
+		Name:      c.Name,
+		Slug:      c.Slug,
+		CreatedAt: timestamp(c.CreatedAt),
+		UpdatedAt: timestamp(c.UpdatedAt),
+	}
+}
+
+func toProductPB(p *domain.Product) *catalogpb.Product {
+	categories := make([]*catalogpb.Category, 0, len(p.Categories))
+	for i := range p.Categories {
+		categories = append(categories, toCategoryPB(&p.Categories[i]))
+	}
+
+	images := make([]*catalogpb.ProductImage, 0, len(p.Images))
+	for i := range p.Images {
+		images = append(images, toProductImagePB(&p.Images[i]))
+	}
+
+	return &catalogpb.Product{
+		Id:              p.ID.String(),
+		Name:            p.Name,
+		Slug:            p.Slug,
+		Description:     p.Description,
+		Price:           p.Price,
+		PrimaryImageUrl: p.PrimaryImageURL,
+		Categories:      categories,
+		Images:          images,
+		CreatedAt:       timestamp(p.CreatedAt),
+		UpdatedAt:       timestamp(p.UpdatedAt),
+	}
+}
+
+func toProductImagePB(img *domain.ProductImage) *catalogpb.ProductImage {
+	return &catalogpb.ProductImage{
+		Id:         img.ID.String(),
+		ProductId:  img.ProductID.String(),
+		Url:        img.Url,
+		IsPrimary:  img.IsPrimary,
+		Visibility: img.Visibility,
+		Position:   int32(img.Position),
+		CreatedAt:  timestamp(img.CreatedAt),
+	}
+}
+
+// timestamp leaves unknown times unset, such as those of the categories
+// embedded in products.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// pagination applies the defaults of list requests.
+func pagination(page, limit int32) (int, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	if page < 1 {
+		return 0, 0, invalidArgument("invalid_page", "page must be a positive number")
+	}
+	if limit < 1 {
+		return 0, 0, invalidArgument("invalid_limit", "limit must be a positive number")
+	}
+	return int(page), int(limit), nil
+}
@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"errors"
+	"product-listing/internal/domain"
+
+	"github.com/op/go-logging"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var rpcLog = logging.MustGetLogger("grpc")
+
+// errorDomain names this service in the ErrorInfo details of errors.
+const errorDomain = "product-listing"
+
+// toStatus maps usecase errors to a gRPC status. Business rule violations
+// keep their code as the reason of an ErrorInfo detail, anything else is
+// reported as an internal error.
+func toStatus(err error) error {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		rpcLog.Errorf("Request failed: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return newStatus(statusCode(domainErr.Kind), domainErr.Code, domainErr.Message)
+}
+
+func statusCode(kind domain.ErrorKind) codes.Code {
+	switch kind {
+	case domain.ErrorKindInvalid:
+		return codes.InvalidArgument
+	case domain.ErrorKindNotFound:
+		return codes.NotFound
+	case domain.ErrorKindConflict:
+		return codes.AlreadyExists
+	case domain.ErrorKindForbidden:
+		return codes.PermissionDenied
+	case domain.ErrorKindUnauthorized:
+		return codes.Unauthenticated
+	case domain.ErrorKindUnprocessable:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+func newStatus(code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	if reason == "" {
+		return st.Err()
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func invalidArgument(reason, message string) error {
+	return newStatus(codes.InvalidArgument, reason, message)
+}
+
+// errorReason is the code of a business rule violation, empty for other
+// errors.
+func errorReason(err error) string {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// catalogServicePrefix marks the methods the interceptors apply to. Health
+// and reflection stay open and are not bound to a store.
+const catalogServicePrefix = "/catalog.v1."
+
+// maxRequestIDLength bounds client supplied request IDs.
+const maxRequestIDLength = 128
+
+// callContext prepares catalog calls the way the REST middleware prepares
+// requests: it tags the call for the audit log, authenticates the caller
+// from x-api-key or "authorization: Bearer <jwt>" metadata, binds the store
+// from the credentials or x-store metadata, applies the rate limits and
+// checks the role the method needs.
+type callContext struct {
+	auth    usecase.AuthUsecase
+	stores  usecase.StoreUsecase
+	limiter domain.RateLimiter
+	read    domain.RateLimit
+	write   domain.RateLimit
+	// reads lists the full names of the methods open to anonymous callers.
+	// Every other method is a write and needs the role roles maps it to, or
+	// admin when it is not listed, so a new method is closed until it is
+	// classified.
+	reads map[string]bool
+	roles map[string]domain.Role
+}
+
+func (cc *callContext) unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := cc.prepare(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (cc *callContext) stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := cc.prepare(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (cc *callContext) prepare(ctx context.Context, method string) (context.Context, error) {
+	if !strings.HasPrefix(method, catalogServicePrefix) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := firstValue(md, "x-request-id")
+	if id == "" || len(id) > maxRequestIDLength {
+		id = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id))
+	ip := clientIP(ctx)
+	ctx = domain.ContextWithRequestMeta(ctx, domain.RequestMeta{ID: id, IP: ip})
+
+	var principal *domain.Principal
+	var err error
+	if key := firstValue(md, "x-api-key"); key != "" {
+		principal, err = cc.auth.AuthenticateAPIKey(ctx, key)
+	} else if token, ok := strings.CutPrefix(firstValue(md, "authorization"), "Bearer "); ok {
+		principal, err = cc.auth.AuthenticateToken(ctx, strings.TrimSpace(token))
+	}
+	if err != nil {
+		if reason := errorReason(err); reason != "" {
+			return nil, newStatus(codes.Unauthenticated, reason, err.Error())
+		}
+		return nil, toStatus(err)
+	}
+	if principal != nil {
+		ctx = domain.ContextWithPrincipal(ctx, principal)
+	}
+
+	store, err := cc.stores.ResolveStore(ctx, principal, firstValue(md, "x-store"))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	ctx = domain.ContextWithStore(ctx, store)
+
+	write := !cc.reads[method]
+	if err := cc.rateLimit(ctx, principal, ip, write); err != nil {
+		return nil, err
+	}
+
+	if write {
+		role, ok := cc.roles[method]
+		if !ok {
+			role = domain.RoleAdmin
+		}
+		if principal == nil {
+			return nil, newStatus(codes.Unauthenticated, "unauthenticated", "authentication required")
+		}
+		if !principal.Role.Allows(role) {
+			return nil, newStatus(codes.PermissionDenied, "insufficient_role", string(role)+" role required")
+		}
+	}
+
+	return ctx, nil
+}
+
+// rateLimit shares its buckets with the REST API, so a caller has one budget
+// whichever protocol it uses. A failing limiter lets calls through.
+func (cc *callContext) rateLimit(ctx context.Context, principal *domain.Principal, ip string, write bool) error {
+	limit, class := cc.read, "read"
+	if write {
+		limit, class = cc.write, "write"
+	}
+	if limit.Limit <= 0 {
+		return nil
+	}
+
+	key := class + ":ip:" + ip
+	if principal != nil {
+		key = class + ":" + principal.Method + ":" + principal.Subject
+	}
+
+	res, err := cc.limiter.Allow(ctx, key, limit)
+	if err != nil {
+		rpcLog.Errorf("Rate limiter failed: %v", err)
+		return nil
+	}
+	if !res.Allowed {
+		retryAfter := max(1, int(math.Ceil(res.RetryAfter.Seconds())))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+		return newStatus(codes.ResourceExhausted, "rate_limited", "rate limit exceeded, retry later")
+	}
+	return nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAuth knows one API key per role, named after it.
+type fakeAuth struct {
+	usecase.AuthUsecase
+}
+
+func (fakeAuth) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	role, ok := strings.CutSuffix(key, "-key")
+	if !ok || !domain.Role(role).Valid() {
+		return nil, domain.NewUnauthorizedError("invalid_api_key", "invalid API key")
+	}
+	return &domain.Principal{Subject: key, Role: domain.Role(role), Method: "api_key"}, nil
+}
+
+type fakeStores struct {
+	usecase.StoreUsecase
+}
+
+func (fakeStores) ResolveStore(ctx context.Context, principal *domain.Principal, requested string) (*domain.Store, error) {
+	return &domain.Store{Slug: "default"}, nil
+}
+
+// recordingLimiter allows every call and records the buckets taken from.
+type recordingLimiter struct {
+	keys []string
+}
+
+func (l *recordingLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	l.keys = append(l.keys, key)
+	return domain.RateLimitResult{Allowed: true}, nil
+}
+
+func testCallContext(limiter domain.RateLimiter) *callContext {
+	return &callContext{
+		auth:    fakeAuth{},
+		stores:  fakeStores{},
+		limiter: limiter,
+		read:    domain.RateLimit{Limit: 10, Window: time.Minute},
+		write:   domain.RateLimit{Limit: 10, Window: time.Minute},
+		reads:   map[string]bool{catalogpb.ProductService_GetProduct_FullMethodName: true},
+		roles:   map[string]domain.Role{catalogpb.ProductService_DeleteProduct_FullMethodName: domain.RoleEditor},
+	}
+}
+
+func TestCallContextRoles(t *testing.T) {
+	const (
+		read     = catalogpb.ProductService_GetProduct_FullMethodName
+		write    = catalogpb.ProductService_DeleteProduct_FullMethodName
+		unlisted = catalogpb.ProductService_UpdateProduct_FullMethodName
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		want   codes.Code
+	}{
+		{"anonymous read", read, "", codes.OK},
+		{"anonymous write", write, "", codes.Unauthenticated},
+		{"viewer write", write, "viewer-key", codes.PermissionDenied},
+		{"editor write", write, "editor-key", codes.OK},
+		{"invalid key", read, "stolen", codes.Unauthenticated},
+		{"anonymous unlisted method", unlisted, "", codes.Unauthenticated},
+		{"editor unlisted method", unlisted, "editor-key", codes.PermissionDenied},
+		{"admin unlisted method", unlisted, "admin-key", codes.OK},
+		{"other service", "/grpc.health.v1.Health/Check", "", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.key != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", tt.key))
+			}
+			_, err := testCallContext(&recordingLimiter{}).prepare(ctx, tt.method)
+			if code := status.Code(err); code != tt.want {
+				t.Errorf("prepare = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallContextBindsStoreAndPrincipal(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "editor-key", "x-request-id", "req-1"))
+	ctx, err := testCallContext(&recordingLimiter{}).prepare(ctx, catalogpb.ProductService_DeleteProduct_FullMethodName)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	if store := domain.StoreFromContext(ctx); store == nil || store.Slug != "default" {
+		t.Errorf("store = %v, want default", store)
+	}
+	if principal := domain.PrincipalFromContext(ctx); principal == nil || principal.Subject != "editor-key" {
+		t.Errorf("principal = %v, want editor-key", principal)
+	}
+	if meta := domain.RequestMetaFromContext(ctx); meta.ID != "req-1" {
+		t.Errorf("request ID = %q, want req-1", meta.ID)
+	}
+}
+
+func TestCallContextRateLimitClasses(t *testing.T) {
+	limiter := &recordingLimiter{}
+	cc := testCallContext(limiter)
+	anonymous := context.Background()
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "admin-key"))
+
+	_, _ = cc.prepare(anonymous, catalogpb.ProductService_GetProduct_FullMethodName)
+	_, _ = cc.prepare(admin, catalogpb.ProductService_DeleteProduct_FullMethodName)
+	_, _ = cc.prepare(admin, catalogpb.ProductService_UpdateProduct_FullMethodName)
+
+	want := []string{"read:ip:", "write:api_key:admin-key", "write:api_key:admin-key"}
+	if strings.Join(limiter.keys, ",") != strings.Join(want, ",") {
+		t.Errorf("buckets = %q, want %q", limiter.keys, want)
+	}
+}
@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"context"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type productImageService struct {
+	catalogpb.UnimplementedProductImageServiceServer
+	usecase usecase.ProductImageUsecase
+}
+
+func newProductImageService(u usecase.ProductImageUsecase) *productImageService {
+	return &productImageService{usecase: u}
+}
+
+func (s *productImageService) AddImage(ctx context.Context, req *catalogpb.AddImageRequest) (*catalogpb.ProductImage, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, invalidArgument("invalid_product_id", "invalid product_id")
+	}
+
+	img, err := s.usecase.AddImage(ctx, domain.ProductImageInput{
+		ProductID:  productID,
+		Url:        req.GetUrl(),
+		IsPrimary:  req.GetIsPrimary(),
+		Visibility: req.GetVisibility(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProductImagePB(img), nil
+}
+
+func (s *productImageService) ListProductImages(ctx context.Context, req *catalogpb.ListProductImagesRequest) (*catalogpb.ListProductImagesResponse, error) {
+	images, err := s.usecase.GetProductImages(ctx, req.GetProductId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &catalogpb.ListProductImagesResponse{Images: make([]*catalogpb.ProductImage, 0, len(images))}
+	for i := range images {
+		resp.Images = append(resp.Images, toProductImagePB(&images[i]))
+	}
+	return resp, nil
+}
+
+func (s *productImageService) DeleteImage(ctx context.Context, req *catalogpb.DeleteImageRequest) (*catalogpb.DeleteImageResponse, error) {
+	if err := s.usecase.DeleteImage(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.DeleteImageResponse{}, nil
+}
+
+func (s *productImageService) SetPrimaryImage(ctx context.Context, req *catalogpb.SetPrimaryImageRequest) (*catalogpb.SetPrimaryImageResponse, error) {
+	if err := s.usecase.SetPrimary(ctx, req.GetProductId(), req.GetImageId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.SetPrimaryImageResponse{}, nil
+}
+
+func (s *productImageService) SetImageVisibility(ctx context.Context, req *catalogpb.SetImageVisibilityRequest) (*catalogpb.SetImageVisibilityResponse, error) {
+	if err := s.usecase.SetVisibility(ctx, req.GetId(), req.GetVisibility()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.SetImageVisibilityResponse{}, nil
+}
+
+func (s *productImageService) ApplyOperations(ctx context.Context, req *catalogpb.ApplyOperationsRequest) (*catalogpb.ApplyOperationsResponse, error) {
+	ops := make([]domain.ImageOperation, 0, len(req.GetOperations()))
+	for _, op := range req.GetOperations() {
+		ops = append(ops, domain.ImageOperation{
+			Op:         op.GetOp(),
+			ProductID:  op.GetProductId(),
+			ImageID:    op.GetImageId(),
+			ImageIDs:   op.GetImageIds(),
+			Url:        op.GetUrl(),
+			IsPrimary:  op.GetIsPrimary(),
+			Visibility: op.GetVisibility(),
+		})
+	}
+
+	results, err := s.usecase.ApplyOperations(ctx, ops)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &catalogpb.ApplyOperationsResponse{Results: make([]*catalogpb.ImageOperationResult, 0, len(results))}
+	for _, result := range results {
+		item := &catalogpb.ImageOperationResult{
+			Index:   int32(result.Index),
+			Op:      result.Op,
+			Status:  int32(codes.OK),
+			Message: "Success",
+		}
+
+		switch {
+		case result.Err != nil:
+			st, _ := status.FromError(toStatus(result.Err))
+			item.Status = int32(st.Code())
+			item.Reason = errorReason(result.Err)
+			item.Message = st.Message()
+		case result.Aborted:
+			item.Status = int32(codes.Aborted)
+			item.Reason = "aborted"
+			item.Message = "not applied, another operation for this product failed"
+		case result.Image != nil:
+			item.Image = toProductImagePB(result.Image)
+		}
+
+		resp.Results = append(resp.Results, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// streamPageSize is how many products StreamProducts loads per query.
+const streamPageSize = 100
+
+type productService struct {
+	catalogpb.UnimplementedProductServiceServer
+	usecase usecase.ProductUsecase
+}
+
+func newProductService(u usecase.ProductUsecase) *productService {
+	return &productService{usecase: u}
+}
+
+func (s *productService) CreateProduct(ctx context.Context, req *catalogpb.CreateProductRequest) (*catalogpb.Product, error) {
+	input, err := productInput(req.GetName(), req.GetSlug(), req.GetDescription(), req.GetCategoryIds(), req.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.usecase.CreateProduct(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProductPB(product), nil
+}
+
+func (s *productService) ListProducts(ctx context.Context, req *catalogpb.ListProductsRequest) (*catalogpb.ListProductsResponse, error) {
+	page, limit, err := pagination(req.GetPage(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.usecase.GetProductCount(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	products, err := s.usecase.GetProducts(ctx, page, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.usecase.ExpandProducts(ctx, products, domain.ProductInclude{Images: req.GetIncludeImages()}); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.ListProductsResponse{
+		Products: toProductsPB(products),
+		Total:    int32(total),
+	}, nil
+}
+
+// StreamProducts walks the catalog a page at a time, so memory use does not
+// grow with its size. Pages continue from the last product sent, so products
+// added or removed during the stream do not shift the rest.
+func (s *productService) StreamProducts(req *catalogpb.StreamProductsRequest, stream grpc.ServerStreamingServer[catalogpb.Product]) error {
+	ctx := stream.Context()
+	include := domain.ProductInclude{Images: req.GetIncludeImages()}
+
+	var after *domain.ProductCursor
+	for {
+		products, err := s.usecase.GetProductsAfter(ctx, after, streamPageSize)
+		if err != nil {
+			return toStatus(err)
+		}
+
+		if err := s.usecase.ExpandProducts(ctx, products, include); err != nil {
+			return toStatus(err)
+		}
+
+		for i := range products {
+			if err := stream.Send(toProductPB(&products[i])); err != nil {
+				return err
+			}
+		}
+
+		if len(products) < streamPageSize {
+			return nil
+		}
+		last := products[len(products)-1]
+		after = &domain.ProductCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func (s *productService) GetProduct(ctx context.Context, req *catalogpb.GetProductRequest) (*catalogpb.Product, error) {
+	product, err := s.usecase.GetProductsById(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	products := []domain.Product{*product}
+	if err := s.usecase.ExpandProducts(ctx, products, domain.ProductInclude{Images: req.GetIncludeImages()}); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProductPB(&products[0]), nil
+}
+
+func (s *productService) ListProductsByCategory(ctx context.Context, req *catalogpb.ListProductsByCategoryRequest) (*catalogpb.ListProductsByCategoryResponse, error) {
+	products, err := s.usecase.GetProductsByCategory(ctx, req.GetCategoryId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.usecase.ExpandProducts(ctx, products, domain.ProductInclude{Images: req.GetIncludeImages()}); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.ListProductsByCategoryResponse{Products: toProductsPB(products)}, nil
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, req *catalogpb.UpdateProductRequest) (*catalogpb.Product, error) {
+	input, err := productInput(req.GetName(), req.GetSlug(), req.GetDescription(), req.GetCategoryIds(), req.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.usecase.UpdateProduct(ctx, req.GetId(), input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProductPB(product), nil
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, req *catalogpb.DeleteProductRequest) (*catalogpb.DeleteProductResponse, error) {
+	if err := s.usecase.DeleteProduct(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &catalogpb.DeleteProductResponse{}, nil
+}
+
+func productInput(name, slug, description string, categoryIDs []string, price float64) (domain.ProductInput, error) {
+	ids := make([]uuid.UUID, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return domain.ProductInput{}, invalidArgument("invalid_category_id", "invalid category_id: "+id)
+		}
+		ids = append(ids, uid)
+	}
+
+	return domain.ProductInput{
+		Name:        name,
+		Slug:        slug,
+		Description: description,
+		CategoryIDs: ids,
+		Price:       price,
+	}, nil
+}
+
+func toProductsPB(products []domain.Product) []*catalogpb.Product {
+	result := make([]*catalogpb.Product, 0, len(products))
+	for i := range products {
+		result = append(result, toProductPB(&products[i]))
+	}
+	return result
+}
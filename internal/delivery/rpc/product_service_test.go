@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// pagedProducts serves a catalog by cursor, newest first, and calls onPage
+// after each page so a test can change the catalog mid-stream.
+type pagedProducts struct {
+	usecase.ProductUsecase
+	products []domain.Product
+	onPage   func()
+}
+
+func (u *pagedProducts) GetProductsAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error) {
+	slices.SortFunc(u.products, func(a, b domain.Product) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(b.ID[:], a.ID[:])
+	})
+
+	var page []domain.Product
+	for _, p := range u.products {
+		if after != nil && !p.CreatedAt.Before(after.CreatedAt) &&
+			(!p.CreatedAt.Equal(after.CreatedAt) || slices.Compare(p.ID[:], after.ID[:]) >= 0) {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, p)
+	}
+	if u.onPage != nil {
+		u.onPage()
+	}
+	return page, nil
+}
+
+func (u *pagedProducts) ExpandProducts(ctx context.Context, products []domain.Product, include domain.ProductInclude) error {
+	return nil
+}
+
+type productStream struct {
+	grpc.ServerStream
+	sent []string
+}
+
+func (s *productStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *productStream) Send(p *catalogpb.Product) error {
+	s.sent = append(s.sent, p.GetId())
+	return nil
+}
+
+func TestStreamProductsSurvivesChanges(t *testing.T) {
+	// Products share creation times, so only the ID orders them
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	u := &pagedProducts{}
+	for i := range 2*streamPageSize + 10 {
+		u.products = append(u.products, domain.Product{ID: uuid.New(), Name: "Mug", CreatedAt: start.Add(time.Duration(i/3) * time.Second)})
+	}
+	want := make([]string, 0, len(u.products))
+	for _, p := range u.products {
+		want = append(want, p.ID.String())
+	}
+
+	pages := 0
+	u.onPage = func() {
+		pages++
+		if pages == 1 {
+			// A product is added in front and one of the first page removed
+			u.products = append(u.products[1:], domain.Product{ID: uuid.New(), CreatedAt: start.Add(time.Hour)})
+		}
+	}
+
+	stream := &productStream{}
+	if err := newProductService(u).StreamProducts(&catalogpb.StreamProductsRequest{}, stream); err != nil {
+		t.Fatalf("StreamProducts: %v", err)
+	}
+
+	if len(stream.sent) != len(want) {
+		t.Fatalf("sent %d products, want %d", len(stream.sent), len(want))
+	}
+	slices.Sort(want)
+	if sent := slices.Sorted(slices.Values(stream.sent)); !slices.Equal(sent, want) {
+		t.Error("sent products differ from the catalog at the start of the stream")
+	}
+	if pages != 3 {
+		t.Errorf("loaded %d pages, want 3", pages)
+	}
+}
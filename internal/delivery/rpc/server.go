@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"product-listing/config"
+	"product-listing/internal/delivery/rpc/catalogpb"
+	"product-listing/internal/domain"
+	"product-listing/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server serves the catalog over gRPC, next to the REST API and on the same
+// usecases, together with the health and reflection services.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+func NewServer(cfg *config.Config, u *usecase.Usecases, limiter domain.RateLimiter) *Server {
+	cc := &callContext{
+		auth:    u.Auth,
+		stores:  u.Store,
+		limiter: limiter,
+		read:    domain.RateLimit{Limit: cfg.RateLimitRead, Window: cfg.RateLimitWindow},
+		write:   domain.RateLimit{Limit: cfg.RateLimitWrite, Window: cfg.RateLimitWindow},
+		reads: map[string]bool{
+			catalogpb.CategoryService_ListCategories_FullMethodName:        true,
+			catalogpb.CategoryService_GetCategory_FullMethodName:           true,
+			catalogpb.CategoryService_GetCategoryBySlug_FullMethodName:     true,
+			catalogpb.ProductService_ListProducts_FullMethodName:           true,
+			catalogpb.ProductService_StreamProducts_FullMethodName:         true,
+			catalogpb.ProductService_GetProduct_FullMethodName:             true,
+			catalogpb.ProductService_ListProductsByCategory_FullMethodName: true,
+			catalogpb.ProductImageService_ListProductImages_FullMethodName: true,
+		},
+		roles: map[string]domain.Role{
+			catalogpb.CategoryService_CreateCategory_FullMethodName:         domain.RoleEditor,
+			catalogpb.CategoryService_UpdateCategory_FullMethodName:         domain.RoleEditor,
+			catalogpb.CategoryService_DeleteCategory_FullMethodName:         domain.RoleEditor,
+			catalogpb.ProductService_CreateProduct_FullMethodName:           domain.RoleEditor,
+			catalogpb.ProductService_UpdateProduct_FullMethodName:           domain.RoleEditor,
+			catalogpb.ProductService_DeleteProduct_FullMethodName:           domain.RoleEditor,
+			catalogpb.ProductImageService_AddImage_FullMethodName:           domain.RoleEditor,
+			catalogpb.ProductImageService_DeleteImage_FullMethodName:        domain.RoleEditor,
+			catalogpb.ProductImageService_SetPrimaryImage_FullMethodName:    domain.RoleEditor,
+			catalogpb.ProductImageService_SetImageVisibility_FullMethodName: domain.RoleEditor,
+			catalogpb.ProductImageService_ApplyOperations_FullMethodName:    domain.RoleEditor,
+		},
+	}
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(cc.unary()),
+		grpc.ChainStreamInterceptor(cc.stream()),
+	)
+	catalogpb.RegisterCategoryServiceServer(srv, newCategoryService(u.Category))
+	catalogpb.RegisterProductServiceServer(srv, newProductService(u.Product))
+	catalogpb.RegisterProductImageServiceServer(srv, newProductImageService(u.ProductImage))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	reflection.Register(srv)
+
+	return &Server{grpc: srv, health: healthServer}
+}
+
+// Serve accepts connections on lis until Shutdown is called.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown reports the server as not serving, then waits for running calls
+// to finish. Calls still running when ctx is done, such as long product
+// streams, are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+const (
+	AuditEntityCategory     = "category"
+	AuditEntityProduct      = "product"
+	AuditEntityProductImage = "product_image"
+)
+
+// AuditEntry records one write. Before is empty for creates and After for
+// deletes.
+type AuditEntry struct {
+	ID          int64
+	StoreID     uuid.UUID
+	Actor       string
+	ActorMethod string
+	Action      string
+	EntityType  string
+	EntityID    string
+	Before      json.RawMessage
+	After       json.RawMessage
+	RequestID   string
+	IP          string
+	CreatedAt   time.Time
+}
+
+// AuditFilter selects entries of the request's store, newest first. Entries
+// with an ID of BeforeID or higher are skipped, which is how pages continue.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	Action     string
+	Since      *time.Time
+	Until      *time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry AuditEntry) error
+	Fetch(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+// RequestMeta identifies the HTTP request a change came from.
+type RequestMeta struct {
+	ID string
+	IP string
+}
+
+type requestMetaKey struct{}
+
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the zero value outside of HTTP requests.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
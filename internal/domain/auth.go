@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+func (r Role) Valid() bool {
+	return roleRank[r] > 0
+}
+
+// Allows reports whether r grants at least the rights of required.
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Role    Role
+	// Method is "api_key" or "jwt"
+	Method string
+	// Store is the ID or slug of the only store the principal may act on.
+	// Empty means any store.
+	Store string
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns nil for anonymous requests.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+type APIKey struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	Role       Role
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	StoreID    *uuid.UUID
+}
+
+type APIKeyInput struct {
+	Name    string
+	Prefix  string
+	KeyHash string
+	Role    Role
+	StoreID *uuid.UUID
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, input APIKeyInput) (*APIKey, error)
+	Fetch(ctx context.Context) ([]APIKey, error)
+	FetchActiveByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+// TokenVerifier checks a bearer token and returns the principal it was issued for.
+type TokenVerifier interface {
+	Verify(token string) (*Principal, error)
+}
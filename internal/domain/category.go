@@ -8,11 +8,11 @@ import (
 )
 
 type Category struct {
-	ID        uuid.UUID
-	Name      string
-	Slug      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type CategoryInput struct {
@@ -21,10 +21,11 @@ type CategoryInput struct {
 }
 
 type CategoryRepository interface {
-	Create(ctx context.Context, c CategoryInput) error
+	Create(ctx context.Context, c CategoryInput) (*Category, error)
 	Fetch(ctx context.Context, limit, offset int) ([]Category, error)
 	FetchById(ctx context.Context, id uuid.UUID) (*Category, error)
 	FetchBySlug(ctx context.Context, slug string) (*Category, error)
+	FetchByIDs(ctx context.Context, ids []uuid.UUID) ([]Category, error)
 	FetchCount(ctx context.Context) (int, error)
 	Update(ctx context.Context, id uuid.UUID, c CategoryInput) error
 	Delete(ctx context.Context, id uuid.UUID) error
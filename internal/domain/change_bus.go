@@ -0,0 +1,28 @@
+package domain
+
+import "github.com/google/uuid"
+
+// CatalogChange is a committed write to a catalog row, announced by the
+// database to every replica. ProductID is the product an image belongs to.
+// Links between products and categories are announced as product updates.
+type CatalogChange struct {
+	StoreID    uuid.UUID
+	EntityType string
+	EntityID   string
+	ProductID  string
+	Action     string
+}
+
+// Missed reports whether the change stands for changes that were lost, for
+// example while the listener reconnected. Anything may have changed then.
+func (c CatalogChange) Missed() bool {
+	return c.EntityType == ""
+}
+
+// ChangeBus hands catalog changes to the components of this process that
+// keep state derived from the catalog, such as caches.
+type ChangeBus interface {
+	// Subscribe returns the changes from now on and a function that ends
+	// the subscription.
+	Subscribe() (<-chan CatalogChange, func())
+}
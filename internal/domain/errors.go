@@ -0,0 +1,48 @@
+package domain
+
+type ErrorKind int
+
+const (
+	ErrorKindInvalid ErrorKind = iota + 1
+	ErrorKindNotFound
+	ErrorKindConflict
+	ErrorKindForbidden
+	ErrorKindUnauthorized
+	ErrorKindUnprocessable
+)
+
+// Error is a business rule violation raised by a usecase. Code is a stable
+// identifier that clients can match on, Message is meant for humans.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewInvalidError(code, message string) *Error {
+	return &Error{Kind: ErrorKindInvalid, Code: code, Message: message}
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrorKindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrorKindConflict, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrorKindForbidden, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrorKindUnauthorized, Code: code, Message: message}
+}
+
+func NewUnprocessableError(code, message string) *Error {
+	return &Error{Kind: ErrorKindUnprocessable, Code: code, Message: message}
+}
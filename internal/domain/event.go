@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeEvent describes a write to the catalog. Type is the entity followed
+// by what happened to it, such as "product.updated". Data is the entity after
+// the change, or before it for deletes. Its JSON form is what publishers send.
+type ChangeEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	StoreID    uuid.UUID       `json:"store_id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+var changeEventSuffixes = map[string]string{
+	AuditActionCreate: "created",
+	AuditActionUpdate: "updated",
+	AuditActionDelete: "deleted",
+}
+
+// ChangeEventType names the event of an audited action, "category.created"
+// for a created category.
+func ChangeEventType(entityType, action string) string {
+	return entityType + "." + changeEventSuffixes[action]
+}
+
+// ChangeEventTypes lists every event type, in a stable order.
+func ChangeEventTypes() []string {
+	var types []string
+	for _, entity := range []string{AuditEntityCategory, AuditEntityProduct, AuditEntityProductImage} {
+		for _, action := range []string{AuditActionCreate, AuditActionUpdate, AuditActionDelete} {
+			types = append(types, ChangeEventType(entity, action))
+		}
+	}
+	return types
+}
+
+// ChangeListener is told about every change while the transaction making it
+// is still open. An error rolls the change back.
+type ChangeListener interface {
+	OnChange(ctx context.Context, event ChangeEvent) error
+}
+
+// EventPublisher hands committed change events to another system. The outbox
+// relay calls it once per event, in order, and calls it again with the same
+// event when it cannot tell whether the last call got through.
+type EventPublisher interface {
+	Publish(ctx context.Context, event ChangeEvent) error
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotentResponse is the stored outcome of the first request sent with
+// an idempotency key. StatusCode is zero while that request is in flight.
+type IdempotentResponse struct {
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Location    string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// IdempotencyRepository stores idempotency keys. The request holding a key is
+// identified by its claim: Complete and Release do nothing once the key is no
+// longer held by that claim.
+type IdempotencyRepository interface {
+	// Reserve claims scope and key for a request until expiresAt, reporting
+	// false when they are already taken.
+	Reserve(ctx context.Context, scope, key, requestHash string, claim uuid.UUID, expiresAt time.Time) (bool, error)
+	Get(ctx context.Context, scope, key string) (*IdempotentResponse, error)
+	// Complete stores the response of the request and keeps it until
+	// expiresAt.
+	Complete(ctx context.Context, scope, key string, claim uuid.UUID, response IdempotentResponse, expiresAt time.Time) error
+	Release(ctx context.Context, scope, key string, claim uuid.UUID) error
+	// ReleaseExpired forgets scope and key if they expired, whoever held them.
+	ReleaseExpired(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
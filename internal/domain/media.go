@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Blob struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// BlobStore holds the media files that product images point at.
+type BlobStore interface {
+	List(ctx context.Context, fn func(blob Blob) error) error
+	// Open reads a blob, a missing one is a not found error.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type MediaGCReport struct {
+	DryRun     bool
+	Scanned    int
+	Referenced int
+	// Recent counts unreferenced blobs kept because they are younger than the
+	// grace period and may belong to an upload still in flight.
+	Recent   int
+	Orphaned []Blob
+	Deleted  int
+}
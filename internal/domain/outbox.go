@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutboxPosition orders the outbox: by writing transaction, then by row.
+type OutboxPosition struct {
+	TxID int64
+	ID   int64
+}
+
+// String is the position as "<tx id>-<id>", the ID of an event in the SSE
+// change feed.
+func (p OutboxPosition) String() string {
+	return fmt.Sprintf("%d-%d", p.TxID, p.ID)
+}
+
+// ParseOutboxPosition reads a position written by String.
+func ParseOutboxPosition(s string) (OutboxPosition, error) {
+	tx, id, ok := strings.Cut(s, "-")
+	if !ok {
+		return OutboxPosition{}, fmt.Errorf("invalid event position %q", s)
+	}
+
+	var p OutboxPosition
+	var err error
+	if p.TxID, err = strconv.ParseInt(tx, 10, 64); err != nil || p.TxID < 0 {
+		return OutboxPosition{}, fmt.Errorf("invalid event position %q", s)
+	}
+	if p.ID, err = strconv.ParseInt(id, 10, 64); err != nil || p.ID < 0 {
+		return OutboxPosition{}, fmt.Errorf("invalid event position %q", s)
+	}
+	return p, nil
+}
+
+type OutboxEvent struct {
+	Position  OutboxPosition
+	Event     ChangeEvent
+	CreatedAt time.Time
+}
+
+// OutboxLag is how far a publisher trails the outbox. OldestPendingAt is nil
+// when it has caught up.
+type OutboxLag struct {
+	Publisher       string
+	Pending         int
+	OldestPendingAt *time.Time
+	RelayedAt       time.Time
+}
+
+type OutboxRepository interface {
+	Create(ctx context.Context, event ChangeEvent) error
+	// LockCursor returns the position of publisher, creating it at the end of
+	// the outbox the first time. It returns false when another transaction
+	// holds the cursor. Call it inside a transaction.
+	LockCursor(ctx context.Context, publisher string) (OutboxPosition, bool, error)
+	// FetchAfter returns events after position whose transaction, and every
+	// one before it, has finished.
+	FetchAfter(ctx context.Context, position OutboxPosition, limit int) ([]OutboxEvent, error)
+	// FetchStoreAfter is FetchAfter for the request's store, limited to
+	// entityTypes unless it is empty.
+	FetchStoreAfter(ctx context.Context, position OutboxPosition, entityTypes []string, limit int) ([]OutboxEvent, error)
+	// Head is a position before every event that may still be written, and
+	// after all but the most recent ones.
+	Head(ctx context.Context) (OutboxPosition, error)
+	SaveCursor(ctx context.Context, publisher string, position OutboxPosition) error
+	FetchLag(ctx context.Context) ([]OutboxLag, error)
+	// DeleteRelayed drops events created before cutoff that every publisher
+	// has passed.
+	DeleteRelayed(ctx context.Context, cutoff time.Time) (int, error)
+}
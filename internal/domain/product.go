@@ -8,15 +8,19 @@ import (
 )
 
 type Product struct {
-	ID              uuid.UUID
-	Name            string
-	Slug            string
-	Description     string
-	Price           float64
-	PrimaryImageURL string
-	Categories      []Category
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Slug            string    `json:"slug"`
+	Description     string    `json:"description"`
+	Price           float64   `json:"price"`
+	PrimaryImageURL string    `json:"primary_image_url"`
+	PrimaryImageID  uuid.UUID `json:"primary_image_id"`
+	// PrimaryImagePrivate marks PrimaryImageURL as needing a signed URL
+	PrimaryImagePrivate bool           `json:"primary_image_private"`
+	Categories          []Category     `json:"categories"`
+	Images              []ProductImage `json:"images,omitempty"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
 }
 
 type ProductInput struct {
@@ -27,11 +31,29 @@ type ProductInput struct {
 	Price       float64
 }
 
+// ProductInclude lists the relations to embed in products on top of the
+// default payload. Categories are always embedded.
+type ProductInclude struct {
+	Images bool
+}
+
+// ProductCursor is the position of a product in listings, which are ordered
+// newest first with the ID breaking ties.
+type ProductCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
 type ProductRepository interface {
-	Create(ctx context.Context, p ProductInput) error
+	Create(ctx context.Context, p ProductInput) (*Product, error)
 	Fetch(ctx context.Context, limit, offset int) ([]Product, error)
+	// FetchAfter returns up to limit products following after, or the first
+	// ones when after is nil. Unlike an offset, the cursor does not skip or
+	// repeat products when others are added or removed meanwhile.
+	FetchAfter(ctx context.Context, after *ProductCursor, limit int) ([]Product, error)
 	FetchById(ctx context.Context, id uuid.UUID) (*Product, error)
 	FetchByCategory(ctx context.Context, cID uuid.UUID) ([]Product, error)
+	FetchByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]Product, error)
 	FetchCount(ctx context.Context) (int, error)
 	Update(ctx context.Context, id uuid.UUID, p ProductInput) error
 	Delete(ctx context.Context, id uuid.UUID) error
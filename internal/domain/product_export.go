@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+// ProductExportFilter narrows an export like the product listings. Without a
+// CategoryID every product of the store is exported.
+type ProductExportFilter struct {
+	CategoryID *uuid.UUID
+}
+
+// ProductExportWriter writes products to an export file, one per row, with
+// their categories and images. Close completes the file.
+type ProductExportWriter interface {
+	Write(p *Product) error
+	Close() error
+}
+
+type ProductExportRepository interface {
+	// Export hands the products of the store in ctx to emit, newest first,
+	// with their categories and images. Rows are read from a server-side
+	// cursor a batch at a time, so a catalog of any size is exported in
+	// constant memory from one snapshot.
+	Export(ctx context.Context, filter ProductExportFilter, emit func(Product) error) error
+}
+
+// FormulaPrefixes start a formula when a spreadsheet opens a CSV file.
+const FormulaPrefixes = "=+-@\t\r"
+
+// EscapeFormula puts a ' in front of CSV text a spreadsheet would run as a
+// formula. Text already starting with a ' gets one too, so UnescapeFormula
+// restores any text exactly.
+func EscapeFormula(s string) string {
+	if s != "" && (s[0] == '\'' || strings.ContainsRune(FormulaPrefixes, rune(s[0]))) {
+		return "'" + s
+	}
+	return s
+}
+
+// UnescapeFormula drops the ' EscapeFormula added. A ' followed by anything
+// else, as typed into a file by hand, is kept.
+func UnescapeFormula(s string) string {
+	if len(s) > 1 && s[0] == '\'' && (s[1] == '\'' || strings.ContainsRune(FormulaPrefixes, rune(s[1]))) {
+		return s[1:]
+	}
+	return s
+}
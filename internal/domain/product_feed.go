@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FeedFormatXML = "xml"
+	FeedFormatTSV = "tsv"
+)
+
+// ProductFeed is the merchant feed of a store. Version grows with every change
+// to its items. A Stale feed, or one whose Fingerprint differs from the
+// renderer's, is rebuilt before it is served again.
+type ProductFeed struct {
+	StoreID     uuid.UUID
+	Fingerprint string
+	Stale       bool
+	Version     int64
+	UpdatedAt   time.Time
+}
+
+// ProductFeedItem is the entry of a product in both feed formats.
+type ProductFeedItem struct {
+	ProductID uuid.UUID
+	XML       string
+	TSV       string
+}
+
+// ProductFeedRenderer turns products into feed entries. The header and footer
+// wrap the entries of a format into a complete file.
+type ProductFeedRenderer interface {
+	// Fingerprint changes whenever rendering the same product would give
+	// another result, such as after a settings change.
+	Fingerprint() string
+	// Render returns false for products that cannot be listed, those without
+	// a public primary image.
+	Render(store *Store, p *Product, primary *ProductImage) (ProductFeedItem, bool)
+	Header(store *Store, format string) string
+	Footer(format string) string
+}
+
+type ProductFeedRepository interface {
+	// Create adds the feed of the store in ctx, stale, unless it exists.
+	Create(ctx context.Context) error
+	// Fetch returns nil when the store has no feed yet.
+	Fetch(ctx context.Context) (*ProductFeed, error)
+	// Lock is Fetch holding the feed until the transaction ends. Call it
+	// inside a transaction.
+	Lock(ctx context.Context) (*ProductFeed, error)
+	MarkStale(ctx context.Context) error
+	// FinishBuild records a rebuild with fingerprint, the feed is no longer
+	// stale.
+	FinishBuild(ctx context.Context, fingerprint string) (*ProductFeed, error)
+	// Touch bumps the version after items changed.
+	Touch(ctx context.Context) error
+	DeleteItems(ctx context.Context) error
+	SaveItems(ctx context.Context, items []ProductFeedItem) error
+	UpsertItem(ctx context.Context, item ProductFeedItem) error
+	DeleteItem(ctx context.Context, productID uuid.UUID) error
+	// FetchItems returns up to limit items after the given product, in order
+	// of product ID.
+	FetchItems(ctx context.Context, afterProductID uuid.UUID, limit int) ([]ProductFeedItem, error)
+}
@@ -2,28 +2,89 @@ package domain
 
 import (
 	"context"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	ImageVisibilityPublic  = "public"
+	ImageVisibilityPrivate = "private"
+)
+
 type ProductImage struct {
-	ID        uuid.UUID `json:"id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Url       string    `json:"url"`
-	IsPrimary bool      `json:"is_primary"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         uuid.UUID `json:"id"`
+	ProductID  uuid.UUID `json:"product_id"`
+	Url        string    `json:"url"`
+	IsPrimary  bool      `json:"is_primary"`
+	Visibility string    `json:"visibility"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type ProductImageInput struct {
-	ProductID uuid.UUID
-	Url       string
-	IsPrimary bool
+	ProductID  uuid.UUID
+	Url        string
+	IsPrimary  bool
+	Visibility string
+}
+
+const (
+	ImageOpAdd        = "add"
+	ImageOpDelete     = "delete"
+	ImageOpSetPrimary = "set_primary"
+	ImageOpReorder    = "reorder"
+)
+
+// ImageOperation is one step of a batch gallery change. Which fields are used
+// depends on Op: add uses ProductID, Url, IsPrimary and Visibility, delete uses
+// ImageID, set_primary uses ProductID and ImageID, reorder uses ProductID and
+// ImageIDs.
+type ImageOperation struct {
+	Op         string
+	ProductID  string
+	ImageID    string
+	ImageIDs   []string
+	Url        string
+	IsPrimary  bool
+	Visibility string
+}
+
+// ImageOperationResult reports the outcome of the operation at Index. Image is
+// set for successful adds. Aborted marks an operation that was not applied
+// because another operation for the same product failed.
+type ImageOperationResult struct {
+	Index   int
+	Op      string
+	Image   *ProductImage
+	Err     error
+	Aborted bool
+}
+
+// ImageMediaPath is the path private images are served from.
+func ImageMediaPath(id uuid.UUID) string {
+	return "/media/images/" + id.String()
+}
+
+// URLSigner signs media paths and checks requests made with signed URLs.
+type URLSigner interface {
+	Sign(path string) string
+	Verify(path string, query url.Values) error
 }
 
 type ProductImageRepository interface {
 	Create(ctx context.Context, input ProductImageInput) (*ProductImage, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*ProductImage, error)
+	GetMediaByID(ctx context.Context, id uuid.UUID) (*ProductImage, error)
 	GetByProductID(ctx context.Context, productID uuid.UUID) ([]ProductImage, error)
+	GetByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]ProductImage, error)
+	GetPrimary(ctx context.Context, productID uuid.UUID) (*ProductImage, error)
+	GetReferencedURLs(ctx context.Context, urls []string) (map[string]bool, error)
+	LockProduct(ctx context.Context, productID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateVisibility(ctx context.Context, id uuid.UUID, visibility string) error
+	UpdatePosition(ctx context.Context, id uuid.UUID, position int) error
+	ClearPrimary(ctx context.Context, productID uuid.UUID) error
 	SetPrimary(ctx context.Context, productID uuid.UUID, imageID uuid.UUID) error
 }
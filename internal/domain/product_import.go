@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+const (
+	ImportJobQueued    = "queued"
+	ImportJobRunning   = "running"
+	ImportJobSucceeded = "succeeded"
+	ImportJobFailed    = "failed"
+)
+
+// ErrImportJobTakenOver is returned when a worker records progress on a job
+// whose lease ran out and that another worker has claimed since.
+var ErrImportJobTakenOver = errors.New("import job was taken over by another worker")
+
+// ImportJob is a bulk product import of one store. Its rows are staged when
+// the file is uploaded and applied in the background in order of Line.
+// ProcessedRows counts rows applied or rejected so far, out of TotalRows.
+// A dry run validates and applies every row but commits nothing, so its
+// counts report what a real run would do.
+type ImportJob struct {
+	ID            uuid.UUID
+	StoreID       uuid.UUID
+	Format        string
+	DryRun        bool
+	Status        string
+	TotalRows     int
+	ProcessedRows int
+	// LastLine is the line of the last row applied
+	LastLine     int
+	CreatedCount int
+	UpdatedCount int
+	FailedCount  int
+	Error        string
+	Actor        string
+	ActorMethod  string
+	RequestID    string
+	IP           string
+	CreatedAt    time.Time
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+}
+
+// ImportRow is one product of an import file. Line is where it starts in the
+// file. Without CategorySlugs an existing product keeps its categories, like
+// an update without category IDs. Error is set on rejected rows.
+type ImportRow struct {
+	Line          int
+	Name          string
+	Slug          string
+	Description   string
+	Price         float64
+	CategorySlugs []string
+	ImageURLs     []string
+	Error         string
+}
+
+// ImportRowReader reads the rows of an import file one at a time. Next returns
+// io.EOF after the last row. A row the reader cannot make sense of comes back
+// with Error set, any other error ends the file.
+type ImportRowReader interface {
+	Next() (ImportRow, error)
+}
+
+// ImportProgress is what applying one batch of rows did to its job. Rejected
+// rows are reported with their Error.
+type ImportProgress struct {
+	LastLine  int
+	Processed int
+	Created   int
+	Updated   int
+	Rejected  []ImportRow
+}
+
+// ImportedProduct is a product written by an import. Created tells a new
+// product from an updated one.
+type ImportedProduct struct {
+	ID      uuid.UUID
+	Slug    string
+	Created bool
+}
+
+type ImportRepository interface {
+	// CreateJob queues a job. Call it in the transaction staging its rows, so
+	// the job is only picked up once all of them are there.
+	CreateJob(ctx context.Context, job ImportJob) (*ImportJob, error)
+	// StageRows stores rows of a job with COPY.
+	StageRows(ctx context.Context, jobID uuid.UUID, rows []ImportRow) error
+	// SetJobTotals records how many rows were staged and how many of them
+	// were rejected right away, which count as processed.
+	SetJobTotals(ctx context.Context, jobID uuid.UUID, totalRows, rejectedRows int) (*ImportJob, error)
+	FetchJobs(ctx context.Context, limit int) ([]ImportJob, error)
+	FetchJobByID(ctx context.Context, id uuid.UUID) (*ImportJob, error)
+	// FetchRejectedRows pages through the rejected rows of a job of the
+	// request's store in order of line, starting after afterLine.
+	FetchRejectedRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]ImportRow, error)
+
+	// ClaimJob takes the oldest queued job of any store, or a running one
+	// whose lease ran out, and holds it for lease. It returns nil when there
+	// is nothing to do.
+	ClaimJob(ctx context.Context, lease time.Duration) (*ImportJob, error)
+	// FetchPendingRows returns the next rows of a job to apply, after
+	// afterLine and not yet rejected.
+	FetchPendingRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]ImportRow, error)
+	// RecordProgress adds progress to the job, which must still be at line
+	// fromLine, and extends its lease.
+	RecordProgress(ctx context.Context, jobID uuid.UUID, fromLine int, progress ImportProgress, lease time.Duration) error
+	FinishJob(ctx context.Context, jobID uuid.UUID, status, message string) error
+	// DeleteFinishedJobs removes jobs finished before the given time with
+	// their rows.
+	DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error)
+
+	// FetchCategoryIDs maps the slugs of existing categories of the store in
+	// ctx to their IDs.
+	FetchCategoryIDs(ctx context.Context, slugs []string) (map[string]uuid.UUID, error)
+	FetchProductsBySlugs(ctx context.Context, slugs []string) (map[string]Product, error)
+	// UpsertProducts creates the products of rows or updates the products
+	// with the same slug, and replaces the categories of rows that list them.
+	UpsertProducts(ctx context.Context, rows []ImportRow, categoryIDs map[string]uuid.UUID) ([]ImportedProduct, error)
+	// AddImages adds the image URLs of rows that products do not have yet.
+	// The first one becomes primary for products without a primary image.
+	AddImages(ctx context.Context, rows []ImportRow, productIDs map[string]uuid.UUID) ([]ProductImage, error)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit is a token bucket holding Limit tokens that refills completely
+// over Window.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, zero when
+	// this one was
+	RetryAfter time.Duration
+}
+
+// RateLimiter takes one token from the bucket named key. Implementations
+// shared between replicas make the limit hold for the whole deployment.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxSitemapURLs is the most URLs the sitemap protocol allows in a sitemap.
+const MaxSitemapURLs = 50000
+
+const (
+	SitemapCategories = "categories"
+	SitemapProducts   = "products"
+)
+
+// SitemapPage is a sitemap of the index: a page of Kind, numbered from 1.
+// LastModified is the latest change to what it lists.
+type SitemapPage struct {
+	Kind         string
+	Number       int
+	LastModified time.Time
+}
+
+// SitemapURL is a page of the storefront with the public images on it.
+type SitemapURL struct {
+	Loc          string
+	LastModified time.Time
+	ImageURLs    []string
+}
+
+// SitemapEntry is a product or category as a sitemap lists it. ImageURLs are
+// the public images of a product, the primary one first.
+type SitemapEntry struct {
+	ID        uuid.UUID
+	Slug      string
+	UpdatedAt time.Time
+	ImageURLs []string
+}
+
+// SitemapRepository reads the products and categories of the store in ctx in
+// order of slug, split into pages of size.
+type SitemapRepository interface {
+	// FetchProductPages returns no pages for a store without products.
+	FetchProductPages(ctx context.Context, size int) ([]SitemapPage, error)
+	FetchCategoryPages(ctx context.Context, size int) ([]SitemapPage, error)
+	FetchProducts(ctx context.Context, page, size int) ([]SitemapEntry, error)
+	FetchCategories(ctx context.Context, page, size int) ([]SitemapEntry, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store is a tenant. Every catalog row belongs to exactly one store and
+// slugs are unique per store.
+type Store struct {
+	ID        uuid.UUID
+	Slug      string
+	Name      string
+	CreatedAt time.Time
+}
+
+type StoreInput struct {
+	Slug string
+	Name string
+}
+
+type StoreRepository interface {
+	Create(ctx context.Context, input StoreInput) (*Store, error)
+	Fetch(ctx context.Context) ([]Store, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*Store, error)
+	FetchBySlug(ctx context.Context, slug string) (*Store, error)
+}
+
+type storeKey struct{}
+
+func ContextWithStore(ctx context.Context, s *Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, s)
+}
+
+// StoreFromContext returns nil when the request has not been bound to a store.
+func StoreFromContext(ctx context.Context) *Store {
+	s, _ := ctx.Value(storeKey{}).(*Store)
+	return s
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Surrogate keys name what a cacheable response contains, so that a CDN can
+// drop exactly the responses a change made stale. Listings also carry the
+// key of the store's listings, which new entities purge.
+func ProductSurrogateKey(id string) string {
+	return "product/" + id
+}
+
+func CategorySurrogateKey(id string) string {
+	return "category/" + id
+}
+
+func ProductListSurrogateKey(storeID uuid.UUID) string {
+	return "store/" + storeID.String() + "/products"
+}
+
+func CategoryListSurrogateKey(storeID uuid.UUID) string {
+	return "store/" + storeID.String() + "/categories"
+}
+
+// SurrogateKeys lists the keys of the responses the change made stale. A
+// missed change has none, as it may have made any response stale.
+func (c CatalogChange) SurrogateKeys() []string {
+	switch c.EntityType {
+	case AuditEntityCategory:
+		return []string{CategorySurrogateKey(c.EntityID), CategoryListSurrogateKey(c.StoreID)}
+	case AuditEntityProduct:
+		// A product that changes categories moves between category listings
+		return []string{ProductSurrogateKey(c.EntityID), ProductListSurrogateKey(c.StoreID)}
+	case AuditEntityProductImage:
+		return []string{ProductSurrogateKey(c.ProductID)}
+	}
+	return nil
+}
+
+// CachePurger drops the responses carrying any of keys from a cache in front
+// of the API, such as a CDN. PurgeAll drops every response.
+type CachePurger interface {
+	Purge(ctx context.Context, keys []string) error
+	PurgeAll(ctx context.Context) error
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription sends the events listed in Events to URL. An entry is
+// an event type, an entity followed by ".*" or "*" for every event.
+type WebhookSubscription struct {
+	ID        uuid.UUID
+	StoreID   uuid.UUID
+	URL       string
+	Events    []string
+	Secret    string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Wants reports whether the subscription receives events of eventType.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	for _, filter := range s.Events {
+		if filter == "*" || filter == eventType {
+			return true
+		}
+		if entity, ok := strings.CutSuffix(filter, ".*"); ok && strings.HasPrefix(eventType, entity+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscriptionInput creates or replaces a subscription. An empty
+// Secret keeps the current one, or generates one on create.
+type WebhookSubscriptionInput struct {
+	URL    string
+	Events []string
+	Secret string
+	Active bool
+}
+
+// WebhookDelivery is one event sent to one subscription. URL and Secret are
+// only set on deliveries claimed for sending.
+type WebhookDelivery struct {
+	ID             int64
+	StoreID        uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	URL            string
+	Secret         string
+}
+
+// WebhookDeliveryFilter selects deliveries of the request's store, newest
+// first, paged like AuditFilter.
+type WebhookDeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	Status         string
+	BeforeID       int64
+	Limit          int
+}
+
+// WebhookAttempt is the outcome of sending a delivery once.
+type WebhookAttempt struct {
+	Status        string
+	NextAttemptAt time.Time
+	StatusCode    int
+	Error         string
+	DeliveredAt   *time.Time
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, input WebhookSubscriptionInput) (*WebhookSubscription, error)
+	FetchSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	FetchActiveSubscriptions(ctx context.Context, storeID uuid.UUID) ([]WebhookSubscription, error)
+	FetchSubscriptionByID(ctx context.Context, id uuid.UUID) (*WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, input WebhookSubscriptionInput) (*WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	CreateDelivery(ctx context.Context, delivery WebhookDelivery) error
+	FetchDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id int64) error
+	// ClaimDeliveries takes up to limit due deliveries of every store and
+	// hides them from other claims for lease.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, id int64, attempt WebhookAttempt) error
+}
+
+// WebhookSender posts a delivery to url signed with secret and returns the
+// status code of the response. Any status other than 2xx is an error.
+type WebhookSender interface {
+	Send(ctx context.Context, url, secret string, delivery WebhookDelivery) (int, error)
+}
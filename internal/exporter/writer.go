@@ -0,0 +1,194 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"product-listing/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listSeparator joins the categories and image URLs of a product in one cell,
+// as the importer splits them.
+const listSeparator = "|"
+
+// columns of CSV and XLSX exports. The importer reads the same names, so an
+// export can be imported again as is.
+var columns = []string{
+	"id",
+	"name",
+	"slug",
+	"description",
+	"price",
+	"category_slugs",
+	"category_names",
+	"image_urls",
+	"primary_image_url",
+	"created_at",
+	"updated_at",
+}
+
+var contentTypes = map[string]string{
+	domain.ExportFormatCSV:    "text/csv; charset=utf-8",
+	domain.ExportFormatNDJSON: "application/x-ndjson",
+	domain.ExportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// NewWriter writes products to w in the given format. Nothing is written to w
+// before the first Write or Close, so a caller can still report an error
+// instead until then.
+func NewWriter(w io.Writer, format string) (domain.ProductExportWriter, error) {
+	switch format {
+	case domain.ExportFormatCSV:
+		return &csvWriter{w: w}, nil
+	case domain.ExportFormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}, nil
+	case domain.ExportFormatXLSX:
+		return &xlsxWriter{w: w}, nil
+	default:
+		return nil, domain.NewInvalidError("invalid_format", "format must be csv, ndjson or xlsx")
+	}
+}
+
+// ContentType is the media type of files of a format NewWriter accepts.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// cell is a column of a product row. Price is the only number.
+type cell struct {
+	text   string
+	number float64
+	isNum  bool
+}
+
+func row(p *domain.Product) []cell {
+	categorySlugs := make([]string, 0, len(p.Categories))
+	categoryNames := make([]string, 0, len(p.Categories))
+	for _, c := range p.Categories {
+		categorySlugs = append(categorySlugs, c.Slug)
+		categoryNames = append(categoryNames, c.Name)
+	}
+
+	return []cell{
+		{text: p.ID.String()},
+		{text: p.Name},
+		{text: p.Slug},
+		{text: p.Description},
+		{number: p.Price, isNum: true},
+		{text: strings.Join(categorySlugs, listSeparator)},
+		{text: strings.Join(categoryNames, listSeparator)},
+		{text: strings.Join(imageURLs(p), listSeparator)},
+		{text: p.PrimaryImageURL},
+		{text: p.CreatedAt.UTC().Format(time.RFC3339)},
+		{text: p.UpdatedAt.UTC().Format(time.RFC3339)},
+	}
+}
+
+func imageURLs(p *domain.Product) []string {
+	urls := make([]string, 0, len(p.Images))
+	for _, img := range p.Images {
+		urls = append(urls, img.Url)
+	}
+	return urls
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// csvWriter escapes text cells a spreadsheet would run as a formula, see
+// domain.EscapeFormula. XLSX cells are typed as text, so they need no escaping.
+type csvWriter struct {
+	w      io.Writer
+	writer *csv.Writer
+	record []string
+}
+
+func (w *csvWriter) start() error {
+	if w.writer != nil {
+		return nil
+	}
+	w.writer = csv.NewWriter(w.w)
+	w.record = make([]string, len(columns))
+	return w.writer.Write(columns)
+}
+
+func (w *csvWriter) Write(p *domain.Product) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	for i, c := range row(p) {
+		if c.isNum {
+			w.record[i] = formatPrice(c.number)
+		} else {
+			w.record[i] = domain.EscapeFormula(c.text)
+		}
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ndjsonRow keeps the lists of a product as arrays, in the shape the importer
+// reads.
+type ndjsonRow struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Slug            string    `json:"slug"`
+	Description     string    `json:"description"`
+	Price           float64   `json:"price"`
+	CategorySlugs   []string  `json:"category_slugs"`
+	CategoryNames   []string  `json:"category_names"`
+	ImageURLs       []string  `json:"image_urls"`
+	PrimaryImageURL string    `json:"primary_image_url"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (w *ndjsonWriter) Write(p *domain.Product) error {
+	out := ndjsonRow{
+		ID:              p.ID.String(),
+		Name:            p.Name,
+		Slug:            p.Slug,
+		Description:     p.Description,
+		Price:           p.Price,
+		CategorySlugs:   make([]string, 0, len(p.Categories)),
+		CategoryNames:   make([]string, 0, len(p.Categories)),
+		ImageURLs:       imageURLs(p),
+		PrimaryImageURL: p.PrimaryImageURL,
+		CreatedAt:       p.CreatedAt.UTC(),
+		UpdatedAt:       p.UpdatedAt.UTC(),
+	}
+	for _, c := range p.Categories {
+		out.CategorySlugs = append(out.CategorySlugs, c.Slug)
+		out.CategoryNames = append(out.CategoryNames, c.Name)
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.w.Flush()
+}
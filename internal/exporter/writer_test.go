@@ -0,0 +1,186 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"product-listing/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var exported = domain.Product{
+	ID:              uuid.MustParse("5d9c8a0e-3a4f-4c41-9a7e-1f2b3c4d5e6f"),
+	Name:            "Mug <large>",
+	Slug:            "mug",
+	Description:     "Holds \"a lot\",\nof tea",
+	Price:           12.5,
+	PrimaryImageURL: "https://cdn.example.com/mug.jpg",
+	Categories:      []domain.Category{{Slug: "gifts", Name: "Gifts"}, {Slug: "kitchen", Name: "Kitchen"}},
+	Images:          []domain.ProductImage{{Url: "https://cdn.example.com/mug.jpg"}, {Url: "https://cdn.example.com/mug-2.jpg"}},
+	CreatedAt:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	UpdatedAt:       time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC),
+}
+
+func export(t *testing.T, format string, products ...domain.Product) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, p := range products {
+		if err := w.Write(&p); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, domain.ExportFormatCSV, exported))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	want := [][]string{columns, {
+		"5d9c8a0e-3a4f-4c41-9a7e-1f2b3c4d5e6f", "Mug <large>", "mug", "Holds \"a lot\",\nof tea", "12.5",
+		"gifts|kitchen", "Gifts|Kitchen", "https://cdn.example.com/mug.jpg|https://cdn.example.com/mug-2.jpg",
+		"https://cdn.example.com/mug.jpg", "2026-01-02T03:04:05Z", "2026-02-03T04:05:06Z",
+	}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+
+	// An empty export still has its header
+	if got := string(export(t, domain.ExportFormatCSV)); got != strings.Join(columns, ",")+"\n" {
+		t.Errorf("empty export = %q", got)
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	p := exported
+	p.Name = "=HYPERLINK(\"https://evil.example.com\")"
+	p.Slug = "+slug"
+	p.Description = "'-5 off"
+	p.Categories = []domain.Category{{Slug: "-sale", Name: "@sale"}}
+	p.Images = []domain.ProductImage{{Url: "=cmd|' /C calc'!A0"}}
+	p.PrimaryImageURL = "@evil"
+
+	records, err := csv.NewReader(bytes.NewReader(export(t, domain.ExportFormatCSV, p))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := []string{
+		p.ID.String(), "'=HYPERLINK(\"https://evil.example.com\")", "'+slug", "''-5 off", "12.5",
+		"'-sale", "'@sale", "'=cmd|' /C calc'!A0", "'@evil", "2026-01-02T03:04:05Z", "2026-02-03T04:05:06Z",
+	}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("cells = %q, want %q", records[1], want)
+	}
+
+	// NDJSON is not opened by spreadsheets and keeps the text as is
+	var line ndjsonRow
+	if err := json.Unmarshal(export(t, domain.ExportFormatNDJSON, p), &line); err != nil || line.Name != p.Name {
+		t.Errorf("NDJSON name = %q, %v, want %q", line.Name, err, p.Name)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var row map[string]any
+	if err := json.Unmarshal(export(t, domain.ExportFormatNDJSON, exported), &row); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if row["slug"] != "mug" || row["price"] != 12.5 || len(row["image_urls"].([]any)) != 2 {
+		t.Errorf("row = %v", row)
+	}
+	if got := row["category_slugs"].([]any); len(got) != 2 || got[1] != "kitchen" {
+		t.Errorf("category_slugs = %v", got)
+	}
+}
+
+type xlsxCell struct {
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+// worksheet checks the parts of an XLSX export and returns the cells of its
+// rows.
+func worksheet(t *testing.T, data []byte) [][]xlsxCell {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	parts := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		parts[f.Name] = f
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if parts[name] == nil {
+			t.Errorf("part %s is missing", name)
+		}
+	}
+
+	sheet, err := parts["xl/worksheets/sheet1.xml"].Open()
+	if err != nil {
+		t.Fatalf("open worksheet: %v", err)
+	}
+	var doc struct {
+		Rows []struct {
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.NewDecoder(sheet).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("invalid worksheet: %v", err)
+	}
+
+	rows := make([][]xlsxCell, len(doc.Rows))
+	for i, r := range doc.Rows {
+		rows[i] = r.Cells
+	}
+	return rows
+}
+
+func TestXLSXWriter(t *testing.T) {
+	rows := worksheet(t, export(t, domain.ExportFormatXLSX, exported, exported))
+	if len(rows) != 3 {
+		t.Fatalf("worksheet has %d rows, want a header and 2 products", len(rows))
+	}
+	cells := rows[1]
+	if cells[1].Inline != "Mug <large>" || cells[3].Inline != "Holds \"a lot\",\nof tea" {
+		t.Errorf("text cells = %+v", cells[:4])
+	}
+	if cells[4].Type != "" || cells[4].Value != "12.5" {
+		t.Errorf("price cell = %+v, want a number", cells[4])
+	}
+}
+
+func TestXLSXWriterKeepsTextAsIs(t *testing.T) {
+	p := exported
+	p.Name = "-20% sale"
+
+	// Inline strings are never evaluated, so nothing is escaped
+	if cells := worksheet(t, export(t, domain.ExportFormatXLSX, p))[1]; cells[1].Inline != p.Name {
+		t.Errorf("name = %q, want %q", cells[1].Inline, p.Name)
+	}
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(io.Discard, "pdf"); err == nil {
+		t.Error("NewWriter accepted pdf")
+	}
+}
@@ -0,0 +1,127 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"product-listing/internal/domain"
+	"unicode/utf8"
+)
+
+const (
+	// maxXLSXRows is the most rows a worksheet holds, the header included.
+	maxXLSXRows = 1 << 20
+	// maxXLSXCellLength is the most characters a cell holds, longer text
+	// is cut short.
+	maxXLSXCellLength = 32767
+)
+
+// xlsxParts are the parts of a workbook with one worksheet, apart from the
+// worksheet itself. Cells are inline strings, so no shared strings table is
+// needed and rows can be written as they come.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Products" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+const (
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams a workbook. The worksheet is the last part of the zip
+// file and is written row by row, compressed on the fly.
+type xlsxWriter struct {
+	w     io.Writer
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+func (w *xlsxWriter) start() error {
+	if w.zip != nil {
+		return nil
+	}
+	w.zip = zip.NewWriter(w.w)
+
+	for _, part := range xlsxParts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := w.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriter(f)
+	if _, err := w.sheet.WriteString(xlsxSheetStart); err != nil {
+		return err
+	}
+
+	header := make([]cell, 0, len(columns))
+	for _, name := range columns {
+		header = append(header, cell{text: name})
+	}
+	return w.writeRow(header)
+}
+
+func (w *xlsxWriter) Write(p *domain.Product) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	return w.writeRow(row(p))
+}
+
+func (w *xlsxWriter) writeRow(cells []cell) error {
+	if w.rows == maxXLSXRows {
+		return fmt.Errorf("a worksheet holds at most %d rows, export as csv or ndjson instead", maxXLSXRows)
+	}
+	w.rows++
+
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for _, c := range cells {
+		if c.isNum {
+			fmt.Fprintf(w.sheet, `<c><v>%s</v></c>`, formatPrice(c.number))
+			continue
+		}
+		w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		text := c.text
+		if utf8.RuneCountInString(text) > maxXLSXCellLength {
+			text = string([]rune(text)[:maxXLSXCellLength])
+		}
+		// EscapeText also replaces characters XML cannot hold
+		if err := xml.EscapeText(w.sheet, []byte(text)); err != nil {
+			return err
+		}
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if _, err := w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
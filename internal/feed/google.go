@@ -0,0 +1,211 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"product-listing/config"
+	"product-listing/internal/domain"
+	"product-listing/internal/storefront"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rendererVersion is part of the fingerprint. Bump it when a change to the
+// renderer alters existing items, so stored feeds are rebuilt.
+const rendererVersion = "google/1"
+
+const (
+	maxTitleLength       = 150
+	maxDescriptionLength = 5000
+)
+
+// tsvColumns are the attribute names Merchant Center expects as the header
+// of a tab separated feed.
+var tsvColumns = []string{
+	"id",
+	"title",
+	"description",
+	"link",
+	"image_link",
+	"availability",
+	"price",
+	"condition",
+	"google_product_category",
+	"product_type",
+	"identifier_exists",
+}
+
+// GoogleConfig holds the settings of a Google Merchant Center feed.
+// Categories maps category slugs to Google product taxonomy IDs or paths.
+type GoogleConfig struct {
+	Links      storefront.Links
+	Currency   string
+	Categories map[string]string
+}
+
+type googleRenderer struct {
+	cfg         GoogleConfig
+	fingerprint string
+}
+
+// NewGoogleRenderer renders products as items of a Google Merchant Center
+// feed, in RSS 2.0 with the g: namespace and tab separated.
+func NewGoogleRenderer(cfg GoogleConfig) domain.ProductFeedRenderer {
+	return &googleRenderer{cfg: cfg, fingerprint: fingerprint(cfg)}
+}
+
+// NewRenderer is the renderer of the feeds configured in cfg, nil when
+// feeds are turned off.
+func NewRenderer(cfg *config.Config) domain.ProductFeedRenderer {
+	if cfg.StorefrontBaseURL == "" {
+		return nil
+	}
+	return NewGoogleRenderer(GoogleConfig{
+		Links:      storefront.Links{BaseURL: cfg.StorefrontBaseURL, ProductPath: cfg.StorefrontProductPath},
+		Currency:   cfg.FeedCurrency,
+		Categories: cfg.FeedGoogleCategories,
+	})
+}
+
+func fingerprint(cfg GoogleConfig) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", rendererVersion, cfg.Links.BaseURL, cfg.Links.ProductPath, cfg.Currency)
+
+	slugs := make([]string, 0, len(cfg.Categories))
+	for slug := range cfg.Categories {
+		slugs = append(slugs, slug)
+	}
+	slices.Sort(slugs)
+	for _, slug := range slugs {
+		fmt.Fprintf(h, "%s=%s\n", slug, cfg.Categories[slug])
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func (r *googleRenderer) Fingerprint() string {
+	return r.fingerprint
+}
+
+// attribute is a field of an item. product_type is the only one that repeats.
+type attribute struct {
+	name   string
+	values []string
+}
+
+func (r *googleRenderer) Render(store *domain.Store, p *domain.Product, primary *domain.ProductImage) (domain.ProductFeedItem, bool) {
+	if primary == nil || primary.Visibility == domain.ImageVisibilityPrivate {
+		return domain.ProductFeedItem{}, false
+	}
+
+	description := p.Description
+	if strings.TrimSpace(description) == "" {
+		description = p.Name
+	}
+
+	// Of the categories in order of slug, the first mapped one is the item's,
+	// so an item renders the same however its categories were loaded
+	categories := slices.Clone(p.Categories)
+	slices.SortFunc(categories, func(a, b domain.Category) int { return strings.Compare(a.Slug, b.Slug) })
+
+	var googleCategory string
+	productTypes := make([]string, 0, len(categories))
+	for _, c := range categories {
+		if googleCategory == "" {
+			googleCategory = r.cfg.Categories[c.Slug]
+		}
+		productTypes = append(productTypes, c.Name)
+	}
+
+	attributes := []attribute{
+		{"id", []string{p.ID.String()}},
+		{"title", []string{truncate(p.Name, maxTitleLength)}},
+		{"description", []string{truncate(description, maxDescriptionLength)}},
+		{"link", []string{r.cfg.Links.Product(store, p.Slug, p.ID)}},
+		{"image_link", []string{primary.Url}},
+		{"availability", []string{"in_stock"}},
+		{"price", []string{strconv.FormatFloat(p.Price, 'f', 2, 64) + " " + r.cfg.Currency}},
+		{"condition", []string{"new"}},
+		{"google_product_category", optional(googleCategory)},
+		{"product_type", productTypes},
+		{"identifier_exists", []string{"no"}},
+	}
+
+	return domain.ProductFeedItem{
+		ProductID: p.ID,
+		XML:       renderXML(attributes),
+		TSV:       renderTSV(attributes),
+	}, true
+}
+
+func renderXML(attributes []attribute) string {
+	var b strings.Builder
+	b.WriteString("<item>\n")
+	for _, a := range attributes {
+		for _, v := range a.values {
+			fmt.Fprintf(&b, "  <g:%s>", a.name)
+			// EscapeText also replaces characters XML cannot hold
+			_ = xml.EscapeText(&b, []byte(v))
+			fmt.Fprintf(&b, "</g:%s>\n", a.name)
+		}
+	}
+	b.WriteString("</item>\n")
+	return b.String()
+}
+
+// renderTSV gives a line of the tab separated feed, which has no quoting:
+// tabs and line breaks in values become spaces, repeated values are joined
+// with commas.
+func renderTSV(attributes []attribute) string {
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+	fields := make([]string, 0, len(attributes))
+	for _, a := range attributes {
+		fields = append(fields, clean.Replace(strings.Join(a.values, ",")))
+	}
+	return strings.Join(fields, "\t") + "\n"
+}
+
+func (r *googleRenderer) Header(store *domain.Store, format string) string {
+	if format == domain.FeedFormatTSV {
+		return strings.Join(tsvColumns, "\t") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">` + "\n<channel>\n")
+	for _, field := range [][2]string{
+		{"title", store.Name},
+		{"link", r.cfg.Links.Home(store)},
+		{"description", "Products of " + store.Name},
+	} {
+		fmt.Fprintf(&b, "<%s>", field[0])
+		_ = xml.EscapeText(&b, []byte(field[1]))
+		fmt.Fprintf(&b, "</%s>\n", field[0])
+	}
+	return b.String()
+}
+
+func (r *googleRenderer) Footer(format string) string {
+	if format == domain.FeedFormatTSV {
+		return ""
+	}
+	return "</channel>\n</rss>\n"
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+func optional(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"encoding/xml"
+	"product-listing/internal/domain"
+	"product-listing/internal/storefront"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testRenderer() domain.ProductFeedRenderer {
+	return NewGoogleRenderer(GoogleConfig{
+		Links:      storefront.Links{BaseURL: "https://{store}.example.com", ProductPath: "/products/{slug}"},
+		Currency:   "EUR",
+		Categories: map[string]string{"kitchen": "638", "garden": "689"},
+	})
+}
+
+func TestGoogleRendererItem(t *testing.T) {
+	store := &domain.Store{Slug: "main", Name: "Main & Co"}
+	p := &domain.Product{
+		ID:          uuid.New(),
+		Name:        "Mug",
+		Slug:        "mug",
+		Description: "A mug\tfor <tea>",
+		Price:       12.5,
+		Categories:  []domain.Category{{Slug: "kitchen", Name: "Kitchen"}, {Slug: "garden", Name: "Garden"}},
+	}
+	image := &domain.ProductImage{Url: "https://cdn.example.com/mug.jpg", Visibility: domain.ImageVisibilityPublic}
+
+	item, ok := testRenderer().Render(store, p, image)
+	if !ok {
+		t.Fatal("Render left out a product with a public primary image")
+	}
+
+	for _, want := range []string{
+		"<g:link>https://main.example.com/products/mug</g:link>",
+		"<g:price>12.50 EUR</g:price>",
+		"<g:description>A mug&#x9;for &lt;tea&gt;</g:description>",
+		"<g:google_product_category>689</g:google_product_category>",
+		"<g:product_type>Garden</g:product_type>\n  <g:product_type>Kitchen</g:product_type>",
+	} {
+		if !strings.Contains(item.XML, want) {
+			t.Errorf("XML item lacks %q:\n%s", want, item.XML)
+		}
+	}
+
+	fields := strings.Split(strings.TrimSuffix(item.TSV, "\n"), "\t")
+	if len(fields) != len(tsvColumns) {
+		t.Fatalf("TSV line has %d fields, want %d: %q", len(fields), len(tsvColumns), item.TSV)
+	}
+	if fields[2] != "A mug for <tea>" || fields[9] != "Garden,Kitchen" {
+		t.Errorf("TSV fields = %q", fields)
+	}
+
+	r := testRenderer()
+	doc := r.Header(store, domain.FeedFormatXML) + item.XML + r.Footer(domain.FeedFormatXML)
+	var rss struct {
+		Items []struct {
+			ID string `xml:"http://base.google.com/ns/1.0 id"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal([]byte(doc), &rss); err != nil {
+		t.Fatalf("feed is not well-formed: %v", err)
+	}
+	if len(rss.Items) != 1 || rss.Items[0].ID != p.ID.String() {
+		t.Errorf("items = %+v", rss.Items)
+	}
+}
+
+func TestGoogleRendererLeavesOutPrivateImages(t *testing.T) {
+	store := &domain.Store{Slug: "main"}
+	p := &domain.Product{ID: uuid.New(), Name: "Mug", Slug: "mug"}
+
+	if _, ok := testRenderer().Render(store, p, nil); ok {
+		t.Error("rendered a product without a primary image")
+	}
+	private := &domain.ProductImage{Url: "https://cdn.example.com/mug.jpg", Visibility: domain.ImageVisibilityPrivate}
+	if _, ok := testRenderer().Render(store, p, private); ok {
+		t.Error("rendered a product with a private primary image")
+	}
+}
+
+func TestGoogleRendererFingerprint(t *testing.T) {
+	other := NewGoogleRenderer(GoogleConfig{
+		Links:      storefront.Links{BaseURL: "https://{store}.example.com", ProductPath: "/products/{slug}"},
+		Currency:   "EUR",
+		Categories: map[string]string{"kitchen": "638"},
+	})
+	if testRenderer().Fingerprint() != testRenderer().Fingerprint() {
+		t.Error("fingerprint differs for the same settings")
+	}
+	if other.Fingerprint() == testRenderer().Fingerprint() {
+		t.Error("fingerprint ignores the category mapping")
+	}
+}
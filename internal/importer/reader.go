@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"product-listing/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// listSeparator separates the category slugs and image URLs in a CSV cell.
+const listSeparator = "|"
+
+// maxLineSize is the longest NDJSON line accepted.
+const maxLineSize = 1 << 20
+
+// requiredColumns must be in the header of a CSV file. category_slugs and
+// image_urls are optional, other columns are ignored.
+var requiredColumns = []string{"name", "slug", "price"}
+
+// NewReader reads rows of the given format from r. A CSV file starts with a
+// header naming its columns.
+func NewReader(r io.Reader, format string) (domain.ImportRowReader, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return newCSVReader(r)
+	case domain.ImportFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, domain.NewInvalidError("invalid_format", "format must be csv or ndjson")
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.NewInvalidError("invalid_header", "the file is empty")
+	}
+	if err != nil {
+		return nil, domain.NewInvalidError("invalid_header", "invalid header: "+err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets like to start files with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, domain.NewInvalidError("invalid_header", "missing column: "+name)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (domain.ImportRow, error) {
+	record, err := r.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.ImportRow{Line: parseErr.StartLine, Error: parseErr.Err.Error()}, nil
+	}
+	if err != nil {
+		return domain.ImportRow{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := domain.ImportRow{
+		Line:          line,
+		Name:          r.cell(record, "name"),
+		Slug:          r.cell(record, "slug"),
+		Description:   r.cell(record, "description"),
+		CategorySlugs: splitList(r.cell(record, "category_slugs")),
+		ImageURLs:     splitList(r.cell(record, "image_urls")),
+	}
+
+	price := r.cell(record, "price")
+	if row.Price, err = strconv.ParseFloat(price, 64); err != nil {
+		row.Error = "invalid price: " + price
+	}
+	return row, nil
+}
+
+// cell is the trimmed value of a column, empty when the record is too short.
+// The ' an export puts in front of text that looks like a formula is dropped.
+func (r *csvReader) cell(record []string, column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return domain.UnescapeFormula(strings.TrimSpace(record[i]))
+}
+
+func splitList(cell string) []string {
+	var items []string
+	for item := range strings.SplitSeq(cell, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ndjsonRow is one line of an NDJSON file.
+type ndjsonRow struct {
+	Name          string   `json:"name"`
+	Slug          string   `json:"slug"`
+	Description   string   `json:"description"`
+	Price         *float64 `json:"price"`
+	CategorySlugs []string `json:"category_slugs"`
+	ImageURLs     []string `json:"image_urls"`
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Next() (domain.ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var parsed ndjsonRow
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return domain.ImportRow{Line: r.line, Error: "invalid JSON: " + err.Error()}, nil
+		}
+
+		row := domain.ImportRow{
+			Line:          r.line,
+			Name:          strings.TrimSpace(parsed.Name),
+			Slug:          strings.TrimSpace(parsed.Slug),
+			Description:   strings.TrimSpace(parsed.Description),
+			CategorySlugs: parsed.CategorySlugs,
+			ImageURLs:     parsed.ImageURLs,
+		}
+		if parsed.Price == nil {
+			row.Error = "price is required"
+		} else {
+			row.Price = *parsed.Price
+		}
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return domain.ImportRow{}, domain.NewInvalidError("line_too_long", fmt.Sprintf("line %d is longer than %d bytes", r.line+1, maxLineSize))
+		}
+		return domain.ImportRow{}, err
+	}
+	return domain.ImportRow{}, io.EOF
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"product-listing/internal/domain"
+	"product-listing/internal/exporter"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func readAll(t *testing.T, r domain.ImportRowReader) []domain.ImportRow {
+	t.Helper()
+	var rows []domain.ImportRow
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	file := "\ufeffSlug,Name,Price,category_slugs,image_urls,notes\n" +
+		"mug,Mug,12.5,kitchen | gifts,https://cdn.example.com/mug.jpg,ignored\n" +
+		"\"tea\",\"Tea,\nloose\",abc,,\n" +
+		"short,Short\n"
+
+	r, err := NewReader(strings.NewReader(file), domain.ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	want := []domain.ImportRow{
+		{Line: 2, Name: "Mug", Slug: "mug", Price: 12.5, CategorySlugs: []string{"kitchen", "gifts"}, ImageURLs: []string{"https://cdn.example.com/mug.jpg"}},
+		{Line: 3, Name: "Tea,\nloose", Slug: "tea", Error: "invalid price: abc"},
+		{Line: 5, Name: "Short", Slug: "short", Error: "invalid price: "},
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestCSVReaderUnescapesFormulas(t *testing.T) {
+	file := "name,slug,price,description,category_slugs\n" +
+		"'=SUM(A1),'+sum,1,''-5 off,'-sale|gifts\n" +
+		"'quoted',quoted,1,',\n"
+
+	r, err := NewReader(strings.NewReader(file), domain.ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	want := []domain.ImportRow{
+		{Line: 2, Name: "=SUM(A1)", Slug: "+sum", Price: 1, Description: "'-5 off", CategorySlugs: []string{"-sale", "gifts"}},
+		{Line: 3, Name: "'quoted'", Slug: "quoted", Price: 1, Description: "'"},
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestCSVReaderReadsExports(t *testing.T) {
+	p := domain.Product{
+		ID:          uuid.New(),
+		Name:        "'=not a formula",
+		Slug:        "mug",
+		Description: "''",
+		Price:       2,
+		Images:      []domain.ProductImage{{Url: "=https://cdn.example.com/mug.jpg"}},
+	}
+	var buf bytes.Buffer
+	w, _ := exporter.NewWriter(&buf, domain.ExportFormatCSV)
+	if err := w.Write(&p); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := NewReader(&buf, domain.ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	want := []domain.ImportRow{{Line: 2, Name: p.Name, Slug: p.Slug, Description: p.Description, Price: p.Price, ImageURLs: []string{p.Images[0].Url}}}
+	if got := readAll(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestCSVReaderRequiresColumns(t *testing.T) {
+	_, err := NewReader(strings.NewReader("name,price\nMug,1\n"), domain.ImportFormatCSV)
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != "invalid_header" {
+		t.Errorf("err = %v, want invalid_header", err)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	file := `{"name": " Mug ", "slug": "mug", "price": 12.5, "category_slugs": ["kitchen"]}` + "\n" +
+		"\n" +
+		`{"name": "Tea", "slug": "tea"}` + "\n" +
+		`{"name": ` + "\n"
+
+	r, err := NewReader(strings.NewReader(file), domain.ImportFormatNDJSON)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	rows := readAll(t, r)
+	if len(rows) != 3 {
+		t.Fatalf("read %d rows, want 3", len(rows))
+	}
+	if want := (domain.ImportRow{Line: 1, Name: "Mug", Slug: "mug", Price: 12.5, CategorySlugs: []string{"kitchen"}}); !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("row = %+v, want %+v", rows[0], want)
+	}
+	if rows[1].Line != 3 || rows[1].Error != "price is required" {
+		t.Errorf("row = %+v, want line 3 without a price", rows[1])
+	}
+	if rows[2].Line != 4 || !strings.HasPrefix(rows[2].Error, "invalid JSON") {
+		t.Errorf("row = %+v, want line 4 with invalid JSON", rows[2])
+	}
+}
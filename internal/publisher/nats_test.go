@@ -0,0 +1,147 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"product-listing/internal/domain"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type natsMsg struct {
+	subject string
+	header  string
+	payload []byte
+}
+
+// fakeNATS accepts one connection and passes on the options of its CONNECT
+// and every message it publishes, answering PINGs as a NATS server would.
+func fakeNATS(t *testing.T, info string) (string, <-chan natsMsg, <-chan map[string]any) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	msgs := make(chan natsMsg, 10)
+	connects := make(chan map[string]any, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		io.WriteString(conn, "INFO "+info+"\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch fields[0] {
+			case "CONNECT":
+				var options map[string]any
+				json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &options)
+				connects <- options
+			case "PING":
+				io.WriteString(conn, "PONG\r\n")
+			case "PUB", "HPUB":
+				total, _ := strconv.Atoi(fields[len(fields)-1])
+				headerLen := 0
+				if fields[0] == "HPUB" {
+					headerLen, _ = strconv.Atoi(fields[2])
+				}
+				body := make([]byte, total+2)
+				io.ReadFull(r, body)
+				msgs <- natsMsg{subject: fields[1], header: string(body[:headerLen]), payload: body[headerLen:total]}
+			}
+		}
+	}()
+
+	return "nats://" + lis.Addr().String(), msgs, connects
+}
+
+func TestNATSPublisher(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    string
+		headers bool
+	}{
+		{"with headers", `{"headers":true}`, true},
+		{"without headers", `{}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, msgs, connects := fakeNATS(t, tt.info)
+			p, err := NewNATSPublisher(url, "catalog", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			event := domain.ChangeEvent{ID: uuid.New(), StoreID: uuid.New(), Type: "product.created", Data: json.RawMessage(`{"name":"Lamp"}`)}
+			if err := p.Publish(context.Background(), event); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+
+			if options := <-connects; options["headers"] != tt.headers {
+				t.Errorf("CONNECT headers = %v, want %v", options["headers"], tt.headers)
+			}
+
+			msg := <-msgs
+			if want := "catalog." + event.StoreID.String() + ".product.created"; msg.subject != want {
+				t.Errorf("subject = %s, want %s", msg.subject, want)
+			}
+			if got := strings.Contains(msg.header, "Nats-Msg-Id: "+event.ID.String()); got != tt.headers {
+				t.Errorf("header = %q, want message id: %v", msg.header, tt.headers)
+			}
+
+			var got domain.ChangeEvent
+			if err := json.Unmarshal(msg.payload, &got); err != nil {
+				t.Fatalf("decode payload %q: %v", msg.payload, err)
+			}
+			if got.ID != event.ID || string(got.Data) != string(event.Data) {
+				t.Errorf("payload = %s", msg.payload)
+			}
+		})
+	}
+}
+
+func TestNATSPublisherReportsServerErrors(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "INFO {}\r\n")
+		bufio.NewReader(conn).ReadString('\n')
+		io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+	}()
+
+	p, _ := NewNATSPublisher("nats://"+lis.Addr().String(), "catalog", time.Second)
+	err = p.Publish(context.Background(), domain.ChangeEvent{ID: uuid.New()})
+	if err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("Publish = %v, want the server's error", err)
+	}
+}
@@ -0,0 +1,27 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"product-listing/internal/domain"
+	"sync"
+)
+
+type writerPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterPublisher writes each event to w as a line of JSON, for piping the
+// API's output into a log shipper or for local debugging.
+func NewWriterPublisher(w io.Writer) domain.EventPublisher {
+	return &writerPublisher{enc: json.NewEncoder(w)}
+}
+
+func (p *writerPublisher) Publish(ctx context.Context, event domain.ChangeEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.enc.Encode(event)
+}
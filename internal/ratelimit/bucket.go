@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"math"
+	"product-listing/internal/domain"
+	"time"
+)
+
+// refillRate is the number of tokens a bucket gains per second.
+func refillRate(limit domain.RateLimit) float64 {
+	return float64(limit.Limit) / limit.Window.Seconds()
+}
+
+// result describes a bucket left holding tokens after the request.
+func result(limit domain.RateLimit, tokens float64, allowed bool) domain.RateLimitResult {
+	rate := refillRate(limit)
+
+	res := domain.RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Limit) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return res
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
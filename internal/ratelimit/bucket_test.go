@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestResult(t *testing.T) {
+	// Ten tokens a minute refill one every six seconds
+	limit := domain.RateLimit{Limit: 10, Window: time.Minute}
+
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    domain.RateLimitResult
+	}{
+		{"full after the request", 10, true, domain.RateLimitResult{Allowed: true, Remaining: 10}},
+		{"one taken from a full bucket", 9, true, domain.RateLimitResult{Allowed: true, Remaining: 9, Reset: 6 * time.Second}},
+		{"last token taken", 0, true, domain.RateLimitResult{Allowed: true, Remaining: 0, Reset: time.Minute}},
+		{"partial token left", 2.5, true, domain.RateLimitResult{Allowed: true, Remaining: 2, Reset: 45 * time.Second}},
+		{"empty", 0, false, domain.RateLimitResult{Remaining: 0, Reset: time.Minute, RetryAfter: 6 * time.Second}},
+		{"almost a token", 0.75, false, domain.RateLimitResult{Remaining: 0, Reset: 55500 * time.Millisecond, RetryAfter: 1500 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := result(limit, tt.tokens, tt.allowed); got != tt.want {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
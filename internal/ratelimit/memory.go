@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"product-listing/internal/domain"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will be full again and can be forgotten
+	full time.Time
+}
+
+// memoryLimiter keeps buckets in process memory. Limits only hold per
+// replica, so use it for single instance deployments and development.
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() domain.RateLimiter {
+	return &memoryLimiter{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	now := time.Now()
+	rate := refillRate(limit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Limit), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Limit), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	res := result(limit, b.tokens, allowed)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep drops buckets that have refilled, as a fresh bucket is identical.
+func (l *memoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.After(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
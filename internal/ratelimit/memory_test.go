@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+)
+
+// rewind moves the last use of a bucket back by d, as if d had passed.
+func rewind(l *memoryLimiter, key string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets[key].updated = l.buckets[key].updated.Add(-d)
+}
+
+func TestMemoryLimiterRefills(t *testing.T) {
+	l := NewMemoryLimiter().(*memoryLimiter)
+	limit := domain.RateLimit{Limit: 3, Window: 3 * time.Second}
+	ctx := context.Background()
+
+	allow := func() domain.RateLimitResult {
+		t.Helper()
+		res, err := l.Allow(ctx, "read:ip:1.2.3.4", limit)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		return res
+	}
+
+	for want := 2; want >= 0; want-- {
+		if res := allow(); !res.Allowed || res.Remaining != want {
+			t.Fatalf("Allow = %+v, want allowed with %d remaining", res, want)
+		}
+	}
+	res := allow()
+	if res.Allowed || res.RetryAfter <= 0 || res.RetryAfter > time.Second {
+		t.Fatalf("Allow on an empty bucket = %+v, want a retry within a second", res)
+	}
+
+	// One token a second comes back
+	rewind(l, "read:ip:1.2.3.4", 1500*time.Millisecond)
+	if res := allow(); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("Allow after 1.5s = %+v, want allowed with 0 remaining", res)
+	}
+
+	// A bucket idle for longer than the window holds no more than the limit
+	rewind(l, "read:ip:1.2.3.4", time.Hour)
+	if res := allow(); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("Allow after an hour = %+v, want allowed with 2 remaining", res)
+	}
+
+	// Other keys have buckets of their own
+	if res, _ := l.Allow(ctx, "write:ip:1.2.3.4", limit); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("Allow on another key = %+v, want a full bucket", res)
+	}
+}
+
+func TestMemoryLimiterSweepsFullBuckets(t *testing.T) {
+	l := NewMemoryLimiter().(*memoryLimiter)
+	limit := domain.RateLimit{Limit: 3, Window: time.Minute}
+	ctx := context.Background()
+
+	_, _ = l.Allow(ctx, "idle", limit)
+	_, _ = l.Allow(ctx, "busy", limit)
+	l.buckets["idle"].full = time.Now().Add(-time.Second)
+	l.lastSweep = time.Now().Add(-2 * sweepInterval)
+
+	_, _ = l.Allow(ctx, "busy", limit)
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("full bucket kept after the sweep")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("bucket in use dropped by the sweep")
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/op/go-logging"
+)
+
+var rateLimitLog = logging.MustGetLogger("ratelimit")
+
+// idleBucketAge is how long a bucket may go unused before it is deleted. It
+// must exceed the longest configured window, after which an idle bucket is
+// full and no different from a missing one.
+const idleBucketAge = 24 * time.Hour
+
+// postgresLimiter keeps buckets in the database so every replica draws from
+// the same bucket. Each request costs one upsert.
+type postgresLimiter struct {
+	db *db.Queries
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresLimiter(database *config.Database) domain.RateLimiter {
+	return &postgresLimiter{db: db.New(database.Pool), lastSweep: time.Now()}
+}
+
+func (l *postgresLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	row, err := l.db.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:      key,
+		Capacity: float64(limit.Limit),
+		Rate:     refillRate(limit),
+	})
+	if err != nil {
+		return domain.RateLimitResult{}, err
+	}
+
+	l.maybeSweep()
+	return result(limit, row.Tokens, row.Allowed), nil
+}
+
+// maybeSweep deletes idle buckets in the background at most once per
+// sweepInterval on this replica.
+func (l *postgresLimiter) maybeSweep() {
+	l.mu.Lock()
+	due := time.Since(l.lastSweep) > sweepInterval
+	if due {
+		l.lastSweep = time.Now()
+	}
+	l.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		idleSince := pgtype.Timestamptz{Time: time.Now().Add(-idleBucketAge), Valid: true}
+		if _, err := l.db.DeleteIdleRateLimitBuckets(context.Background(), idleSince); err != nil {
+			rateLimitLog.Warningf("Failed to delete idle rate limit buckets: %v", err)
+		}
+	}()
+}
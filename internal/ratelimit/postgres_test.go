@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// bucketRow is the row TakeRateLimitToken returns.
+type bucketRow struct {
+	tokens  float64
+	allowed bool
+}
+
+func (r bucketRow) Scan(dest ...any) error {
+	*dest[0].(*float64) = r.tokens
+	*dest[1].(*bool) = r.allowed
+	return nil
+}
+
+// bucketDB answers TakeRateLimitToken with row and records its arguments.
+type bucketDB struct {
+	db.DBTX
+	row  bucketRow
+	args []any
+}
+
+func (d *bucketDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	d.args = args
+	return d.row
+}
+
+func TestPostgresLimiter(t *testing.T) {
+	limit := domain.RateLimit{Limit: 120, Window: time.Minute}
+
+	tests := []struct {
+		name string
+		row  bucketRow
+		want domain.RateLimitResult
+	}{
+		{"allowed", bucketRow{tokens: 119, allowed: true}, domain.RateLimitResult{Allowed: true, Remaining: 119, Reset: 500 * time.Millisecond}},
+		{"refused", bucketRow{tokens: 0.5, allowed: false}, domain.RateLimitResult{Reset: 59750 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &bucketDB{row: tt.row}
+			l := &postgresLimiter{db: db.New(database), lastSweep: time.Now()}
+
+			res, err := l.Allow(context.Background(), "write:api_key:alice", limit)
+			if err != nil {
+				t.Fatalf("Allow: %v", err)
+			}
+			if res != tt.want {
+				t.Errorf("Allow = %+v, want %+v", res, tt.want)
+			}
+			// Two tokens a second into a bucket of 120
+			if len(database.args) != 3 || database.args[0] != "write:api_key:alice" || database.args[1] != 120.0 || database.args[2] != 2.0 {
+				t.Errorf("query arguments = %v, want key, capacity 120 and rate 2", database.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type apiKeyRepository struct {
+	db *db.Queries
+}
+
+func NewAPIKeyRepository(database *config.Database) domain.APIKeyRepository {
+	return &apiKeyRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, input domain.APIKeyInput) (*domain.APIKey, error) {
+	params := db.CreateAPIKeyParams{
+		Name:    input.Name,
+		Prefix:  input.Prefix,
+		KeyHash: input.KeyHash,
+		Role:    string(input.Role),
+		StoreID: optionalUUID(input.StoreID),
+	}
+
+	key, err := queries(ctx, r.db).CreateAPIKey(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toAPIKeyEntity(&key)
+	return &entity, nil
+}
+
+func (r *apiKeyRepository) Fetch(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := queries(ctx, r.db).GetAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.APIKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, toAPIKeyEntity(&key))
+	}
+	return result, nil
+}
+
+// FetchActiveByHash returns nil without an error when no active key matches.
+func (r *apiKeyRepository) FetchActiveByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	key, err := queries(ctx, r.db).GetActiveAPIKeyByHash(ctx, keyHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toAPIKeyEntity(&key)
+	return &entity, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	rows, err := queries(ctx, r.db).RevokeAPIKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.NewNotFoundError("api_key_not_found", "api key not found")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	return queries(ctx, r.db).TouchAPIKey(ctx, id)
+}
+
+func toAPIKeyEntity(k *db.ApiKey) domain.APIKey {
+	return domain.APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Role:       domain.Role(k.Role),
+		CreatedAt:  k.CreatedAt.Time,
+		LastUsedAt: optionalTime(k.LastUsedAt),
+		RevokedAt:  optionalTime(k.RevokedAt),
+		StoreID:    nullableUUID(k.StoreID),
+	}
+}
+
+func optionalTime(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func optionalUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+func nullableUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type auditRepository struct {
+	db *db.Queries
+}
+
+func NewAuditRepository(database *config.Database) domain.AuditRepository {
+	return &auditRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+// Create joins the transaction in ctx, so an entry is only kept when the
+// change it describes is committed.
+func (r *auditRepository) Create(ctx context.Context, entry domain.AuditEntry) error {
+	params := db.CreateAuditEntryParams{
+		StoreID:     entry.StoreID,
+		Actor:       entry.Actor,
+		ActorMethod: entry.ActorMethod,
+		Action:      entry.Action,
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		Before:      entry.Before,
+		After:       entry.After,
+		RequestID:   entry.RequestID,
+		Ip:          entry.IP,
+	}
+	return queries(ctx, r.db).CreateAuditEntry(ctx, params)
+}
+
+func (r *auditRepository) Fetch(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetAuditEntriesParams{
+		StoreID:    storeID,
+		EntityType: optionalText(filter.EntityType),
+		EntityID:   optionalText(filter.EntityID),
+		Actor:      optionalText(filter.Actor),
+		Action:     optionalText(filter.Action),
+		Since:      optionalTimestamp(filter.Since),
+		Until:      optionalTimestamp(filter.Until),
+		RowLimit:   int32(filter.Limit),
+	}
+	if filter.BeforeID > 0 {
+		params.BeforeID = pgtype.Int8{Int64: filter.BeforeID, Valid: true}
+	}
+
+	entries, err := queries(ctx, r.db).GetAuditEntries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, toAuditEntity(&e))
+	}
+	return result, nil
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func optionalTimestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
+
+func toAuditEntity(e *db.AuditLog) domain.AuditEntry {
+	return domain.AuditEntry{
+		ID:          e.ID,
+		StoreID:     e.StoreID,
+		Actor:       e.Actor,
+		ActorMethod: e.ActorMethod,
+		Action:      e.Action,
+		EntityType:  e.EntityType,
+		EntityID:    e.EntityID,
+		Before:      e.Before,
+		After:       e.After,
+		RequestID:   e.RequestID,
+		IP:          e.Ip,
+		CreatedAt:   e.CreatedAt.Time,
+	}
+}
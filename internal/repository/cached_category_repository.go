@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// cachedCategoryRepository serves category reads from the catalog cache.
+// Batched loads by ID are not cached.
+type cachedCategoryRepository struct {
+	next  domain.CategoryRepository
+	cache *CatalogCache
+}
+
+func NewCachedCategoryRepository(next domain.CategoryRepository, cache *CatalogCache) domain.CategoryRepository {
+	return &cachedCategoryRepository{next: next, cache: cache}
+}
+
+func (r *cachedCategoryRepository) Create(ctx context.Context, c domain.CategoryInput) (*domain.Category, error) {
+	category, err := r.next.Create(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityCategory, category.ID, domain.AuditActionCreate)
+	return category, nil
+}
+
+func (r *cachedCategoryRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Category, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.categoryKey(ctx, storeID, "page", limit, offset)
+	}, func(ctx context.Context) ([]domain.Category, error) {
+		return r.next.Fetch(ctx, limit, offset)
+	})
+}
+
+func (r *cachedCategoryRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.categoryKey(ctx, storeID, "id", id)
+	}, func(ctx context.Context) (*domain.Category, error) {
+		return r.next.FetchById(ctx, id)
+	})
+}
+
+func (r *cachedCategoryRepository) FetchBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.categoryKey(ctx, storeID, "slug", slug)
+	}, func(ctx context.Context) (*domain.Category, error) {
+		return r.next.FetchBySlug(ctx, slug)
+	})
+}
+
+func (r *cachedCategoryRepository) FetchByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error) {
+	return r.next.FetchByIDs(ctx, ids)
+}
+
+func (r *cachedCategoryRepository) FetchCount(ctx context.Context) (int, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.categoryKey(ctx, storeID, "count")
+	}, r.next.FetchCount)
+}
+
+func (r *cachedCategoryRepository) Update(ctx context.Context, id uuid.UUID, c domain.CategoryInput) error {
+	if err := r.next.Update(ctx, id, c); err != nil {
+		return err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityCategory, id, domain.AuditActionUpdate)
+	return nil
+}
+
+func (r *cachedCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityCategory, id, domain.AuditActionDelete)
+	return nil
+}
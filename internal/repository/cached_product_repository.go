@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// cachedProductRepository serves product reads from the catalog cache. Loads
+// of products by many categories at once are not cached.
+type cachedProductRepository struct {
+	next  domain.ProductRepository
+	cache *CatalogCache
+}
+
+func NewCachedProductRepository(next domain.ProductRepository, cache *CatalogCache) domain.ProductRepository {
+	return &cachedProductRepository{next: next, cache: cache}
+}
+
+func (r *cachedProductRepository) Create(ctx context.Context, p domain.ProductInput) (*domain.Product, error) {
+	product, err := r.next.Create(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionCreate)
+	return product, nil
+}
+
+func (r *cachedProductRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Product, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.productListKey(ctx, storeID, "page", limit, offset)
+	}, func(ctx context.Context) ([]domain.Product, error) {
+		return r.next.Fetch(ctx, limit, offset)
+	})
+}
+
+// FetchAfter is not cached, it serves streams that each walk the catalog once.
+func (r *cachedProductRepository) FetchAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error) {
+	return r.next.FetchAfter(ctx, after, limit)
+}
+
+func (r *cachedProductRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.productKey(ctx, storeID, id)
+	}, func(ctx context.Context) (*domain.Product, error) {
+		return r.next.FetchById(ctx, id)
+	})
+}
+
+func (r *cachedProductRepository) FetchByCategory(ctx context.Context, cID uuid.UUID) ([]domain.Product, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.productListKey(ctx, storeID, "category", cID)
+	}, func(ctx context.Context) ([]domain.Product, error) {
+		return r.next.FetchByCategory(ctx, cID)
+	})
+}
+
+func (r *cachedProductRepository) FetchByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error) {
+	return r.next.FetchByCategories(ctx, categoryIDs, limit)
+}
+
+func (r *cachedProductRepository) FetchCount(ctx context.Context) (int, error) {
+	return readThrough(ctx, r.cache, func(ctx context.Context, storeID uuid.UUID) (string, error) {
+		return r.cache.productListKey(ctx, storeID, "count")
+	}, r.next.FetchCount)
+}
+
+func (r *cachedProductRepository) Update(ctx context.Context, id uuid.UUID, p domain.ProductInput) error {
+	if err := r.next.Update(ctx, id, p); err != nil {
+		return err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityProduct, id, domain.AuditActionUpdate)
+	return nil
+}
+
+func (r *cachedProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.cache.invalidateWrite(ctx, domain.AuditEntityProduct, id, domain.AuditActionDelete)
+	return nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-listing/internal/cache"
+	"product-listing/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/op/go-logging"
+)
+
+var cacheLog = logging.MustGetLogger("cache")
+
+// CatalogCache is the read-through cache of products and categories. Keys of
+// a store carry a generation of its categories, and listings one of its
+// products as well, so a change drops every listing it may appear in by
+// dropping a generation. Each product has a generation of its own, so a load
+// that started before a change and finishes after it caches under a key no
+// longer read. Products embed their categories, so a category change drops
+// every product entry of the store too.
+//
+// Changes committed through any replica reach Invalidate through the change
+// bus. Writes through the cached repositories invalidate right away as well,
+// which may be before their transaction commits; the change announced on
+// commit covers that gap. Entries expire after the TTL in any case.
+type CatalogCache struct {
+	cache domain.Cache
+	ttl   time.Duration
+	loads cache.Group
+}
+
+// NewCatalogCache caches in c for ttl. A nil c disables caching.
+func NewCatalogCache(c domain.Cache, ttl time.Duration) *CatalogCache {
+	return &CatalogCache{cache: c, ttl: ttl}
+}
+
+// Watch applies the changes announced on changes until ctx is done.
+func (c *CatalogCache) Watch(ctx context.Context, changes domain.ChangeBus) {
+	ch, unsubscribe := changes.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-ch:
+			if err := c.Invalidate(ctx, change); err != nil {
+				cacheLog.Errorf("Catalog cache invalidation failed: %v", err)
+			}
+		}
+	}
+}
+
+// Invalidate drops the entries change made stale.
+func (c *CatalogCache) Invalidate(ctx context.Context, change domain.CatalogChange) error {
+	if c.cache == nil {
+		return nil
+	}
+	if change.Missed() {
+		return c.cache.Clear(ctx)
+	}
+
+	switch change.EntityType {
+	case domain.AuditEntityCategory:
+		return c.cache.Delete(ctx, generationKey(change.StoreID, "categories"))
+	case domain.AuditEntityProduct, domain.AuditEntityProductImage:
+		productID := change.EntityID
+		if change.EntityType == domain.AuditEntityProductImage {
+			productID = change.ProductID
+		}
+
+		keys := []string{generationKey(change.StoreID, "products")}
+		if productID != "" {
+			keys = append(keys, generationKey(change.StoreID, "product:"+productID))
+		}
+		return c.cache.Delete(ctx, keys...)
+	}
+	return nil
+}
+
+// invalidateWrite invalidates after a write through a cached repository. A
+// failure is only logged, the change announced on commit tries again.
+func (c *CatalogCache) invalidateWrite(ctx context.Context, entityType string, id uuid.UUID, action string) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return
+	}
+
+	change := domain.CatalogChange{StoreID: storeID, EntityType: entityType, EntityID: id.String(), Action: action}
+	if err := c.Invalidate(ctx, change); err != nil {
+		cacheLog.Warningf("Catalog cache invalidation failed: %v", err)
+	}
+}
+
+func (c *CatalogCache) categoryKey(ctx context.Context, storeID uuid.UUID, parts ...any) (string, error) {
+	gen, err := c.generation(ctx, storeID, "categories")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("categories:%s:%s:%s", storeID, gen, joinKey(parts)), nil
+}
+
+func (c *CatalogCache) productKey(ctx context.Context, storeID, id uuid.UUID) (string, error) {
+	categoriesGen, err := c.generation(ctx, storeID, "categories")
+	if err != nil {
+		return "", err
+	}
+	productGen, err := c.generation(ctx, storeID, "product:"+id.String())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("product:%s:%s:%s:%s", storeID, categoriesGen, productGen, id), nil
+}
+
+func (c *CatalogCache) productListKey(ctx context.Context, storeID uuid.UUID, parts ...any) (string, error) {
+	categoriesGen, err := c.generation(ctx, storeID, "categories")
+	if err != nil {
+		return "", err
+	}
+	productsGen, err := c.generation(ctx, storeID, "products")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products:%s:%s:%s:%s", storeID, categoriesGen, productsGen, joinKey(parts)), nil
+}
+
+// generation returns the current generation of name in the store, starting a
+// new one when there is none. Replicas racing to start one may each use their
+// own for a moment, which only costs misses.
+func (c *CatalogCache) generation(ctx context.Context, storeID uuid.UUID, name string) (string, error) {
+	key := generationKey(storeID, name)
+	gen, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return string(gen), nil
+	}
+
+	next := uuid.NewString()
+	if err := c.cache.Set(ctx, key, []byte(next), c.ttl); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+func generationKey(storeID uuid.UUID, name string) string {
+	return fmt.Sprintf("gen:%s:%s", storeID, name)
+}
+
+func joinKey(parts []any) string {
+	s := make([]string, len(parts))
+	for i, p := range parts {
+		s[i] = fmt.Sprint(p)
+	}
+	return strings.Join(s, ":")
+}
+
+// readThrough returns the value cached under the key that key builds, or
+// loads and caches it. Concurrent misses of a key share one load. Reads in a
+// transaction bypass the cache, since they may see writes that are not
+// committed. A failing cache is logged and bypassed.
+func readThrough[T any](ctx context.Context, c *CatalogCache, key func(ctx context.Context, storeID uuid.UUID) (string, error), load func(ctx context.Context) (T, error)) (T, error) {
+	if c.cache == nil || inTransaction(ctx) {
+		return load(ctx)
+	}
+
+	var value T
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	k, err := key(ctx, storeID)
+	if err != nil {
+		cacheLog.Warningf("Catalog cache unavailable: %v", err)
+		return load(ctx)
+	}
+
+	data, ok, err := c.cache.Get(ctx, k)
+	if err != nil {
+		cacheLog.Warningf("Catalog cache read failed: %v", err)
+	}
+	if ok && json.Unmarshal(data, &value) == nil {
+		return value, nil
+	}
+
+	data, err = c.loads.Do(k, func() ([]byte, error) {
+		// The load is shared, so one caller giving up must not cancel it
+		ctx := context.WithoutCancel(ctx)
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cache entry: %w", err)
+		}
+		if err := c.cache.Set(ctx, k, data, c.ttl); err != nil {
+			cacheLog.Warningf("Catalog cache write failed: %v", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	// Every caller decodes its own copy, which it is free to modify
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
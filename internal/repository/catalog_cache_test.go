@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"product-listing/internal/cache"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countingProductRepository serves a fixed product and counts the reads that
+// reach it. duringRead runs once after a read took its copy, standing in for a
+// write committed while the read is in flight.
+type countingProductRepository struct {
+	domain.ProductRepository
+	product    domain.Product
+	reads      int
+	duringRead func()
+}
+
+func (r *countingProductRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	r.reads++
+	p := r.product
+	if r.duringRead != nil {
+		r.duringRead()
+		r.duringRead = nil
+	}
+	return &p, nil
+}
+
+func (r *countingProductRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Product, error) {
+	r.reads++
+	return []domain.Product{r.product}, nil
+}
+
+func TestCachedProductRepositoryReadsThrough(t *testing.T) {
+	store := &domain.Store{ID: uuid.New()}
+	ctx := domain.ContextWithStore(context.Background(), store)
+	next := &countingProductRepository{product: domain.Product{ID: uuid.New(), Name: "Lamp", Categories: []domain.Category{}}}
+	catalog := NewCatalogCache(cache.NewMemoryCache(100), time.Minute)
+	repo := NewCachedProductRepository(next, catalog)
+
+	first, err := repo.FetchById(ctx, next.product.ID)
+	if err != nil {
+		t.Fatalf("FetchById: %v", err)
+	}
+	first.Name = "changed by the caller"
+
+	second, err := repo.FetchById(ctx, next.product.ID)
+	if err != nil {
+		t.Fatalf("FetchById: %v", err)
+	}
+	if next.reads != 1 {
+		t.Errorf("repository read %d times, want 1", next.reads)
+	}
+	if second.Name != "Lamp" || second.Categories == nil {
+		t.Errorf("cached product = %+v, want the product as loaded", second)
+	}
+
+	// Another store has entries of its own
+	other := domain.ContextWithStore(context.Background(), &domain.Store{ID: uuid.New()})
+	if _, err := repo.FetchById(other, next.product.ID); err != nil || next.reads != 2 {
+		t.Errorf("read of another store: %v, %d reads, want a miss", err, next.reads)
+	}
+}
+
+func TestCatalogCacheInvalidatesPrecisely(t *testing.T) {
+	store := &domain.Store{ID: uuid.New()}
+	ctx := domain.ContextWithStore(context.Background(), store)
+	next := &countingProductRepository{product: domain.Product{ID: uuid.New()}}
+	catalog := NewCatalogCache(cache.NewMemoryCache(100), time.Minute)
+	repo := NewCachedProductRepository(next, catalog)
+	unrelated := uuid.New()
+
+	warm := func() {
+		t.Helper()
+		next.reads = 0
+		_, _ = repo.FetchById(ctx, next.product.ID)
+		_, _ = repo.FetchById(ctx, unrelated)
+		_, _ = repo.Fetch(ctx, 10, 0)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		change domain.CatalogChange
+		reads  int
+	}{
+		{
+			name:   "product",
+			change: domain.CatalogChange{StoreID: store.ID, EntityType: domain.AuditEntityProduct, EntityID: next.product.ID.String()},
+			reads:  2, // the product and the listing
+		},
+		{
+			name:   "image",
+			change: domain.CatalogChange{StoreID: store.ID, EntityType: domain.AuditEntityProductImage, EntityID: uuid.NewString(), ProductID: next.product.ID.String()},
+			reads:  2,
+		},
+		{
+			name:   "category",
+			change: domain.CatalogChange{StoreID: store.ID, EntityType: domain.AuditEntityCategory, EntityID: uuid.NewString()},
+			reads:  3, // products embed their categories
+		},
+		{
+			name:   "other store",
+			change: domain.CatalogChange{StoreID: uuid.New(), EntityType: domain.AuditEntityProduct, EntityID: next.product.ID.String()},
+			reads:  0,
+		},
+		{
+			name:  "missed",
+			reads: 3,
+		},
+	} {
+		warm()
+		if err := catalog.Invalidate(context.Background(), tc.change); err != nil {
+			t.Fatalf("%s: Invalidate: %v", tc.name, err)
+		}
+		warm()
+		if next.reads != tc.reads {
+			t.Errorf("%s: %d reads after the change, want %d", tc.name, next.reads, tc.reads)
+		}
+	}
+}
+
+func TestCatalogCacheDropsLoadsRacingAChange(t *testing.T) {
+	store := &domain.Store{ID: uuid.New()}
+	ctx := domain.ContextWithStore(context.Background(), store)
+	next := &countingProductRepository{product: domain.Product{ID: uuid.New(), Name: "Lamp"}}
+	catalog := NewCatalogCache(cache.NewMemoryCache(100), time.Minute)
+	repo := NewCachedProductRepository(next, catalog)
+
+	// The product is renamed and invalidated after the read loaded it but
+	// before the read caches it
+	next.duringRead = func() {
+		next.product.Name = "Desk lamp"
+		change := domain.CatalogChange{StoreID: store.ID, EntityType: domain.AuditEntityProduct, EntityID: next.product.ID.String()}
+		if err := catalog.Invalidate(context.Background(), change); err != nil {
+			t.Fatalf("Invalidate: %v", err)
+		}
+	}
+	if p, err := repo.FetchById(ctx, next.product.ID); err != nil || p.Name != "Lamp" {
+		t.Fatalf("racing read = %+v, %v, want the product as it was loaded", p, err)
+	}
+
+	p, err := repo.FetchById(ctx, next.product.ID)
+	if err != nil {
+		t.Fatalf("FetchById: %v", err)
+	}
+	if p.Name != "Desk lamp" || next.reads != 2 {
+		t.Errorf("read after the change = %q with %d reads, want the renamed product from the repository", p.Name, next.reads)
+	}
+}
+
+// slugCategories finds categories by slug among those of the bound store.
+type slugCategories struct {
+	domain.CategoryRepository
+	categories map[uuid.UUID][]domain.Category
+	reads      int
+}
+
+func (r *slugCategories) FetchBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	r.reads++
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range r.categories[storeID] {
+		if c.Slug == slug {
+			return &c, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("category_not_found", "category not found")
+}
+
+func TestCachedCategorySlugsAreScopedToStore(t *testing.T) {
+	acme, other := &domain.Store{ID: uuid.New()}, &domain.Store{ID: uuid.New()}
+	next := &slugCategories{categories: map[uuid.UUID][]domain.Category{
+		acme.ID:  {{ID: uuid.New(), Name: "Acme Kitchen", Slug: "kitchen"}},
+		other.ID: {{ID: uuid.New(), Name: "Other Kitchen", Slug: "kitchen"}},
+	}}
+	repo := NewCachedCategoryRepository(next, NewCatalogCache(cache.NewMemoryCache(100), time.Minute))
+
+	for range 2 {
+		for _, store := range []*domain.Store{acme, other} {
+			ctx := domain.ContextWithStore(context.Background(), store)
+			category, err := repo.FetchBySlug(ctx, "kitchen")
+			if err != nil {
+				t.Fatalf("FetchBySlug: %v", err)
+			}
+			if want := next.categories[store.ID][0]; category.ID != want.ID {
+				t.Errorf("category = %s, want %s of the bound store", category.Name, want.Name)
+			}
+		}
+	}
+	if next.reads != 2 {
+		t.Errorf("repository read %d times, want once per store", next.reads)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"product-listing/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type categoryRepository struct {
@@ -20,27 +21,41 @@ func NewCategoryRepository(database *config.Database) domain.CategoryRepository
 	}
 }
 
-func (r *categoryRepository) Create(ctx context.Context, c domain.CategoryInput) error {
+func (r *categoryRepository) Create(ctx context.Context, c domain.CategoryInput) (*domain.Category, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.CreateCategoryParams{
-		Name: c.Name,
-		Slug: c.Slug,
+		StoreID: storeID,
+		Name:    c.Name,
+		Slug:    c.Slug,
 	}
 
-	_, err := r.db.CreateCategory(ctx, params)
+	category, err := queries(ctx, r.db).CreateCategory(ctx, params)
 	if err != nil {
-		return errors.New(err.Error())
+		return nil, errors.New(err.Error())
 	}
 
-	return nil
+	result := toCategoryEntity(&category)
+
+	return &result, nil
 }
 
 func (r *categoryRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Category, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.GetCategoriesParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		StoreID: storeID,
+		Limit:   int32(limit),
+		Offset:  int32(offset),
 	}
 
-	categories, err := r.db.GetCategories(ctx, params)
+	categories, err := queries(ctx, r.db).GetCategories(ctx, params)
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -54,7 +69,15 @@ func (r *categoryRepository) Fetch(ctx context.Context, limit, offset int) ([]do
 }
 
 func (r *categoryRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
-	category, err := r.db.GetCategoryById(ctx, id)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := queries(ctx, r.db).GetCategoryById(ctx, db.GetCategoryByIdParams{StoreID: storeID, ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("category_not_found", "category not found")
+	}
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -65,7 +88,15 @@ func (r *categoryRepository) FetchById(ctx context.Context, id uuid.UUID) (*doma
 }
 
 func (r *categoryRepository) FetchBySlug(ctx context.Context, slug string) (*domain.Category, error) {
-	category, err := r.db.GetCategoryBySlug(ctx, slug)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := queries(ctx, r.db).GetCategoryBySlug(ctx, db.GetCategoryBySlugParams{StoreID: storeID, Slug: slug})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("category_not_found", "category not found")
+	}
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -75,8 +106,33 @@ func (r *categoryRepository) FetchBySlug(ctx context.Context, slug string) (*dom
 	return &result, nil
 }
 
+// FetchByIDs skips IDs that match no category.
+func (r *categoryRepository) FetchByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := queries(ctx, r.db).GetCategoriesByIDs(ctx, db.GetCategoriesByIDsParams{StoreID: storeID, Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Category, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, toCategoryEntity(&c))
+	}
+
+	return result, nil
+}
+
 func (r *categoryRepository) FetchCount(ctx context.Context) (int, error) {
-	total, err := r.db.GetCategoriesCount(ctx)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := queries(ctx, r.db).GetCategoriesCount(ctx, storeID)
 	if err != nil {
 		return 0, err
 	}
@@ -85,13 +141,19 @@ func (r *categoryRepository) FetchCount(ctx context.Context) (int, error) {
 }
 
 func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, c domain.CategoryInput) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := db.UpdateCategoryParams{
-		ID:   id,
-		Name: c.Name,
-		Slug: c.Slug,
+		StoreID: storeID,
+		ID:      id,
+		Name:    c.Name,
+		Slug:    c.Slug,
 	}
 
-	err := r.db.UpdateCategory(ctx, params)
+	err = queries(ctx, r.db).UpdateCategory(ctx, params)
 	if err != nil {
 		return errors.New(err.Error())
 
@@ -101,7 +163,12 @@ func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, c domain.
 }
 
 func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	err := r.db.DeleteCategory(ctx, id)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = queries(ctx, r.db).DeleteCategory(ctx, db.DeleteCategoryParams{StoreID: storeID, ID: id})
 	if err != nil {
 		return errors.New(err.Error())
 	}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type idempotencyRepository struct {
+	db *db.Queries
+}
+
+func NewIdempotencyRepository(database *config.Database) domain.IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, scope, key, requestHash string, claim uuid.UUID, expiresAt time.Time) (bool, error) {
+	rows, err := queries(ctx, r.db).ReserveIdempotencyKey(ctx, db.ReserveIdempotencyKeyParams{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		Claim:       pgtype.UUID{Bytes: claim, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// Get returns nil without an error when the key is not stored.
+func (r *idempotencyRepository) Get(ctx context.Context, scope, key string) (*domain.IdempotentResponse, error) {
+	row, err := queries(ctx, r.db).GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{Scope: scope, Key: key})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IdempotentResponse{
+		RequestHash: row.RequestHash,
+		StatusCode:  int(row.StatusCode.Int32),
+		ContentType: row.ContentType.String,
+		Location:    row.Location.String,
+		Body:        row.ResponseBody,
+		ExpiresAt:   row.ExpiresAt.Time,
+	}, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, scope, key string, claim uuid.UUID, response domain.IdempotentResponse, expiresAt time.Time) error {
+	return queries(ctx, r.db).CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		Scope:        scope,
+		Key:          key,
+		StatusCode:   pgtype.Int4{Int32: int32(response.StatusCode), Valid: true},
+		ContentType:  pgtype.Text{String: response.ContentType, Valid: response.ContentType != ""},
+		ResponseBody: response.Body,
+		ExpiresAt:    pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		Location:     pgtype.Text{String: response.Location, Valid: response.Location != ""},
+		Claim:        pgtype.UUID{Bytes: claim, Valid: true},
+	})
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, scope, key string, claim uuid.UUID) error {
+	return queries(ctx, r.db).DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		Scope: scope,
+		Key:   key,
+		Claim: pgtype.UUID{Bytes: claim, Valid: true},
+	})
+}
+
+func (r *idempotencyRepository) ReleaseExpired(ctx context.Context, scope, key string) error {
+	return queries(ctx, r.db).DeleteIdempotencyKeyIfExpired(ctx, db.DeleteIdempotencyKeyIfExpiredParams{Scope: scope, Key: key})
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return queries(ctx, r.db).DeleteExpiredIdempotencyKeys(ctx)
+}
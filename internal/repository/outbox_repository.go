@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type outboxRepository struct {
+	db *db.Queries
+}
+
+func NewOutboxRepository(database *config.Database) domain.OutboxRepository {
+	return &outboxRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+// Create joins the transaction in ctx, so an event is only kept when the
+// change it describes is committed.
+func (r *outboxRepository) Create(ctx context.Context, event domain.ChangeEvent) error {
+	return queries(ctx, r.db).CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		EventID:    event.ID,
+		StoreID:    event.StoreID,
+		EventType:  event.Type,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Action:     event.Action,
+		Data:       event.Data,
+		OccurredAt: pgtype.Timestamptz{Time: event.OccurredAt, Valid: true},
+	})
+}
+
+func (r *outboxRepository) LockCursor(ctx context.Context, publisher string) (domain.OutboxPosition, bool, error) {
+	q := queries(ctx, r.db)
+	if err := q.InitOutboxCursor(ctx, publisher); err != nil {
+		return domain.OutboxPosition{}, false, err
+	}
+
+	cursor, err := q.LockOutboxCursor(ctx, publisher)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.OutboxPosition{}, false, nil
+	}
+	if err != nil {
+		return domain.OutboxPosition{}, false, err
+	}
+	return domain.OutboxPosition{TxID: cursor.TxID, ID: cursor.LastID}, true, nil
+}
+
+func (r *outboxRepository) FetchAfter(ctx context.Context, position domain.OutboxPosition, limit int) ([]domain.OutboxEvent, error) {
+	rows, err := queries(ctx, r.db).GetOutboxEventsAfter(ctx, db.GetOutboxEventsAfterParams{
+		TxID:     position.TxID,
+		LastID:   position.ID,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOutboxEvents(rows), nil
+}
+
+func (r *outboxRepository) FetchStoreAfter(ctx context.Context, position domain.OutboxPosition, entityTypes []string, limit int) ([]domain.OutboxEvent, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetStoreOutboxEventsAfter(ctx, db.GetStoreOutboxEventsAfterParams{
+		StoreID:     storeID,
+		TxID:        position.TxID,
+		LastID:      position.ID,
+		EntityTypes: entityTypes,
+		RowLimit:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toOutboxEvents(rows), nil
+}
+
+// Head is just before the oldest transaction still running. Events of
+// transactions that finished after it started are after it too, and are sent
+// again to a feed that starts there.
+func (r *outboxRepository) Head(ctx context.Context) (domain.OutboxPosition, error) {
+	txID, err := queries(ctx, r.db).GetOutboxHead(ctx)
+	if err != nil {
+		return domain.OutboxPosition{}, err
+	}
+	return domain.OutboxPosition{TxID: txID, ID: math.MaxInt64}, nil
+}
+
+func (r *outboxRepository) SaveCursor(ctx context.Context, publisher string, position domain.OutboxPosition) error {
+	return queries(ctx, r.db).UpdateOutboxCursor(ctx, db.UpdateOutboxCursorParams{
+		Publisher: publisher,
+		TxID:      position.TxID,
+		LastID:    position.ID,
+	})
+}
+
+func (r *outboxRepository) FetchLag(ctx context.Context) ([]domain.OutboxLag, error) {
+	rows, err := queries(ctx, r.db).GetOutboxLag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.OutboxLag, 0, len(rows))
+	for _, row := range rows {
+		lag := domain.OutboxLag{
+			Publisher: row.Publisher,
+			Pending:   int(row.Pending),
+			RelayedAt: row.UpdatedAt.Time,
+		}
+		if row.OldestPendingAt.Valid {
+			lag.OldestPendingAt = &row.OldestPendingAt.Time
+		}
+		result = append(result, lag)
+	}
+	return result, nil
+}
+
+func (r *outboxRepository) DeleteRelayed(ctx context.Context, cutoff time.Time) (int, error) {
+	deleted, err := queries(ctx, r.db).DeleteRelayedOutboxEvents(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	return int(deleted), err
+}
+
+func toOutboxEvents(rows []db.Outbox) []domain.OutboxEvent {
+	result := make([]domain.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.OutboxEvent{
+			Position: domain.OutboxPosition{TxID: row.TxID, ID: row.ID},
+			Event: domain.ChangeEvent{
+				ID:         row.EventID,
+				StoreID:    row.StoreID,
+				Type:       row.EventType,
+				EntityType: row.EntityType,
+				EntityID:   row.EntityID,
+				Action:     row.Action,
+				Data:       row.Data,
+				OccurredAt: row.OccurredAt.Time,
+			},
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return result
+}
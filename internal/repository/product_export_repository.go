@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-listing/config"
+	"product-listing/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// productExportBatchSize is how many rows are fetched from the cursor at once.
+const productExportBatchSize = 500
+
+// declareProductExport and fetchProductExport are written by hand, sqlc has
+// no support for cursors. The order matches GetAllProducts.
+const declareProductExport = `DECLARE product_export NO SCROLL CURSOR FOR
+SELECT
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug) ORDER BY c.slug)
+        FROM product_categories pc
+        JOIN categories c ON c.id = pc.category_id
+        WHERE pc.product_id = p.id
+    )::json AS categories,
+    (
+        SELECT json_agg(jsonb_build_object(
+            'id', pi.id,
+            'url', pi.url,
+            'is_primary', COALESCE(pi.is_primary, false),
+            'visibility', pi.visibility,
+            'position', pi.position
+        ) ORDER BY pi.is_primary DESC NULLS LAST, pi.position, pi.created_at)
+        FROM product_images pi
+        WHERE pi.product_id = p.id
+    )::json AS images
+FROM products p
+WHERE p.store_id = $1
+  AND ($2::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM product_categories pc
+      WHERE pc.product_id = p.id AND pc.category_id = $2::uuid
+  ))
+ORDER BY p.created_at DESC, p.id`
+
+var fetchProductExport = fmt.Sprintf("FETCH FORWARD %d FROM product_export", productExportBatchSize)
+
+type productExportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewProductExportRepository(database *config.Database) domain.ProductExportRepository {
+	return &productExportRepository{pool: database.Pool}
+}
+
+// Export holds a read-only transaction open for as long as emit takes, a slow
+// consumer keeps its snapshot and connection for the whole export.
+func (r *productExportRepository) Export(ctx context.Context, filter domain.ProductExportFilter, emit func(domain.Product) error) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, declareProductExport, storeID, filter.CategoryID); err != nil {
+		return err
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fetchProductExport)
+		if err != nil {
+			return err
+		}
+		products, err := pgx.CollectRows(rows, scanExportedProduct)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range products {
+			if err := emit(p); err != nil {
+				return err
+			}
+		}
+
+		if len(products) < productExportBatchSize {
+			return nil
+		}
+	}
+}
+
+func scanExportedProduct(row pgx.CollectableRow) (domain.Product, error) {
+	var (
+		p          domain.Product
+		categories []byte
+		images     []byte
+	)
+	err := row.Scan(&p.ID, &p.Name, &p.Slug, &p.Description, &p.Price, &p.CreatedAt, &p.UpdatedAt, &categories, &images)
+	if err != nil {
+		return p, err
+	}
+
+	p.Categories = parseCategories(categories)
+	if len(images) > 0 {
+		if err := json.Unmarshal(images, &p.Images); err != nil {
+			return p, fmt.Errorf("failed to decode images of product %s: %w", p.ID, err)
+		}
+	}
+	for i := range p.Images {
+		p.Images[i].ProductID = p.ID
+		if p.Images[i].IsPrimary {
+			p.PrimaryImageID = p.Images[i].ID
+			p.PrimaryImageURL = p.Images[i].Url
+			p.PrimaryImagePrivate = p.Images[i].Visibility == domain.ImageVisibilityPrivate
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type productFeedRepository struct {
+	db *db.Queries
+}
+
+func NewProductFeedRepository(database *config.Database) domain.ProductFeedRepository {
+	return &productFeedRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *productFeedRepository) Create(ctx context.Context) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return queries(ctx, r.db).CreateProductFeed(ctx, storeID)
+}
+
+func (r *productFeedRepository) Fetch(ctx context.Context) (*domain.ProductFeed, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := queries(ctx, r.db).GetProductFeed(ctx, storeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductFeedEntity(&feed)
+	return &entity, nil
+}
+
+func (r *productFeedRepository) Lock(ctx context.Context) (*domain.ProductFeed, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := queries(ctx, r.db).LockProductFeed(ctx, storeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductFeedEntity(&feed)
+	return &entity, nil
+}
+
+func (r *productFeedRepository) MarkStale(ctx context.Context) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return queries(ctx, r.db).MarkProductFeedStale(ctx, storeID)
+}
+
+func (r *productFeedRepository) FinishBuild(ctx context.Context, fingerprint string) (*domain.ProductFeed, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := queries(ctx, r.db).FinishProductFeedBuild(ctx, db.FinishProductFeedBuildParams{
+		StoreID:     storeID,
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductFeedEntity(&feed)
+	return &entity, nil
+}
+
+func (r *productFeedRepository) Touch(ctx context.Context) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return queries(ctx, r.db).TouchProductFeed(ctx, storeID)
+}
+
+func (r *productFeedRepository) DeleteItems(ctx context.Context) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return queries(ctx, r.db).DeleteProductFeedItems(ctx, storeID)
+}
+
+func (r *productFeedRepository) SaveItems(ctx context.Context, items []domain.ProductFeedItem) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := make([]db.CreateProductFeedItemsParams, 0, len(items))
+	for _, item := range items {
+		params = append(params, db.CreateProductFeedItemsParams{
+			StoreID:   storeID,
+			ProductID: item.ProductID,
+			Xml:       item.XML,
+			Tsv:       item.TSV,
+		})
+	}
+
+	_, err = queries(ctx, r.db).CreateProductFeedItems(ctx, params)
+	return err
+}
+
+func (r *productFeedRepository) UpsertItem(ctx context.Context, item domain.ProductFeedItem) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return queries(ctx, r.db).UpsertProductFeedItem(ctx, db.UpsertProductFeedItemParams{
+		StoreID:   storeID,
+		ProductID: item.ProductID,
+		Xml:       item.XML,
+		Tsv:       item.TSV,
+	})
+}
+
+func (r *productFeedRepository) DeleteItem(ctx context.Context, productID uuid.UUID) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return queries(ctx, r.db).DeleteProductFeedItem(ctx, db.DeleteProductFeedItemParams{
+		StoreID:   storeID,
+		ProductID: productID,
+	})
+}
+
+func (r *productFeedRepository) FetchItems(ctx context.Context, afterProductID uuid.UUID, limit int) ([]domain.ProductFeedItem, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetProductFeedItems(ctx, db.GetProductFeedItemsParams{
+		StoreID:   storeID,
+		ProductID: afterProductID,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ProductFeedItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, domain.ProductFeedItem{
+			ProductID: row.ProductID,
+			XML:       row.Xml,
+			TSV:       row.Tsv,
+		})
+	}
+	return items, nil
+}
+
+func toProductFeedEntity(f *db.ProductFeed) domain.ProductFeed {
+	return domain.ProductFeed{
+		StoreID:     f.StoreID,
+		Fingerprint: f.Fingerprint,
+		Stale:       f.Stale,
+		Version:     f.Version,
+		UpdatedAt:   f.UpdatedAt.Time,
+	}
+}
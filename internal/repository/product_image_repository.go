@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"product-listing/config"
 	"product-listing/internal/db"
 	"product-listing/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -21,13 +23,54 @@ func NewProductImageRepository(database *config.Database) domain.ProductImageRep
 }
 
 func (r *productImageRepository) Create(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.CreateProductImageParams{
-		ProductID: input.ProductID,
-		Url:       input.Url,
-		IsPrimary: pgtype.Bool{Bool: input.IsPrimary, Valid: true},
+		StoreID:    storeID,
+		ProductID:  input.ProductID,
+		Url:        input.Url,
+		IsPrimary:  pgtype.Bool{Bool: input.IsPrimary, Valid: true},
+		Visibility: input.Visibility,
+	}
+
+	pi, err := queries(ctx, r.db).CreateProductImage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductImageEntity(&pi)
+	return &entity, nil
+}
+
+func (r *productImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductImage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	pi, err := r.db.CreateProductImage(ctx, params)
+	pi, err := queries(ctx, r.db).GetProductImageByID(ctx, db.GetProductImageByIDParams{StoreID: storeID, ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("image_not_found", "image not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductImageEntity(&pi)
+	return &entity, nil
+}
+
+// GetMediaByID looks an image up in any store. It only backs the media route,
+// where the URL signature rather than the request's store grants access.
+func (r *productImageRepository) GetMediaByID(ctx context.Context, id uuid.UUID) (*domain.ProductImage, error) {
+	ctx = acrossStores(ctx)
+	pi, err := queries(ctx, r.db).GetMediaImageByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("image_not_found", "image not found")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +80,12 @@ func (r *productImageRepository) Create(ctx context.Context, input domain.Produc
 }
 
 func (r *productImageRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]domain.ProductImage, error) {
-	images, err := r.db.GetProductImages(ctx, productID)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := queries(ctx, r.db).GetProductImages(ctx, db.GetProductImagesParams{StoreID: storeID, ProductID: productID})
 	if err != nil {
 		return nil, err
 	}
@@ -49,24 +97,146 @@ func (r *productImageRepository) GetByProductID(ctx context.Context, productID u
 	return result, nil
 }
 
+func (r *productImageRepository) GetByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.ProductImage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetProductImagesByProductIDsParams{
+		StoreID:    storeID,
+		ProductIds: productIDs,
+	}
+	images, err := queries(ctx, r.db).GetProductImagesByProductIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]domain.ProductImage, len(productIDs))
+	for _, img := range images {
+		result[img.ProductID] = append(result[img.ProductID], toProductImageEntity(&img))
+	}
+	return result, nil
+}
+
+// GetPrimary returns nil without an error when the product has no primary image.
+func (r *productImageRepository) GetPrimary(ctx context.Context, productID uuid.UUID) (*domain.ProductImage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pi, err := queries(ctx, r.db).GetProductPrimaryImage(ctx, db.GetProductPrimaryImageParams{StoreID: storeID, ProductID: productID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toProductImageEntity(&pi)
+	return &entity, nil
+}
+
+// GetReferencedURLs looks in every store, as they share the media storage.
+func (r *productImageRepository) GetReferencedURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	ctx = acrossStores(ctx)
+	referenced, err := queries(ctx, r.db).GetReferencedImageURLs(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(referenced))
+	for _, url := range referenced {
+		result[url] = true
+	}
+	return result, nil
+}
+
+// LockProduct takes a row lock on the product so concurrent image changes for
+// it are serialized. It must be called inside a transaction.
+func (r *productImageRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries(ctx, r.db).LockProduct(ctx, db.LockProductParams{StoreID: storeID, ID: productID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("product_not_found", "product not found")
+	}
+	return err
+}
+
 func (r *productImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.DeleteProductImage(ctx, id)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return queries(ctx, r.db).DeleteProductImage(ctx, db.DeleteProductImageParams{StoreID: storeID, ID: id})
+}
+
+func (r *productImageRepository) UpdateVisibility(ctx context.Context, id uuid.UUID, visibility string) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := db.UpdateProductImageVisibilityParams{
+		StoreID:    storeID,
+		ID:         id,
+		Visibility: visibility,
+	}
+	return queries(ctx, r.db).UpdateProductImageVisibility(ctx, params)
+}
+
+func (r *productImageRepository) UpdatePosition(ctx context.Context, id uuid.UUID, position int) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := db.UpdateProductImagePositionParams{
+		StoreID:  storeID,
+		ID:       id,
+		Position: int32(position),
+	}
+	return queries(ctx, r.db).UpdateProductImagePosition(ctx, params)
+}
+
+func (r *productImageRepository) ClearPrimary(ctx context.Context, productID uuid.UUID) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return queries(ctx, r.db).ClearProductPrimaryImage(ctx, db.ClearProductPrimaryImageParams{StoreID: storeID, ProductID: productID})
 }
 
 func (r *productImageRepository) SetPrimary(ctx context.Context, productID uuid.UUID, imageID uuid.UUID) error {
-	params := db.SetProductPrimaryImageParams{
-		ProductID: productID,
-		ID:        imageID,
+	// Clear first: the partial unique index is checked row by row, so flipping
+	// both images in a single UPDATE can trip it.
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := queries(ctx, r.db)
+	if err := q.ClearProductPrimaryImage(ctx, db.ClearProductPrimaryImageParams{StoreID: storeID, ProductID: productID}); err != nil {
+		return err
 	}
-	return r.db.SetProductPrimaryImage(ctx, params)
+	return q.MarkProductImagePrimary(ctx, db.MarkProductImagePrimaryParams{StoreID: storeID, ID: imageID})
 }
 
 func toProductImageEntity(pi *db.ProductImage) domain.ProductImage {
 	return domain.ProductImage{
-		ID:        pi.ID,
-		ProductID: pi.ProductID,
-		Url:       pi.Url,
-		IsPrimary: pi.IsPrimary.Bool,
-		CreatedAt: pi.CreatedAt.Time,
+		ID:         pi.ID,
+		ProductID:  pi.ProductID,
+		Url:        pi.Url,
+		IsPrimary:  pi.IsPrimary.Bool,
+		Visibility: pi.Visibility,
+		Position:   int(pi.Position),
+		CreatedAt:  pi.CreatedAt.Time,
 	}
 }
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type productImportRepository struct {
+	db *db.Queries
+}
+
+func NewProductImportRepository(database *config.Database) domain.ImportRepository {
+	return &productImportRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+// CreateJob records the principal and request from ctx as the job's actor,
+// the audit entries of the import are written in their name.
+func (r *productImportRepository) CreateJob(ctx context.Context, job domain.ImportJob) (*domain.ImportJob, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := queries(ctx, r.db).CreateProductImportJob(ctx, db.CreateProductImportJobParams{
+		StoreID:     storeID,
+		Format:      job.Format,
+		DryRun:      job.DryRun,
+		Actor:       job.Actor,
+		ActorMethod: job.ActorMethod,
+		RequestID:   job.RequestID,
+		Ip:          job.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toImportJobEntity(&created)
+	return &entity, nil
+}
+
+func (r *productImportRepository) StageRows(ctx context.Context, jobID uuid.UUID, rows []domain.ImportRow) error {
+	params := make([]db.CreateProductImportRowsParams, 0, len(rows))
+	for _, row := range rows {
+		params = append(params, db.CreateProductImportRowsParams{
+			JobID:         jobID,
+			Line:          int32(row.Line),
+			Name:          row.Name,
+			Slug:          row.Slug,
+			Description:   row.Description,
+			Price:         row.Price,
+			CategorySlugs: row.CategorySlugs,
+			ImageUrls:     nonNil(row.ImageURLs),
+			Error:         optionalText(row.Error),
+		})
+	}
+
+	_, err := queries(ctx, r.db).CreateProductImportRows(ctx, params)
+	return err
+}
+
+func (r *productImportRepository) SetJobTotals(ctx context.Context, jobID uuid.UUID, totalRows, rejectedRows int) (*domain.ImportJob, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := queries(ctx, r.db).SetProductImportJobTotals(ctx, db.SetProductImportJobTotalsParams{
+		TotalRows:    int32(totalRows),
+		RejectedRows: int32(rejectedRows),
+		StoreID:      storeID,
+		ID:           jobID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toImportJobEntity(&job)
+	return &entity, nil
+}
+
+func (r *productImportRepository) FetchJobs(ctx context.Context, limit int) ([]domain.ImportJob, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := queries(ctx, r.db).GetProductImportJobs(ctx, db.GetProductImportJobsParams{StoreID: storeID, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ImportJob, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, toImportJobEntity(&job))
+	}
+	return result, nil
+}
+
+func (r *productImportRepository) FetchJobByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := queries(ctx, r.db).GetProductImportJob(ctx, db.GetProductImportJobParams{StoreID: storeID, ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("import_not_found", "import job not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toImportJobEntity(&job)
+	return &entity, nil
+}
+
+// FetchRejectedRows does not check the store, fetch the job first.
+func (r *productImportRepository) FetchRejectedRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]domain.ImportRow, error) {
+	rows, err := queries(ctx, r.db).GetRejectedProductImportRows(ctx, db.GetRejectedProductImportRowsParams{
+		JobID: jobID,
+		Line:  int32(afterLine),
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toImportRowEntities(rows), nil
+}
+
+func (r *productImportRepository) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ImportJob, error) {
+	job, err := queries(ctx, r.db).ClaimProductImportJob(ctx, lease.Seconds())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toImportJobEntity(&job)
+	return &entity, nil
+}
+
+func (r *productImportRepository) FetchPendingRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]domain.ImportRow, error) {
+	rows, err := queries(ctx, r.db).GetPendingProductImportRows(ctx, db.GetPendingProductImportRowsParams{
+		JobID: jobID,
+		Line:  int32(afterLine),
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toImportRowEntities(rows), nil
+}
+
+// RecordProgress fails when another worker took the job over after its lease
+// ran out, which rolls back the batch the caller applied.
+func (r *productImportRepository) RecordProgress(ctx context.Context, jobID uuid.UUID, fromLine int, progress domain.ImportProgress, lease time.Duration) error {
+	if len(progress.Rejected) > 0 {
+		lines := make([]int32, 0, len(progress.Rejected))
+		messages := make([]string, 0, len(progress.Rejected))
+		for _, row := range progress.Rejected {
+			lines = append(lines, int32(row.Line))
+			messages = append(messages, row.Error)
+		}
+
+		err := queries(ctx, r.db).RejectProductImportRows(ctx, db.RejectProductImportRowsParams{
+			Lines:  lines,
+			Errors: messages,
+			JobID:  jobID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	updated, err := queries(ctx, r.db).RecordProductImportProgress(ctx, db.RecordProductImportProgressParams{
+		LastLine:     int32(progress.LastLine),
+		Processed:    int32(progress.Processed),
+		Created:      int32(progress.Created),
+		Updated:      int32(progress.Updated),
+		Failed:       int32(len(progress.Rejected)),
+		LeaseSeconds: lease.Seconds(),
+		ID:           jobID,
+		FromLine:     int32(fromLine),
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("import job %s at line %d: %w", jobID, fromLine, domain.ErrImportJobTakenOver)
+	}
+	return nil
+}
+
+// FinishJob keeps only the rejected rows, as the job's error report.
+func (r *productImportRepository) FinishJob(ctx context.Context, jobID uuid.UUID, status, message string) error {
+	err := queries(ctx, r.db).FinishProductImportJob(ctx, db.FinishProductImportJobParams{
+		ID:     jobID,
+		Status: status,
+		Error:  optionalText(message),
+	})
+	if err != nil {
+		return err
+	}
+	return queries(ctx, r.db).DeleteAppliedProductImportRows(ctx, jobID)
+}
+
+func (r *productImportRepository) DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error) {
+	return queries(ctx, r.db).DeleteFinishedProductImportJobs(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
+
+func (r *productImportRepository) FetchCategoryIDs(ctx context.Context, slugs []string) (map[string]uuid.UUID, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := queries(ctx, r.db).GetCategoryIDsBySlugs(ctx, db.GetCategoryIDsBySlugsParams{StoreID: storeID, Slugs: slugs})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uuid.UUID, len(categories))
+	for _, c := range categories {
+		result[c.Slug] = c.ID
+	}
+	return result, nil
+}
+
+func (r *productImportRepository) FetchProductsBySlugs(ctx context.Context, slugs []string) (map[string]domain.Product, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := queries(ctx, r.db).GetProductsBySlugs(ctx, db.GetProductsBySlugsParams{StoreID: storeID, Slugs: slugs})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]domain.Product, len(products))
+	for _, p := range products {
+		result[p.Slug] = toProductEntityBySlugs(&p)
+	}
+	return result, nil
+}
+
+// UpsertProducts writes all rows with one statement per table. The slugs of
+// rows must be unique.
+func (r *productImportRepository) UpsertProducts(ctx context.Context, rows []domain.ImportRow, categoryIDs map[string]uuid.UUID) ([]domain.ImportedProduct, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.UpsertImportedProductsParams{StoreID: storeID}
+	for _, row := range rows {
+		params.Names = append(params.Names, row.Name)
+		params.Slugs = append(params.Slugs, row.Slug)
+		params.Descriptions = append(params.Descriptions, row.Description)
+		params.Prices = append(params.Prices, row.Price)
+	}
+
+	upserted, err := queries(ctx, r.db).UpsertImportedProducts(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ImportedProduct, 0, len(upserted))
+	productIDs := make(map[string]uuid.UUID, len(upserted))
+	for _, p := range upserted {
+		result = append(result, domain.ImportedProduct{ID: p.ID, Slug: p.Slug, Created: p.Inserted})
+		productIDs[p.Slug] = p.ID
+	}
+
+	// Replace the categories of rows that list any
+	var cleared, linkedProducts, linkedCategories []uuid.UUID
+	for _, row := range rows {
+		if len(row.CategorySlugs) == 0 {
+			continue
+		}
+		cleared = append(cleared, productIDs[row.Slug])
+		for _, slug := range row.CategorySlugs {
+			linkedProducts = append(linkedProducts, productIDs[row.Slug])
+			linkedCategories = append(linkedCategories, categoryIDs[slug])
+		}
+	}
+	if len(cleared) == 0 {
+		return result, nil
+	}
+
+	err = queries(ctx, r.db).ClearProductCategoriesByProductIDs(ctx, db.ClearProductCategoriesByProductIDsParams{
+		StoreID:    storeID,
+		ProductIds: cleared,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = queries(ctx, r.db).AddProductCategories(ctx, db.AddProductCategoriesParams{
+		StoreID:     storeID,
+		ProductIds:  linkedProducts,
+		CategoryIds: linkedCategories,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *productImportRepository) AddImages(ctx context.Context, rows []domain.ImportRow, productIDs map[string]uuid.UUID) ([]domain.ProductImage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.AddImportedProductImagesParams{StoreID: storeID}
+	for _, row := range rows {
+		for i, url := range row.ImageURLs {
+			params.ProductIds = append(params.ProductIds, productIDs[row.Slug])
+			params.Urls = append(params.Urls, url)
+			params.Ords = append(params.Ords, int32(i+1))
+		}
+	}
+	if len(params.Urls) == 0 {
+		return nil, nil
+	}
+
+	images, err := queries(ctx, r.db).AddImportedProductImages(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ProductImage, 0, len(images))
+	for _, img := range images {
+		result = append(result, toProductImageEntity(&img))
+	}
+	return result, nil
+}
+
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func toImportRowEntities(rows []db.ProductImportRow) []domain.ImportRow {
+	result := make([]domain.ImportRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.ImportRow{
+			Line:          int(row.Line),
+			Name:          row.Name,
+			Slug:          row.Slug,
+			Description:   row.Description,
+			Price:         row.Price,
+			CategorySlugs: row.CategorySlugs,
+			ImageURLs:     row.ImageUrls,
+			Error:         row.Error.String,
+		})
+	}
+	return result
+}
+
+func toImportJobEntity(j *db.ProductImportJob) domain.ImportJob {
+	job := domain.ImportJob{
+		ID:            j.ID,
+		StoreID:       j.StoreID,
+		Format:        j.Format,
+		DryRun:        j.DryRun,
+		Status:        j.Status,
+		TotalRows:     int(j.TotalRows),
+		ProcessedRows: int(j.ProcessedRows),
+		LastLine:      int(j.LastLine),
+		CreatedCount:  int(j.CreatedCount),
+		UpdatedCount:  int(j.UpdatedCount),
+		FailedCount:   int(j.FailedCount),
+		Error:         j.Error.String,
+		Actor:         j.Actor,
+		ActorMethod:   j.ActorMethod,
+		RequestID:     j.RequestID,
+		IP:            j.Ip,
+		CreatedAt:     j.CreatedAt.Time,
+	}
+	if j.StartedAt.Valid {
+		job.StartedAt = &j.StartedAt.Time
+	}
+	if j.FinishedAt.Valid {
+		job.FinishedAt = &j.FinishedAt.Time
+	}
+	return job
+}
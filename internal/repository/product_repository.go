@@ -9,6 +9,8 @@ import (
 	"product-listing/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type productRepository struct {
@@ -21,37 +23,43 @@ func NewProductRepository(database *config.Database) domain.ProductRepository {
 	}
 }
 
-func (r *productRepository) Create(ctx context.Context, p domain.ProductInput) error {
+func (r *productRepository) Create(ctx context.Context, p domain.ProductInput) (*domain.Product, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.CreateProductParams{
+		StoreID:     storeID,
 		Name:        p.Name,
 		Slug:        p.Slug,
 		Description: p.Description,
 		Price:       p.Price,
 	}
-	product, err := r.db.CreateProduct(ctx, params)
+	product, err := queries(ctx, r.db).CreateProduct(ctx, params)
 	if err != nil {
-		return errors.New(err.Error())
+		return nil, errors.New(err.Error())
 	}
 
-	for _, catID := range p.CategoryIDs {
-		err := r.db.AddProductCategory(ctx, db.AddProductCategoryParams{
-			ProductID:  product.ID,
-			CategoryID: catID,
-		})
-		if err != nil {
-			return err
-		}
+	if err := r.addCategories(ctx, storeID, product.ID, p.CategoryIDs); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return r.FetchById(ctx, product.ID)
 }
 
 func (r *productRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Product, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := db.GetAllProductsParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		StoreID: storeID,
+		Limit:   int32(limit),
+		Offset:  int32(offset),
 	}
-	products, err := r.db.GetAllProducts(ctx, params)
+	products, err := queries(ctx, r.db).GetAllProducts(ctx, params)
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -64,8 +72,38 @@ func (r *productRepository) Fetch(ctx context.Context, limit, offset int) ([]dom
 	return result, nil
 }
 
+func (r *productRepository) FetchAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetProductsAfterParams{StoreID: storeID, RowLimit: int32(limit)}
+	if after != nil {
+		params.AfterCreatedAt = pgtype.Timestamp{Time: after.CreatedAt, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: after.ID, Valid: true}
+	}
+	products, err := queries(ctx, r.db).GetProductsAfter(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Product, 0, len(products))
+	for _, p := range products {
+		row := db.GetAllProductsRow(p)
+		result = append(result, toProductEntity(&row))
+	}
+
+	return result, nil
+}
+
 func (r *productRepository) FetchCount(ctx context.Context) (int, error) {
-	total, err := r.db.GetProductsCount(ctx)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := queries(ctx, r.db).GetProductsCount(ctx, storeID)
 	if err != nil {
 		return int(0), err
 	}
@@ -74,7 +112,15 @@ func (r *productRepository) FetchCount(ctx context.Context) (int, error) {
 }
 
 func (r *productRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
-	product, err := r.db.GetProductByID(ctx, id)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := queries(ctx, r.db).GetProductByID(ctx, db.GetProductByIDParams{StoreID: storeID, ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("product_not_found", "product not found")
+	}
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -85,7 +131,12 @@ func (r *productRepository) FetchById(ctx context.Context, id uuid.UUID) (*domai
 }
 
 func (r *productRepository) FetchByCategory(ctx context.Context, cID uuid.UUID) ([]domain.Product, error) {
-	products, err := r.db.GetProductsByCategoryID(ctx, cID)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := queries(ctx, r.db).GetProductsByCategoryID(ctx, db.GetProductsByCategoryIDParams{StoreID: storeID, CategoryID: cID})
 	if err != nil {
 		return nil, errors.New(err.Error())
 	}
@@ -98,41 +149,70 @@ func (r *productRepository) FetchByCategory(ctx context.Context, cID uuid.UUID)
 	return result, nil
 }
 
+// FetchByCategories returns the newest limit products of each category,
+// newest first. A product in several of the categories is listed under each
+// of them.
+func (r *productRepository) FetchByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetProductsByCategoryIDsParams{
+		StoreID:     storeID,
+		CategoryIds: categoryIDs,
+		PerCategory: int32(limit),
+	}
+	products, err := queries(ctx, r.db).GetProductsByCategoryIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]domain.Product, len(categoryIDs))
+	for _, p := range products {
+		result[p.CategoryID] = append(result[p.CategoryID], toProductEntityByCategoryIDs(&p))
+	}
+	return result, nil
+}
+
 func (r *productRepository) Update(ctx context.Context, id uuid.UUID, p domain.ProductInput) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := db.UpdateProductParams{
+		StoreID:     storeID,
 		ID:          id,
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
 	}
 
-	err := r.db.UpdateProduct(ctx, params)
+	err = queries(ctx, r.db).UpdateProduct(ctx, params)
 	if err != nil {
 		return errors.New(err.Error())
 	}
 
 	if len(p.CategoryIDs) > 0 {
-		err = r.db.ClearProductCategories(ctx, id)
+		err = queries(ctx, r.db).ClearProductCategories(ctx, db.ClearProductCategoriesParams{StoreID: storeID, ProductID: id})
 		if err != nil {
 			return err
 		}
 
-		for _, catID := range p.CategoryIDs {
-			err = r.db.AddProductCategory(ctx, db.AddProductCategoryParams{
-				ProductID:  id,
-				CategoryID: catID,
-			})
-			if err != nil {
-				return err
-			}
-		}
+		return r.addCategories(ctx, storeID, id, p.CategoryIDs)
 	}
 
 	return nil
 }
 
 func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	err := r.db.DeleteProduct(ctx, id)
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = queries(ctx, r.db).DeleteProduct(ctx, db.DeleteProductParams{StoreID: storeID, ID: id})
 	if err != nil {
 		return errors.New(err.Error())
 	}
@@ -140,6 +220,26 @@ func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// addCategories links categories of the same store only; a category from
+// another store matches no row and is rejected as unknown.
+func (r *productRepository) addCategories(ctx context.Context, storeID, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	for _, catID := range categoryIDs {
+		rows, err := queries(ctx, r.db).AddProductCategory(ctx, db.AddProductCategoryParams{
+			StoreID:    storeID,
+			ProductID:  productID,
+			CategoryID: catID,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return domain.NewInvalidError("category_not_found", "category not found: "+catID.String())
+		}
+	}
+
+	return nil
+}
+
 func parseCategories(data []byte) []domain.Category {
 	if len(data) == 0 {
 		return nil
@@ -151,42 +251,80 @@ func parseCategories(data []byte) []domain.Category {
 
 func toProductEntity(p *db.GetAllProductsRow) domain.Product {
 	return domain.Product{
-		ID:              p.ID,
-		Name:            p.Name,
-		Slug:            p.Slug,
-		Description:     p.Description,
-		Price:           p.Price,
-		PrimaryImageURL: p.PrimaryImageUrl.String,
-		Categories:      parseCategories(p.Categories),
-		CreatedAt:       p.CreatedAt.Time,
-		UpdatedAt:       p.UpdatedAt.Time,
+		ID:                  p.ID,
+		Name:                p.Name,
+		Slug:                p.Slug,
+		Description:         p.Description,
+		Price:               p.Price,
+		PrimaryImageURL:     p.PrimaryImageUrl.String,
+		PrimaryImageID:      uuid.UUID(p.PrimaryImageID.Bytes),
+		PrimaryImagePrivate: p.PrimaryImageVisibility.String == domain.ImageVisibilityPrivate,
+		Categories:          parseCategories(p.Categories),
+		CreatedAt:           p.CreatedAt.Time,
+		UpdatedAt:           p.UpdatedAt.Time,
 	}
 }
 
 func toProductEntityByID(p *db.GetProductByIDRow) domain.Product {
 	return domain.Product{
-		ID:              p.ID,
-		Name:            p.Name,
-		Slug:            p.Slug,
-		Description:     p.Description,
-		Price:           p.Price,
-		PrimaryImageURL: p.PrimaryImageUrl.String,
-		Categories:      parseCategories(p.Categories),
-		CreatedAt:       p.CreatedAt.Time,
-		UpdatedAt:       p.UpdatedAt.Time,
+		ID:                  p.ID,
+		Name:                p.Name,
+		Slug:                p.Slug,
+		Description:         p.Description,
+		Price:               p.Price,
+		PrimaryImageURL:     p.PrimaryImageUrl.String,
+		PrimaryImageID:      uuid.UUID(p.PrimaryImageID.Bytes),
+		PrimaryImagePrivate: p.PrimaryImageVisibility.String == domain.ImageVisibilityPrivate,
+		Categories:          parseCategories(p.Categories),
+		CreatedAt:           p.CreatedAt.Time,
+		UpdatedAt:           p.UpdatedAt.Time,
 	}
 }
 
 func toProductEntityByCategoryID(p *db.GetProductsByCategoryIDRow) domain.Product {
 	return domain.Product{
-		ID:              p.ID,
-		Name:            p.Name,
-		Slug:            p.Slug,
-		Description:     p.Description,
-		Price:           p.Price,
-		PrimaryImageURL: p.PrimaryImageUrl.String,
-		Categories:      parseCategories(p.Categories),
-		CreatedAt:       p.CreatedAt.Time,
-		UpdatedAt:       p.UpdatedAt.Time,
+		ID:                  p.ID,
+		Name:                p.Name,
+		Slug:                p.Slug,
+		Description:         p.Description,
+		Price:               p.Price,
+		PrimaryImageURL:     p.PrimaryImageUrl.String,
+		PrimaryImageID:      uuid.UUID(p.PrimaryImageID.Bytes),
+		PrimaryImagePrivate: p.PrimaryImageVisibility.String == domain.ImageVisibilityPrivate,
+		Categories:          parseCategories(p.Categories),
+		CreatedAt:           p.CreatedAt.Time,
+		UpdatedAt:           p.UpdatedAt.Time,
+	}
+}
+
+func toProductEntityByCategoryIDs(p *db.GetProductsByCategoryIDsRow) domain.Product {
+	return domain.Product{
+		ID:                  p.ID,
+		Name:                p.Name,
+		Slug:                p.Slug,
+		Description:         p.Description,
+		Price:               p.Price,
+		PrimaryImageURL:     p.PrimaryImageUrl.String,
+		PrimaryImageID:      uuid.UUID(p.PrimaryImageID.Bytes),
+		PrimaryImagePrivate: p.PrimaryImageVisibility.String == domain.ImageVisibilityPrivate,
+		Categories:          parseCategories(p.Categories),
+		CreatedAt:           p.CreatedAt.Time,
+		UpdatedAt:           p.UpdatedAt.Time,
+	}
+}
+
+func toProductEntityBySlugs(p *db.GetProductsBySlugsRow) domain.Product {
+	return domain.Product{
+		ID:                  p.ID,
+		Name:                p.Name,
+		Slug:                p.Slug,
+		Description:         p.Description,
+		Price:               p.Price,
+		PrimaryImageURL:     p.PrimaryImageUrl.String,
+		PrimaryImageID:      uuid.UUID(p.PrimaryImageID.Bytes),
+		PrimaryImagePrivate: p.PrimaryImageVisibility.String == domain.ImageVisibilityPrivate,
+		Categories:          parseCategories(p.Categories),
+		CreatedAt:           p.CreatedAt.Time,
+		UpdatedAt:           p.UpdatedAt.Time,
 	}
 }
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+)
+
+type sitemapRepository struct {
+	db *db.Queries
+}
+
+func NewSitemapRepository(database *config.Database) domain.SitemapRepository {
+	return &sitemapRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *sitemapRepository) FetchProductPages(ctx context.Context, size int) ([]domain.SitemapPage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetProductSitemapPages(ctx, db.GetProductSitemapPagesParams{
+		StoreID:  storeID,
+		PageSize: int32(size),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]domain.SitemapPage, 0, len(rows))
+	for _, row := range rows {
+		pages = append(pages, domain.SitemapPage{
+			Kind:         domain.SitemapProducts,
+			Number:       int(row.Page),
+			LastModified: row.LastModified.Time,
+		})
+	}
+	return pages, nil
+}
+
+func (r *sitemapRepository) FetchCategoryPages(ctx context.Context, size int) ([]domain.SitemapPage, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetCategorySitemapPages(ctx, db.GetCategorySitemapPagesParams{
+		StoreID:  storeID,
+		PageSize: int32(size),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]domain.SitemapPage, 0, len(rows))
+	for _, row := range rows {
+		pages = append(pages, domain.SitemapPage{
+			Kind:         domain.SitemapCategories,
+			Number:       int(row.Page),
+			LastModified: row.LastModified.Time,
+		})
+	}
+	return pages, nil
+}
+
+func (r *sitemapRepository) FetchProducts(ctx context.Context, page, size int) ([]domain.SitemapEntry, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetSitemapProducts(ctx, db.GetSitemapProductsParams{
+		StoreID: storeID,
+		Limit:   int32(size),
+		Offset:  int32((page - 1) * size),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.SitemapEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, domain.SitemapEntry{
+			ID:        row.ID,
+			Slug:      row.Slug,
+			UpdatedAt: row.UpdatedAt.Time,
+			ImageURLs: row.ImageUrls,
+		})
+	}
+	return entries, nil
+}
+
+func (r *sitemapRepository) FetchCategories(ctx context.Context, page, size int) ([]domain.SitemapEntry, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries(ctx, r.db).GetSitemapCategories(ctx, db.GetSitemapCategoriesParams{
+		StoreID: storeID,
+		Limit:   int32(size),
+		Offset:  int32((page - 1) * size),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.SitemapEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, domain.SitemapEntry{
+			ID:        row.ID,
+			Slug:      row.Slug,
+			UpdatedAt: row.UpdatedAt.Time,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type storeRepository struct {
+	db *db.Queries
+}
+
+func NewStoreRepository(database *config.Database) domain.StoreRepository {
+	return &storeRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *storeRepository) Create(ctx context.Context, input domain.StoreInput) (*domain.Store, error) {
+	store, err := queries(ctx, r.db).CreateStore(ctx, db.CreateStoreParams{
+		Slug: input.Slug,
+		Name: input.Name,
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, domain.NewConflictError("store_slug_taken", "store slug already exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toStoreEntity(&store)
+	return &entity, nil
+}
+
+func (r *storeRepository) Fetch(ctx context.Context) ([]domain.Store, error) {
+	stores, err := queries(ctx, r.db).GetStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Store, 0, len(stores))
+	for _, s := range stores {
+		result = append(result, toStoreEntity(&s))
+	}
+	return result, nil
+}
+
+func (r *storeRepository) FetchByID(ctx context.Context, id uuid.UUID) (*domain.Store, error) {
+	store, err := queries(ctx, r.db).GetStoreByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("store_not_found", "store not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toStoreEntity(&store)
+	return &entity, nil
+}
+
+func (r *storeRepository) FetchBySlug(ctx context.Context, slug string) (*domain.Store, error) {
+	store, err := queries(ctx, r.db).GetStoreBySlug(ctx, slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("store_not_found", "store not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toStoreEntity(&store)
+	return &entity, nil
+}
+
+// currentStoreID returns the store the request was bound to. Catalog
+// repositories refuse to run without one so no query can span stores.
+func currentStoreID(ctx context.Context) (uuid.UUID, error) {
+	store := domain.StoreFromContext(ctx)
+	if store == nil {
+		return uuid.Nil, errors.New("no store bound to request")
+	}
+	return store.ID, nil
+}
+
+// allStoresSetting is the app.store_id of sessions reading every store's
+// catalog, which only the lookups that deliberately span stores ask for.
+const allStoresSetting = "*"
+
+type allStoresKey struct{}
+
+// acrossStores lets a read on ctx see the catalog rows of every store.
+func acrossStores(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allStoresKey{}, true)
+}
+
+// StoreSetting is the app.store_id setting of connections acquired with ctx:
+// the bound store's ID, allStoresSetting for reads across stores, or empty,
+// which row level security shows no catalog rows to. Pass it to
+// config.NewDatabase.
+func StoreSetting(ctx context.Context) string {
+	if store := domain.StoreFromContext(ctx); store != nil {
+		return store.ID.String()
+	}
+	if all, _ := ctx.Value(allStoresKey{}).(bool); all {
+		return allStoresSetting
+	}
+	return ""
+}
+
+func toStoreEntity(s *db.Store) domain.Store {
+	return domain.Store{
+		ID:        s.ID,
+		Slug:      s.Slug,
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStoreSetting(t *testing.T) {
+	store := &domain.Store{ID: uuid.New()}
+	bound := domain.ContextWithStore(context.Background(), store)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"unbound", context.Background(), ""},
+		{"bound", bound, store.ID.String()},
+		{"across stores", acrossStores(context.Background()), allStoresSetting},
+		// A read across stores never widens a request bound to one
+		{"bound across stores", acrossStores(bound), store.ID.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StoreSetting(tt.ctx); got != tt.want {
+				t.Errorf("StoreSetting = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// txStoreKey holds the app.store_id setting of the transaction in ctx.
+type txStoreKey struct{}
+
+type transactor struct {
+	pool *pgxpool.Pool
+}
+
+func NewTransactor(database *config.Database) domain.Transactor {
+	return &transactor{pool: database.Pool}
+}
+
+// WithinTransaction runs fn in a transaction, joining the one ctx carries if
+// any. The connection of a new transaction is set up for the store of ctx.
+// One joined with another store, such as by an outbox publisher binding the
+// store of an event, is switched to it until fn returns.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Join the outer transaction instead of opening a nested one
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		outer, _ := ctx.Value(txStoreKey{}).(string)
+		setting := StoreSetting(ctx)
+		if setting == outer {
+			return fn(ctx)
+		}
+
+		if err := setTransactionStore(ctx, tx, setting); err != nil {
+			return err
+		}
+		if err := fn(context.WithValue(ctx, txStoreKey{}, setting)); err != nil {
+			return err
+		}
+		return setTransactionStore(ctx, tx, outer)
+	}
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = context.WithValue(ctx, txStoreKey{}, StoreSetting(ctx))
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// setTransactionStore changes app.store_id until the transaction ends, after
+// which the connection has the setting it was handed out with again.
+func setTransactionStore(ctx context.Context, tx pgx.Tx, setting string) error {
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.store_id', $1, true)", setting); err != nil {
+		return fmt.Errorf("failed to set store for transaction: %w", err)
+	}
+	return nil
+}
+
+// queries returns q bound to the transaction carried by ctx, if any.
+func queries(ctx context.Context, q *db.Queries) *db.Queries {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return q.WithTx(tx)
+	}
+	return q
+}
+
+// inTransaction reports whether ctx carries a transaction.
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return ok
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-listing/config"
+	"product-listing/internal/db"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type webhookRepository struct {
+	db *db.Queries
+}
+
+func NewWebhookRepository(database *config.Database) domain.WebhookRepository {
+	return &webhookRepository{
+		db: db.New(database.Pool),
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := queries(ctx, r.db).CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		StoreID: storeID,
+		Url:     input.URL,
+		Events:  input.Events,
+		Secret:  input.Secret,
+		Active:  input.Active,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toWebhookSubscriptionEntity(&subscription)
+	return &entity, nil
+}
+
+func (r *webhookRepository) FetchSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := queries(ctx, r.db).GetWebhookSubscriptions(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookSubscriptionEntities(subscriptions), nil
+}
+
+// FetchActiveSubscriptions takes the store explicitly, it is called while a
+// change is recorded and the event carries its store.
+func (r *webhookRepository) FetchActiveSubscriptions(ctx context.Context, storeID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := queries(ctx, r.db).GetActiveWebhookSubscriptions(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookSubscriptionEntities(subscriptions), nil
+}
+
+func (r *webhookRepository) FetchSubscriptionByID(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := queries(ctx, r.db).GetWebhookSubscription(ctx, db.GetWebhookSubscriptionParams{StoreID: storeID, ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("webhook_not_found", "webhook subscription not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toWebhookSubscriptionEntity(&subscription)
+	return &entity, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := queries(ctx, r.db).UpdateWebhookSubscription(ctx, db.UpdateWebhookSubscriptionParams{
+		Url:     input.URL,
+		Events:  input.Events,
+		Active:  input.Active,
+		Secret:  optionalText(input.Secret),
+		StoreID: storeID,
+		ID:      id,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("webhook_not_found", "webhook subscription not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entity := toWebhookSubscriptionEntity(&subscription)
+	return &entity, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := queries(ctx, r.db).DeleteWebhookSubscription(ctx, db.DeleteWebhookSubscriptionParams{StoreID: storeID, ID: id})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.NewNotFoundError("webhook_not_found", "webhook subscription not found")
+	}
+	return nil
+}
+
+// CreateDelivery joins the transaction in ctx, so a delivery is only queued
+// when the change it reports is committed.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	return queries(ctx, r.db).CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+		StoreID:        delivery.StoreID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+	})
+}
+
+func (r *webhookRepository) FetchDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.GetWebhookDeliveriesParams{
+		StoreID:  storeID,
+		Status:   optionalText(filter.Status),
+		RowLimit: int32(filter.Limit),
+	}
+	if filter.BeforeID > 0 {
+		params.BeforeID = pgtype.Int8{Int64: filter.BeforeID, Valid: true}
+	}
+	if filter.SubscriptionID != nil {
+		params.SubscriptionID = pgtype.UUID{Bytes: *filter.SubscriptionID, Valid: true}
+	}
+
+	deliveries, err := queries(ctx, r.db).GetWebhookDeliveries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, toWebhookDeliveryEntity(&d))
+	}
+	return result, nil
+}
+
+func (r *webhookRepository) RetryDelivery(ctx context.Context, id int64) error {
+	storeID, err := currentStoreID(ctx)
+	if err != nil {
+		return err
+	}
+
+	retried, err := queries(ctx, r.db).RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{StoreID: storeID, ID: id})
+	if err != nil {
+		return err
+	}
+	if retried == 0 {
+		return domain.NewNotFoundError("dead_letter_not_found", "no dead delivery with this id")
+	}
+	return nil
+}
+
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	rows, err := queries(ctx, r.db).ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.WebhookDelivery{
+			ID:             row.ID,
+			StoreID:        row.StoreID,
+			SubscriptionID: row.SubscriptionID,
+			EventID:        row.EventID,
+			EventType:      row.EventType,
+			Payload:        row.Payload,
+			Status:         domain.WebhookDeliveryPending,
+			Attempts:       int(row.Attempts),
+			URL:            row.Url,
+			Secret:         row.Secret,
+		})
+	}
+	return result, nil
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, id int64, attempt domain.WebhookAttempt) error {
+	params := db.RecordWebhookDeliveryAttemptParams{
+		ID:            id,
+		Status:        attempt.Status,
+		NextAttemptAt: pgtype.Timestamptz{Time: attempt.NextAttemptAt, Valid: true},
+		LastError:     optionalText(attempt.Error),
+	}
+	if attempt.StatusCode != 0 {
+		params.LastStatusCode = pgtype.Int4{Int32: int32(attempt.StatusCode), Valid: true}
+	}
+	if attempt.DeliveredAt != nil {
+		params.DeliveredAt = pgtype.Timestamptz{Time: *attempt.DeliveredAt, Valid: true}
+	}
+	return queries(ctx, r.db).RecordWebhookDeliveryAttempt(ctx, params)
+}
+
+func toWebhookSubscriptionEntities(subscriptions []db.WebhookSubscription) []domain.WebhookSubscription {
+	result := make([]domain.WebhookSubscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		result = append(result, toWebhookSubscriptionEntity(&s))
+	}
+	return result
+}
+
+func toWebhookSubscriptionEntity(s *db.WebhookSubscription) domain.WebhookSubscription {
+	return domain.WebhookSubscription{
+		ID:        s.ID,
+		StoreID:   s.StoreID,
+		URL:       s.Url,
+		Events:    s.Events,
+		Secret:    s.Secret,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt.Time,
+		UpdatedAt: s.UpdatedAt.Time,
+	}
+}
+
+func toWebhookDeliveryEntity(d *db.WebhookDelivery) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		ID:             d.ID,
+		StoreID:        d.StoreID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       int(d.Attempts),
+		NextAttemptAt:  d.NextAttemptAt.Time,
+		LastStatusCode: int(d.LastStatusCode.Int32),
+		LastError:      d.LastError.String,
+		CreatedAt:      d.CreatedAt.Time,
+	}
+	if d.DeliveredAt.Valid {
+		delivery.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return delivery
+}
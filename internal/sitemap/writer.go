@@ -0,0 +1,73 @@
+package sitemap
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"product-listing/internal/domain"
+	"time"
+)
+
+const (
+	indexStart = xml.Header + `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n"
+	indexEnd   = "</sitemapindex>\n"
+
+	urlSetStart = xml.Header + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">` + "\n"
+	urlSetEnd   = "</urlset>\n"
+)
+
+// Sitemap is an entry of a sitemap index.
+type Sitemap struct {
+	Loc          string
+	LastModified time.Time
+}
+
+// WriteIndex writes a sitemap index listing sitemaps.
+func WriteIndex(w io.Writer, sitemaps []Sitemap) error {
+	b := bufio.NewWriter(w)
+	b.WriteString(indexStart)
+	for _, s := range sitemaps {
+		b.WriteString("  <sitemap>\n")
+		writeElement(b, "    ", "loc", s.Loc)
+		writeLastModified(b, s.LastModified)
+		b.WriteString("  </sitemap>\n")
+	}
+	b.WriteString(indexEnd)
+	return b.Flush()
+}
+
+// WriteURLSet writes a sitemap of urls, with their images in the image
+// extension.
+func WriteURLSet(w io.Writer, urls []domain.SitemapURL) error {
+	b := bufio.NewWriter(w)
+	b.WriteString(urlSetStart)
+	for _, u := range urls {
+		b.WriteString("  <url>\n")
+		writeElement(b, "    ", "loc", u.Loc)
+		writeLastModified(b, u.LastModified)
+		for _, image := range u.ImageURLs {
+			b.WriteString("    <image:image>\n")
+			writeElement(b, "      ", "image:loc", image)
+			b.WriteString("    </image:image>\n")
+		}
+		b.WriteString("  </url>\n")
+	}
+	b.WriteString(urlSetEnd)
+	return b.Flush()
+}
+
+// writeLastModified leaves lastmod out when the time is unknown.
+func writeLastModified(b *bufio.Writer, t time.Time) {
+	if !t.IsZero() {
+		writeElement(b, "    ", "lastmod", t.UTC().Format(time.RFC3339))
+	}
+}
+
+// writeElement writes a text element. Errors stick to b and come out of
+// Flush.
+func writeElement(b *bufio.Writer, indent, name, text string) {
+	b.WriteString(indent + "<" + name + ">")
+	// EscapeText also replaces characters XML cannot hold
+	_ = xml.EscapeText(b, []byte(text))
+	b.WriteString("</" + name + ">\n")
+}
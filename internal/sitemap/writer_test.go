@@ -0,0 +1,75 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"product-listing/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteURLSet(t *testing.T) {
+	modified := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	urls := []domain.SitemapURL{
+		{
+			Loc:          "https://main.example.com/products/mug?a=1&b=2",
+			LastModified: modified,
+			ImageURLs:    []string{"https://cdn.example.com/mug.jpg", "https://cdn.example.com/mug-2.jpg"},
+		},
+		{Loc: "https://main.example.com/products/pan"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteURLSet(&buf, urls); err != nil {
+		t.Fatalf("WriteURLSet: %v", err)
+	}
+
+	var set struct {
+		URLs []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+			Images  []struct {
+				Loc string `xml:"http://www.google.com/schemas/sitemap-image/1.1 loc"`
+			} `xml:"http://www.google.com/schemas/sitemap-image/1.1 image"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &set); err != nil {
+		t.Fatalf("sitemap is not well-formed: %v\n%s", err, buf.String())
+	}
+	if len(set.URLs) != 2 {
+		t.Fatalf("got %d urls, want 2", len(set.URLs))
+	}
+	first := set.URLs[0]
+	if first.Loc != urls[0].Loc || first.LastMod != "2026-10-01T12:30:00Z" || len(first.Images) != 2 {
+		t.Errorf("first url = %+v", first)
+	}
+	if strings.Contains(buf.String(), "<url>\n    <loc>https://main.example.com/products/pan</loc>\n    <lastmod>") {
+		t.Error("lastmod written for a URL without a modification time")
+	}
+}
+
+func TestWriteIndex(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteIndex(&buf, []Sitemap{
+		{Loc: "https://main.example.com/sitemaps/categories-1.xml"},
+		{Loc: "https://main.example.com/sitemaps/products-1.xml", LastModified: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("WriteIndex: %v", err)
+	}
+
+	var index struct {
+		XMLName  xml.Name
+		Sitemaps []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &index); err != nil {
+		t.Fatalf("index is not well-formed: %v", err)
+	}
+	if index.XMLName.Local != "sitemapindex" || len(index.Sitemaps) != 2 || index.Sitemaps[1].LastMod != "2026-10-01T00:00:00Z" {
+		t.Errorf("index = %+v", index)
+	}
+}
@@ -0,0 +1,41 @@
+package storefront
+
+import (
+	"net/url"
+	"product-listing/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Links builds the public URLs of catalog pages on the storefront. BaseURL may
+// contain {store}, the slug of the store, for storefronts with a host per
+// store. ProductPath and CategoryPath may contain {store}, {slug} and {id}.
+type Links struct {
+	BaseURL      string
+	ProductPath  string
+	CategoryPath string
+}
+
+// Home is the storefront of store.
+func (l Links) Home(store *domain.Store) string {
+	return expand(l.BaseURL, store, "", "")
+}
+
+// Product is the page of a product in store.
+func (l Links) Product(store *domain.Store, slug string, id uuid.UUID) string {
+	return l.Home(store) + expand(l.ProductPath, store, slug, id.String())
+}
+
+// Category is the page of a category in store.
+func (l Links) Category(store *domain.Store, slug string, id uuid.UUID) string {
+	return l.Home(store) + expand(l.CategoryPath, store, slug, id.String())
+}
+
+func expand(template string, store *domain.Store, slug, id string) string {
+	return strings.NewReplacer(
+		"{store}", url.PathEscape(store.Slug),
+		"{slug}", url.PathEscape(slug),
+		"{id}", id,
+	).Replace(template)
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-listing/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+	// auditExportPageSize is how many entries an export reads per query
+	auditExportPageSize = 1000
+)
+
+var auditActions = map[string]bool{
+	domain.AuditActionCreate: true,
+	domain.AuditActionUpdate: true,
+	domain.AuditActionDelete: true,
+}
+
+type AuditUsecase interface {
+	Record(ctx context.Context, action, entityType, entityID string, before, after any) error
+	GetEntries(ctx context.Context, filter domain.AuditFilter, cursor string) ([]domain.AuditEntry, string, error)
+	Export(ctx context.Context, filter domain.AuditFilter, emit func(domain.AuditEntry) error) error
+}
+
+// auditUsecase tells listeners about every recorded write, so whatever they
+// do commits or rolls back together with the change.
+type auditUsecase struct {
+	repo      domain.AuditRepository
+	listeners []domain.ChangeListener
+}
+
+func NewAuditUsecase(repo domain.AuditRepository, listeners ...domain.ChangeListener) AuditUsecase {
+	return &auditUsecase{repo: repo, listeners: listeners}
+}
+
+// Record appends an entry for a write made by the caller in ctx. Call it
+// inside the transaction making the change so both commit or neither does.
+// before and after are stored as JSON; pass nil for the side that does not
+// exist.
+func (u *auditUsecase) Record(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	store := domain.StoreFromContext(ctx)
+	if store == nil {
+		return fmt.Errorf("audit %s %s %s: no store bound to request", action, entityType, entityID)
+	}
+
+	entry := domain.AuditEntry{
+		StoreID:     store.ID,
+		Actor:       "anonymous",
+		ActorMethod: "none",
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+	}
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		entry.Actor = principal.Subject
+		entry.ActorMethod = principal.Method
+	}
+	meta := domain.RequestMetaFromContext(ctx)
+	entry.RequestID = meta.ID
+	entry.IP = meta.IP
+
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+
+	if err := u.repo.Create(ctx, entry); err != nil {
+		return err
+	}
+
+	if len(u.listeners) == 0 {
+		return nil
+	}
+
+	event := domain.ChangeEvent{
+		ID:         uuid.New(),
+		StoreID:    entry.StoreID,
+		Type:       domain.ChangeEventType(entityType, action),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Data:       entry.After,
+		OccurredAt: time.Now().UTC(),
+	}
+	if action == domain.AuditActionDelete {
+		event.Data = entry.Before
+	}
+	for _, listener := range u.listeners {
+		if err := listener.OnChange(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEntries returns one page of entries, newest first, and the cursor of the
+// next page, which is empty on the last page.
+func (u *auditUsecase) GetEntries(ctx context.Context, filter domain.AuditFilter, cursor string) ([]domain.AuditEntry, string, error) {
+	if err := validateAuditFilter(filter); err != nil {
+		return nil, "", err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	if filter.Limit > maxAuditPageSize {
+		filter.Limit = maxAuditPageSize
+	}
+
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, "", domain.NewInvalidError("invalid_cursor", "invalid cursor: "+cursor)
+		}
+		filter.BeforeID = id
+	}
+
+	entries, err := u.repo.Fetch(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(entries) == filter.Limit {
+		next = strconv.FormatInt(entries[len(entries)-1].ID, 10)
+	}
+	return entries, next, nil
+}
+
+// Export hands every matching entry to emit, newest first, reading the log
+// page by page so exports of any size run in constant memory.
+func (u *auditUsecase) Export(ctx context.Context, filter domain.AuditFilter, emit func(domain.AuditEntry) error) error {
+	if err := validateAuditFilter(filter); err != nil {
+		return err
+	}
+
+	filter.Limit = auditExportPageSize
+	filter.BeforeID = 0
+	for {
+		entries, err := u.repo.Fetch(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := emit(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < filter.Limit {
+			return nil
+		}
+		filter.BeforeID = entries[len(entries)-1].ID
+	}
+}
+
+func validateAuditFilter(filter domain.AuditFilter) error {
+	if filter.Action != "" && !auditActions[filter.Action] {
+		return domain.NewInvalidError("invalid_action", "action must be create, update or delete")
+	}
+	return nil
+}
+
+func auditSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	return data, nil
+}
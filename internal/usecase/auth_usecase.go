@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"product-listing/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyTouchInterval limits how often last_used_at is written for a key.
+const apiKeyTouchInterval = time.Minute
+
+type AuthUsecase interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error)
+	AuthenticateToken(ctx context.Context, token string) (*domain.Principal, error)
+	CreateAPIKey(ctx context.Context, name string, role domain.Role, storeSlug string) (*domain.APIKey, string, error)
+	GetAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// authUsecase stores API keys as SHA-256 hashes only; the plain key is shown
+// once, when it is created. bootstrapKey is an optional admin key taken from
+// the environment so the first real keys can be created.
+type authUsecase struct {
+	repo         domain.APIKeyRepository
+	stores       domain.StoreRepository
+	verifier     domain.TokenVerifier
+	bootstrapKey string
+}
+
+func NewAuthUsecase(repo domain.APIKeyRepository, stores domain.StoreRepository, verifier domain.TokenVerifier, bootstrapKey string) AuthUsecase {
+	return &authUsecase{repo: repo, stores: stores, verifier: verifier, bootstrapKey: bootstrapKey}
+}
+
+func (u *authUsecase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	hash := hashAPIKey(key)
+
+	if u.bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(hashAPIKey(u.bootstrapKey))) == 1 {
+		return &domain.Principal{Subject: "bootstrap", Role: domain.RoleAdmin, Method: "api_key"}, nil
+	}
+
+	apiKey, err := u.repo.FetchActiveByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, domain.NewUnauthorizedError("invalid_api_key", "invalid api key")
+	}
+
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		_ = u.repo.Touch(ctx, apiKey.ID)
+	}
+
+	principal := &domain.Principal{Subject: apiKey.ID.String(), Role: apiKey.Role, Method: "api_key"}
+	if apiKey.StoreID != nil {
+		principal.Store = apiKey.StoreID.String()
+	}
+	return principal, nil
+}
+
+func (u *authUsecase) AuthenticateToken(ctx context.Context, token string) (*domain.Principal, error) {
+	if u.verifier == nil {
+		return nil, domain.NewUnauthorizedError("jwt_disabled", "bearer tokens are not enabled")
+	}
+
+	principal, err := u.verifier.Verify(token)
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("invalid_token", "invalid token: "+err.Error())
+	}
+
+	return principal, nil
+}
+
+// CreateAPIKey binds the key to the store with storeSlug, or leaves it free to
+// act on any store when storeSlug is empty.
+func (u *authUsecase) CreateAPIKey(ctx context.Context, name string, role domain.Role, storeSlug string) (*domain.APIKey, string, error) {
+	if name == "" {
+		return nil, "", domain.NewInvalidError("api_key_name_required", "api key name cannot be empty")
+	}
+	if !role.Valid() {
+		return nil, "", domain.NewInvalidError("invalid_role", "role must be viewer, editor or admin")
+	}
+
+	var storeID *uuid.UUID
+	if storeSlug != "" {
+		store, err := u.stores.FetchBySlug(ctx, storeSlug)
+		if err != nil {
+			return nil, "", err
+		}
+		storeID = &store.ID
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := "pl_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey, err := u.repo.Create(ctx, domain.APIKeyInput{
+		Name:    name,
+		Prefix:  key[:10],
+		KeyHash: hashAPIKey(key),
+		Role:    role,
+		StoreID: storeID,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (u *authUsecase) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return u.repo.Fetch(ctx)
+}
+
+func (u *authUsecase) RevokeAPIKey(ctx context.Context, id string) error {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return domain.NewInvalidError("invalid_api_key_id", "invalid id: "+id)
+	}
+
+	return u.repo.Revoke(ctx, uid)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -9,38 +9,52 @@ import (
 )
 
 type CategoryUsecase interface {
-	CreateCategory(ctx context.Context, c domain.CategoryInput) error
+	CreateCategory(ctx context.Context, c domain.CategoryInput) (*domain.Category, error)
 	GetCategories(ctx context.Context, limit, offset int) ([]domain.Category, error)
 	GetCategoryById(ctx context.Context, id string) (*domain.Category, error)
 	GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error)
 	GetCategoryCount(ctx context.Context) (int, error)
-	UpdateCategory(ctx context.Context, id string, c domain.CategoryInput) error
+	UpdateCategory(ctx context.Context, id string, c domain.CategoryInput) (*domain.Category, error)
 	DeleteCategory(ctx context.Context, id string) error
 }
 
+// categoryUsecase records every write in the audit log, in the same
+// transaction as the write.
 type categoryUsecase struct {
-	repo domain.CategoryRepository
+	repo  domain.CategoryRepository
+	tx    domain.Transactor
+	audit AuditUsecase
 }
 
-func NewCategoryUsecase(repo domain.CategoryRepository) CategoryUsecase {
-	return &categoryUsecase{repo: repo}
+func NewCategoryUsecase(repo domain.CategoryRepository, tx domain.Transactor, audit AuditUsecase) CategoryUsecase {
+	return &categoryUsecase{repo: repo, tx: tx, audit: audit}
 }
 
-func (u *categoryUsecase) CreateCategory(ctx context.Context, c domain.CategoryInput) error {
+func (u *categoryUsecase) CreateCategory(ctx context.Context, c domain.CategoryInput) (*domain.Category, error) {
 	if c.Name == "" {
-		return errors.New("Category name cannot be empty")
+		return nil, errors.New("Category name cannot be empty")
 	}
 
 	if c.Slug == "" {
-		return errors.New("Category slug cannot be empty")
+		return nil, errors.New("Category slug cannot be empty")
 	}
 
-	err := u.repo.Create(ctx, c)
+	var category *domain.Category
+	err := u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		category, err = u.repo.Create(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionCreate, domain.AuditEntityCategory, category.ID.String(), nil, category)
+	})
 	if err != nil {
-		return errors.New(err.Error())
+		return nil, errors.New(err.Error())
 	}
 
-	return nil
+	return category, nil
 }
 
 func (u *categoryUsecase) GetCategories(ctx context.Context, page, limit int) ([]domain.Category, error) {
@@ -85,24 +99,63 @@ func (u *categoryUsecase) GetCategoryBySlug(ctx context.Context, slug string) (*
 	return category, nil
 }
 
-func (u *categoryUsecase) UpdateCategory(ctx context.Context, id string, c domain.CategoryInput) error {
-	uid, _ := uuid.Parse(id)
+// GetCategoriesByIDs loads many categories in one query. IDs without a
+// category are left out of the result.
+func (u *categoryUsecase) GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return u.repo.FetchByIDs(ctx, ids)
+}
 
-	err := u.repo.Update(ctx, uid, c)
+func (u *categoryUsecase) UpdateCategory(ctx context.Context, id string, c domain.CategoryInput) (*domain.Category, error) {
+	uid, err := parseID(id, "invalid_id")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	var after *domain.Category
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		if err := u.repo.Update(ctx, uid, c); err != nil {
+			return err
+		}
+
+		after, err = u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityCategory, id, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
 }
 
 func (u *categoryUsecase) DeleteCategory(ctx context.Context, id string) error {
-	uid, _ := uuid.Parse(id)
-
-	err := u.repo.Delete(ctx, uid)
+	uid, err := parseID(id, "invalid_id")
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		if err := u.repo.Delete(ctx, uid); err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionDelete, domain.AuditEntityCategory, id, before, nil)
+	})
 }
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+)
+
+// changeFeedBatchSize is how many events a feed is handed per read.
+const changeFeedBatchSize = 100
+
+var changeFeedEntityTypes = map[string]bool{
+	domain.AuditEntityCategory:     true,
+	domain.AuditEntityProduct:      true,
+	domain.AuditEntityProductImage: true,
+}
+
+type ChangeFeedUsecase interface {
+	// Start returns the position a feed of the request's store begins after:
+	// lastEventID when a client resumes, the head of the outbox otherwise.
+	Start(ctx context.Context, lastEventID string, entityTypes []string) (domain.OutboxPosition, error)
+	// Next returns the committed events after position, in outbox order.
+	Next(ctx context.Context, position domain.OutboxPosition, entityTypes []string) ([]domain.OutboxEvent, error)
+}
+
+// changeFeedUsecase reads the outbox for live change feeds. Feeds keep no
+// cursor of their own, a client resumes by sending back the ID of the last
+// event it saw, which works as long as the outbox retains the events.
+type changeFeedUsecase struct {
+	repo domain.OutboxRepository
+}
+
+func NewChangeFeedUsecase(repo domain.OutboxRepository) ChangeFeedUsecase {
+	return &changeFeedUsecase{repo: repo}
+}
+
+func (u *changeFeedUsecase) Start(ctx context.Context, lastEventID string, entityTypes []string) (domain.OutboxPosition, error) {
+	for _, t := range entityTypes {
+		if !changeFeedEntityTypes[t] {
+			return domain.OutboxPosition{}, domain.NewInvalidError("invalid_entity_type", "entity_type must be category, product or product_image")
+		}
+	}
+
+	if lastEventID == "" {
+		return u.repo.Head(ctx)
+	}
+
+	position, err := domain.ParseOutboxPosition(lastEventID)
+	if err != nil {
+		return domain.OutboxPosition{}, domain.NewInvalidError("invalid_last_event_id", "invalid Last-Event-ID: "+lastEventID)
+	}
+	return position, nil
+}
+
+func (u *changeFeedUsecase) Next(ctx context.Context, position domain.OutboxPosition, entityTypes []string) ([]domain.OutboxEvent, error) {
+	return u.repo.FetchStoreAfter(ctx, position, entityTypes, changeFeedBatchSize)
+}
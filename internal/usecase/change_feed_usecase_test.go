@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestChangeFeedStartsAtHeadAndResumes(t *testing.T) {
+	repo := &fakeOutboxRepository{}
+	ctx := context.Background()
+	_ = repo.Create(ctx, domain.ChangeEvent{ID: uuid.New(), EntityType: domain.AuditEntityProduct})
+	feed := NewChangeFeedUsecase(repo)
+
+	position, err := feed.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// Only changes after the start are sent
+	product := uuid.New()
+	_ = repo.Create(ctx, domain.ChangeEvent{ID: uuid.New(), EntityType: domain.AuditEntityCategory})
+	_ = repo.Create(ctx, domain.ChangeEvent{ID: product, EntityType: domain.AuditEntityProduct})
+
+	events, err := feed.Next(ctx, position, []string{domain.AuditEntityProduct})
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(events) != 1 || events[0].Event.ID != product {
+		t.Fatalf("Next = %v, want only the new product event", events)
+	}
+
+	resumed, err := feed.Start(ctx, events[0].Position.String(), nil)
+	if err != nil || resumed != events[0].Position {
+		t.Fatalf("Start(%q) = %v, %v", events[0].Position, resumed, err)
+	}
+	if events, _ := feed.Next(ctx, resumed, nil); len(events) != 0 {
+		t.Errorf("Next after the last event = %v, want none", events)
+	}
+}
+
+func TestChangeFeedRejectsInvalidInput(t *testing.T) {
+	feed := NewChangeFeedUsecase(&fakeOutboxRepository{})
+
+	for _, tc := range []struct {
+		lastEventID string
+		entityTypes []string
+	}{
+		{lastEventID: "12"},
+		{lastEventID: "a-1"},
+		{lastEventID: "-1-2"},
+		{entityTypes: []string{"store"}},
+	} {
+		var domainErr *domain.Error
+		_, err := feed.Start(context.Background(), tc.lastEventID, tc.entityTypes)
+		if !errors.As(err, &domainErr) || domainErr.Kind != domain.ErrorKindInvalid {
+			t.Errorf("Start(%q, %v) = %v, want an invalid error", tc.lastEventID, tc.entityTypes, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idempotencySweepInterval is how often expired keys are deleted.
+const idempotencySweepInterval = time.Minute
+
+type IdempotencyUsecase interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*domain.IdempotentResponse, uuid.UUID, error)
+	// Complete stores the response to replay, its RequestHash and ExpiresAt
+	// are ignored.
+	Complete(ctx context.Context, scope, key string, claim uuid.UUID, response domain.IdempotentResponse) error
+	Release(ctx context.Context, scope, key string, claim uuid.UUID) error
+}
+
+// idempotencyUsecase remembers responses for ttl. Keys are scoped to the
+// caller so two clients picking the same key do not collide. A request holds
+// its key for lockTimeout until it completes, so the key of a request that
+// crashed can be claimed again once that has passed.
+type idempotencyUsecase struct {
+	repo        domain.IdempotencyRepository
+	ttl         time.Duration
+	lockTimeout time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewIdempotencyUsecase(repo domain.IdempotencyRepository, ttl, lockTimeout time.Duration) IdempotencyUsecase {
+	return &idempotencyUsecase{repo: repo, ttl: ttl, lockTimeout: lockTimeout, lastSweep: time.Now()}
+}
+
+// Begin claims the key for a new request and returns the claim, which
+// completes or releases it, or returns the stored response of an identical
+// earlier request. A request with different content fails as unprocessable,
+// and one racing an unfinished request with the same key as a conflict until
+// its lock times out.
+func (u *idempotencyUsecase) Begin(ctx context.Context, scope, key, requestHash string) (*domain.IdempotentResponse, uuid.UUID, error) {
+	u.maybeSweep()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		claim := uuid.New()
+		reserved, err := u.repo.Reserve(ctx, scope, key, requestHash, claim, time.Now().Add(u.lockTimeout))
+		if err != nil {
+			return nil, uuid.Nil, err
+		}
+		if reserved {
+			return nil, claim, nil
+		}
+
+		stored, err := u.repo.Get(ctx, scope, key)
+		if err != nil {
+			return nil, uuid.Nil, err
+		}
+		// Expired, abandoned by a request that did not complete within the
+		// lock timeout, or released between the two queries: claim it afresh.
+		// Only an expired key is released, so of several retries reclaiming
+		// it at once, one gets it and the others see it in progress.
+		if stored == nil || time.Now().After(stored.ExpiresAt) {
+			if stored != nil {
+				if err := u.repo.ReleaseExpired(ctx, scope, key); err != nil {
+					return nil, uuid.Nil, err
+				}
+			}
+			continue
+		}
+
+		if stored.RequestHash != requestHash {
+			return nil, uuid.Nil, domain.NewUnprocessableError("idempotency_key_reused", "idempotency key was already used for a different request")
+		}
+		if stored.StatusCode == 0 {
+			return nil, uuid.Nil, domain.NewConflictError("idempotency_key_in_progress", "a request with this idempotency key is still in progress")
+		}
+		return stored, uuid.Nil, nil
+	}
+
+	return nil, uuid.Nil, domain.NewConflictError("idempotency_key_in_progress", "a request with this idempotency key is still in progress")
+}
+
+// Complete does nothing when claim lost the key after its lock timed out.
+func (u *idempotencyUsecase) Complete(ctx context.Context, scope, key string, claim uuid.UUID, response domain.IdempotentResponse) error {
+	return u.repo.Complete(ctx, scope, key, claim, response, time.Now().Add(u.ttl))
+}
+
+// Release forgets a key whose request did not finish, so it can be retried.
+// It does nothing when claim lost the key after its lock timed out.
+func (u *idempotencyUsecase) Release(ctx context.Context, scope, key string, claim uuid.UUID) error {
+	return u.repo.Release(ctx, scope, key, claim)
+}
+
+func (u *idempotencyUsecase) maybeSweep() {
+	u.mu.Lock()
+	due := time.Since(u.lastSweep) > idempotencySweepInterval
+	if due {
+		u.lastSweep = time.Now()
+	}
+	u.mu.Unlock()
+
+	if due {
+		go func() {
+			_, _ = u.repo.DeleteExpired(context.Background())
+		}()
+	}
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeIdempotencyKey struct {
+	response domain.IdempotentResponse
+	claim    uuid.UUID
+}
+
+// fakeIdempotencyRepository holds keys in memory with the conditions of the
+// queries, each call being atomic like a statement.
+type fakeIdempotencyRepository struct {
+	mu   sync.Mutex
+	keys map[string]fakeIdempotencyKey
+}
+
+func newFakeIdempotencyRepository() *fakeIdempotencyRepository {
+	return &fakeIdempotencyRepository{keys: make(map[string]fakeIdempotencyKey)}
+}
+
+func (r *fakeIdempotencyRepository) Reserve(ctx context.Context, scope, key, requestHash string, claim uuid.UUID, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[scope+key]; ok {
+		return false, nil
+	}
+	r.keys[scope+key] = fakeIdempotencyKey{
+		response: domain.IdempotentResponse{RequestHash: requestHash, ExpiresAt: expiresAt},
+		claim:    claim,
+	}
+	return true, nil
+}
+
+func (r *fakeIdempotencyRepository) Get(ctx context.Context, scope, key string) (*domain.IdempotentResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.keys[scope+key]
+	if !ok {
+		return nil, nil
+	}
+	return &stored.response, nil
+}
+
+func (r *fakeIdempotencyRepository) Complete(ctx context.Context, scope, key string, claim uuid.UUID, response domain.IdempotentResponse, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.keys[scope+key]
+	if !ok || stored.claim != claim || stored.response.StatusCode != 0 {
+		return nil
+	}
+	response.RequestHash, response.ExpiresAt = stored.response.RequestHash, expiresAt
+	r.keys[scope+key] = fakeIdempotencyKey{response: response, claim: claim}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Release(ctx context.Context, scope, key string, claim uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[scope+key]; ok && stored.claim == claim && stored.response.StatusCode == 0 {
+		delete(r.keys, scope+key)
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) ReleaseExpired(ctx context.Context, scope, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[scope+key]; ok && time.Now().After(stored.response.ExpiresAt) {
+		delete(r.keys, scope+key)
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// expire makes the claim on a key time out, as if its request had crashed.
+func (r *fakeIdempotencyRepository) expire(scope, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.keys[scope+key]
+	stored.response.ExpiresAt = time.Now().Add(-time.Second)
+	r.keys[scope+key] = stored
+}
+
+func (r *fakeIdempotencyRepository) get(scope, key string) fakeIdempotencyKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys[scope+key]
+}
+
+func TestBeginReclaimsAbandonedKey(t *testing.T) {
+	repo := newFakeIdempotencyRepository()
+	u := NewIdempotencyUsecase(repo, 24*time.Hour, time.Minute)
+	ctx := context.Background()
+
+	if stored, _, err := u.Begin(ctx, "caller", "key", "hash"); stored != nil || err != nil {
+		t.Fatalf("first Begin = %v, %v, want a fresh claim", stored, err)
+	}
+
+	var domainErr *domain.Error
+	_, _, err := u.Begin(ctx, "caller", "key", "hash")
+	if !errors.As(err, &domainErr) || domainErr.Code != "idempotency_key_in_progress" {
+		t.Fatalf("Begin while in progress = %v, want idempotency_key_in_progress", err)
+	}
+
+	repo.expire("caller", "key")
+	stored, claim, err := u.Begin(ctx, "caller", "key", "hash")
+	if stored != nil || err != nil {
+		t.Fatalf("Begin after the lock timeout = %v, %v, want a fresh claim", stored, err)
+	}
+	if until := time.Until(repo.get("caller", "key").response.ExpiresAt); until > time.Minute {
+		t.Errorf("claim held for %v, want at most the lock timeout", until)
+	}
+
+	created := domain.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Location: "/api/v2/products/1", Body: []byte(`{}`)}
+	if err := u.Complete(ctx, "caller", "key", claim, created); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if until := time.Until(repo.get("caller", "key").response.ExpiresAt); until < time.Hour {
+		t.Errorf("response kept for %v, want the ttl", until)
+	}
+	stored, _, err = u.Begin(ctx, "caller", "key", "hash")
+	if err != nil || stored == nil || stored.StatusCode != 201 || stored.Location != created.Location {
+		t.Errorf("Begin after Complete = %v, %v, want the stored response", stored, err)
+	}
+}
+
+func TestBeginReclaimsOnceUnderConcurrency(t *testing.T) {
+	repo := newFakeIdempotencyRepository()
+	u := NewIdempotencyUsecase(repo, 24*time.Hour, time.Minute)
+	ctx := context.Background()
+
+	if _, _, err := u.Begin(ctx, "caller", "key", "hash"); err != nil {
+		t.Fatalf("first Begin: %v", err)
+	}
+	repo.expire("caller", "key")
+
+	const retries = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claims  int
+		waiting int
+	)
+	for range retries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stored, _, err := u.Begin(ctx, "caller", "key", "hash")
+			mu.Lock()
+			defer mu.Unlock()
+			var domainErr *domain.Error
+			switch {
+			case err == nil && stored == nil:
+				claims++
+			case errors.As(err, &domainErr) && domainErr.Code == "idempotency_key_in_progress":
+				waiting++
+			default:
+				t.Errorf("Begin = %v, %v", stored, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claims != 1 || waiting != retries-1 {
+		t.Errorf("%d retries claimed the key and %d saw it in progress, want 1 and %d", claims, waiting, retries-1)
+	}
+}
+
+func TestStaleClaimCannotTouchReclaimedKey(t *testing.T) {
+	repo := newFakeIdempotencyRepository()
+	u := NewIdempotencyUsecase(repo, 24*time.Hour, time.Minute)
+	ctx := context.Background()
+
+	_, original, err := u.Begin(ctx, "caller", "key", "hash")
+	if err != nil {
+		t.Fatalf("first Begin: %v", err)
+	}
+	repo.expire("caller", "key")
+	_, retry, err := u.Begin(ctx, "caller", "key", "hash")
+	if err != nil {
+		t.Fatalf("retry Begin: %v", err)
+	}
+
+	// The original request finishes late, one way or the other
+	if err := u.Release(ctx, "caller", "key", original); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := u.Complete(ctx, "caller", "key", original, domain.IdempotentResponse{StatusCode: 201}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if stored := repo.get("caller", "key"); stored.claim != retry || stored.response.StatusCode != 0 {
+		t.Fatalf("key = %+v, want it still held by the retry", stored)
+	}
+
+	if err := u.Complete(ctx, "caller", "key", retry, domain.IdempotentResponse{StatusCode: 202}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if stored := repo.get("caller", "key"); stored.response.StatusCode != 202 {
+		t.Errorf("stored status = %d, want the retry's 202", stored.response.StatusCode)
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"time"
+)
+
+// gcBatchSize is how many blobs are checked against the database at once.
+const gcBatchSize = 500
+
+type MediaGCUsecase interface {
+	Collect(ctx context.Context, dryRun bool) (*domain.MediaGCReport, error)
+}
+
+// mediaGCUsecase deletes blobs that no product image points at. A blob is
+// referenced when an image URL equals baseURL followed by the blob key.
+type mediaGCUsecase struct {
+	store   domain.BlobStore
+	repo    domain.ProductImageRepository
+	baseURL string
+	grace   time.Duration
+}
+
+func NewMediaGCUsecase(store domain.BlobStore, repo domain.ProductImageRepository, baseURL string, grace time.Duration) MediaGCUsecase {
+	return &mediaGCUsecase{store: store, repo: repo, baseURL: baseURL, grace: grace}
+}
+
+func (u *mediaGCUsecase) Collect(ctx context.Context, dryRun bool) (*domain.MediaGCReport, error) {
+	report := &domain.MediaGCReport{DryRun: dryRun}
+	cutoff := time.Now().Add(-u.grace)
+
+	batch := make([]domain.Blob, 0, gcBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		urls := make([]string, 0, len(batch))
+		for _, blob := range batch {
+			urls = append(urls, u.baseURL+blob.Key)
+		}
+
+		referenced, err := u.repo.GetReferencedURLs(ctx, urls)
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range batch {
+			switch {
+			case referenced[u.baseURL+blob.Key]:
+				report.Referenced++
+			case blob.ModTime.After(cutoff):
+				report.Recent++
+			default:
+				report.Orphaned = append(report.Orphaned, blob)
+				if dryRun {
+					continue
+				}
+				if err := u.store.Delete(ctx, blob.Key); err != nil {
+					return err
+				}
+				report.Deleted++
+			}
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	err := u.store.List(ctx, func(blob domain.Blob) error {
+		report.Scanned++
+		batch = append(batch, blob)
+		if len(batch) < gcBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
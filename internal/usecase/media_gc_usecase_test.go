@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"product-listing/internal/domain"
+	"product-listing/internal/storage"
+	"slices"
+	"testing"
+	"time"
+)
+
+// referencedURLs reports the URLs in the set as referenced by an image.
+type referencedURLs struct {
+	domain.ProductImageRepository
+	urls map[string]bool
+}
+
+func (r referencedURLs) GetReferencedURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	for _, url := range urls {
+		if r.urls[url] {
+			referenced[url] = true
+		}
+	}
+	return referenced, nil
+}
+
+// writeBlob creates a file under dir last modified age ago.
+func writeBlob(t *testing.T, dir, key string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func orphanKeys(report *domain.MediaGCReport) []string {
+	var keys []string
+	for _, blob := range report.Orphaned {
+		keys = append(keys, blob.Key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestMediaGCCollect(t *testing.T) {
+	const baseURL = "https://media.example.com/"
+	dir := t.TempDir()
+	writeBlob(t, dir, "products/referenced.jpg", 48*time.Hour)
+	writeBlob(t, dir, "products/orphaned.jpg", 48*time.Hour)
+	writeBlob(t, dir, "products/2026/old.png", 25*time.Hour)
+	writeBlob(t, dir, "products/uploading.jpg", time.Hour)
+
+	repo := referencedURLs{urls: map[string]bool{baseURL + "products/referenced.jpg": true}}
+	gc := NewMediaGCUsecase(storage.NewLocalStore(dir), repo, baseURL, 24*time.Hour)
+	wantOrphans := []string{"products/2026/old.png", "products/orphaned.jpg"}
+
+	report, err := gc.Collect(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Collect dry run: %v", err)
+	}
+	if report.Scanned != 4 || report.Referenced != 1 || report.Recent != 1 || report.Deleted != 0 || !report.DryRun {
+		t.Errorf("dry run report = %+v, want 4 scanned, 1 referenced, 1 recent and none deleted", report)
+	}
+	if got := orphanKeys(report); !slices.Equal(got, wantOrphans) {
+		t.Errorf("dry run orphans = %v, want %v", got, wantOrphans)
+	}
+	for _, key := range wantOrphans {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key))); err != nil {
+			t.Errorf("dry run removed %s: %v", key, err)
+		}
+	}
+
+	report, err = gc.Collect(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if report.Deleted != 2 || report.DryRun {
+		t.Errorf("report = %+v, want 2 deleted", report)
+	}
+	if got := orphanKeys(report); !slices.Equal(got, wantOrphans) {
+		t.Errorf("orphans = %v, want %v", got, wantOrphans)
+	}
+	for key, kept := range map[string]bool{
+		"products/referenced.jpg": true,
+		"products/uploading.jpg":  true,
+		"products/orphaned.jpg":   false,
+		"products/2026/old.png":   false,
+	} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key)))
+		if exists := err == nil; exists != kept {
+			t.Errorf("%s exists = %t, want %t", key, exists, kept)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"product-listing/internal/domain"
+	"slices"
+	"time"
+)
+
+// outboxBatchSize is how many events a publisher is handed per relay.
+const outboxBatchSize = 100
+
+type OutboxRelay interface {
+	// Relay publishes the next batch of events to every publisher and
+	// returns how many it published in total.
+	Relay(ctx context.Context) (int, error)
+	// Prune deletes events every publisher has passed, once they are older
+	// than the retention.
+	Prune(ctx context.Context) (int, error)
+}
+
+// outboxRelay publishes the outbox to each publisher in order, at least once.
+// Every publisher has its own cursor, so a failing publisher holds back only
+// itself. The cursor is locked while a batch is published, so several
+// instances can run the relay without sending an event out of order.
+type outboxRelay struct {
+	repo       domain.OutboxRepository
+	tx         domain.Transactor
+	publishers map[string]domain.EventPublisher
+	retention  time.Duration
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, tx domain.Transactor, publishers map[string]domain.EventPublisher, retention time.Duration) OutboxRelay {
+	return &outboxRelay{repo: repo, tx: tx, publishers: publishers, retention: retention}
+}
+
+func (r *outboxRelay) Relay(ctx context.Context) (int, error) {
+	total := 0
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(r.publishers)) {
+		relayed, err := r.relay(ctx, name, r.publishers[name])
+		total += relayed
+		if err != nil {
+			errs = append(errs, fmt.Errorf("publisher %s: %w", name, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// relay publishes one batch and moves the cursor past the events that went
+// out. A publisher that fails gets the failed event again on the next relay.
+// The publisher runs inside the transaction, so one that writes to the
+// database does so exactly once.
+func (r *outboxRelay) relay(ctx context.Context, name string, publisher domain.EventPublisher) (int, error) {
+	relayed := 0
+	var publishErr error
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		position, locked, err := r.repo.LockCursor(ctx, name)
+		if err != nil || !locked {
+			return err
+		}
+
+		events, err := r.repo.FetchAfter(ctx, position, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if publishErr = publisher.Publish(ctx, e.Event); publishErr != nil {
+				break
+			}
+			position = e.Position
+			relayed++
+		}
+
+		if relayed == 0 {
+			return nil
+		}
+		return r.repo.SaveCursor(ctx, name, position)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return relayed, publishErr
+}
+
+func (r *outboxRelay) Prune(ctx context.Context) (int, error) {
+	return r.repo.DeleteRelayed(ctx, time.Now().Add(-r.retention))
+}
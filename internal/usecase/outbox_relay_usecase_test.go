@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeOutboxRepository keeps events in position order and a cursor per
+// publisher. Cursors in locked cannot be locked again.
+type fakeOutboxRepository struct {
+	events  []domain.OutboxEvent
+	cursors map[string]domain.OutboxPosition
+	locked  map[string]bool
+}
+
+func (r *fakeOutboxRepository) Create(ctx context.Context, event domain.ChangeEvent) error {
+	id := int64(len(r.events) + 1)
+	r.events = append(r.events, domain.OutboxEvent{Position: domain.OutboxPosition{TxID: id, ID: id}, Event: event})
+	return nil
+}
+
+func (r *fakeOutboxRepository) LockCursor(ctx context.Context, publisher string) (domain.OutboxPosition, bool, error) {
+	if r.locked[publisher] {
+		return domain.OutboxPosition{}, false, nil
+	}
+	return r.cursors[publisher], true, nil
+}
+
+func (r *fakeOutboxRepository) FetchAfter(ctx context.Context, position domain.OutboxPosition, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	for _, e := range r.events {
+		if e.Position.TxID > position.TxID && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// FetchStoreAfter ignores the store, the fake holds the events of one.
+func (r *fakeOutboxRepository) FetchStoreAfter(ctx context.Context, position domain.OutboxPosition, entityTypes []string, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	for _, e := range r.events {
+		if len(entityTypes) > 0 && !slices.Contains(entityTypes, e.Event.EntityType) {
+			continue
+		}
+		if e.Position.TxID > position.TxID && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeOutboxRepository) Head(ctx context.Context) (domain.OutboxPosition, error) {
+	if len(r.events) == 0 {
+		return domain.OutboxPosition{}, nil
+	}
+	return r.events[len(r.events)-1].Position, nil
+}
+
+func (r *fakeOutboxRepository) SaveCursor(ctx context.Context, publisher string, position domain.OutboxPosition) error {
+	r.cursors[publisher] = position
+	return nil
+}
+
+func (r *fakeOutboxRepository) FetchLag(ctx context.Context) ([]domain.OutboxLag, error) {
+	return nil, nil
+}
+
+func (r *fakeOutboxRepository) DeleteRelayed(ctx context.Context, cutoff time.Time) (int, error) {
+	return 0, nil
+}
+
+// recordingPublisher fails the events in failing and records the others.
+type recordingPublisher struct {
+	published []uuid.UUID
+	failing   map[uuid.UUID]bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.ChangeEvent) error {
+	if p.failing[event.ID] {
+		return errors.New("unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestOutboxRelayPublishesInOrderAtLeastOnce(t *testing.T) {
+	repo := &fakeOutboxRepository{cursors: map[string]domain.OutboxPosition{}, locked: map[string]bool{}}
+	outbox := NewOutboxUsecase(repo)
+
+	var ids []uuid.UUID
+	for range 3 {
+		id := uuid.New()
+		ids = append(ids, id)
+		if err := outbox.OnChange(context.Background(), domain.ChangeEvent{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	healthy := &recordingPublisher{}
+	flaky := &recordingPublisher{failing: map[uuid.UUID]bool{ids[1]: true}}
+	relay := NewOutboxRelay(repo, fakeTransactor{}, map[string]domain.EventPublisher{"healthy": healthy, "flaky": flaky}, time.Hour)
+
+	relayed, err := relay.Relay(context.Background())
+	if err == nil {
+		t.Error("Relay succeeded, want the flaky publisher's error")
+	}
+	if relayed != 4 {
+		t.Errorf("relayed %d events, want 4", relayed)
+	}
+	if len(healthy.published) != 3 || len(flaky.published) != 1 {
+		t.Fatalf("published %v and %v", healthy.published, flaky.published)
+	}
+	if repo.cursors["flaky"].ID != 1 {
+		t.Errorf("flaky cursor at %d, want 1", repo.cursors["flaky"].ID)
+	}
+
+	// The failed event is retried first and nothing after it is skipped
+	delete(flaky.failing, ids[1])
+	if _, err := relay.Relay(context.Background()); err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	for i, id := range ids {
+		if flaky.published[i] != id {
+			t.Errorf("flaky publisher got %v, want %v", flaky.published, ids)
+			break
+		}
+	}
+	if len(healthy.published) != 3 {
+		t.Errorf("healthy publisher got %d events, want 3", len(healthy.published))
+	}
+}
+
+func TestOutboxRelaySkipsLockedCursors(t *testing.T) {
+	repo := &fakeOutboxRepository{cursors: map[string]domain.OutboxPosition{}, locked: map[string]bool{"webhook": true}}
+	_ = repo.Create(context.Background(), domain.ChangeEvent{ID: uuid.New()})
+
+	publisher := &recordingPublisher{}
+	relay := NewOutboxRelay(repo, fakeTransactor{}, map[string]domain.EventPublisher{"webhook": publisher}, time.Hour)
+
+	relayed, err := relay.Relay(context.Background())
+	if err != nil || relayed != 0 || len(publisher.published) != 0 {
+		t.Errorf("Relay = %d, %v, published %v; want nothing while another instance relays", relayed, err, publisher.published)
+	}
+}
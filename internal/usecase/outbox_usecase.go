@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+)
+
+type OutboxUsecase interface {
+	domain.ChangeListener
+	GetLag(ctx context.Context) ([]domain.OutboxLag, error)
+}
+
+// outboxUsecase writes every change to the outbox, in the transaction of the
+// change, for OutboxRelay to publish once it is committed.
+type outboxUsecase struct {
+	repo domain.OutboxRepository
+}
+
+func NewOutboxUsecase(repo domain.OutboxRepository) OutboxUsecase {
+	return &outboxUsecase{repo: repo}
+}
+
+func (u *outboxUsecase) OnChange(ctx context.Context, event domain.ChangeEvent) error {
+	return u.repo.Create(ctx, event)
+}
+
+// GetLag reports every publisher that has relayed at least once.
+func (u *outboxUsecase) GetLag(ctx context.Context) ([]domain.OutboxLag, error) {
+	return u.repo.FetchLag(ctx)
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+)
+
+type ProductExportUsecase interface {
+	// Export hands every matching product of the store to emit, newest
+	// first, with its categories and images.
+	Export(ctx context.Context, filter domain.ProductExportFilter, emit func(*domain.Product) error) error
+}
+
+// productExportUsecase presents exported products like reads do, private
+// images get signed URLs that expire.
+type productExportUsecase struct {
+	repo   domain.ProductExportRepository
+	signer domain.URLSigner
+}
+
+func NewProductExportUsecase(repo domain.ProductExportRepository, signer domain.URLSigner) ProductExportUsecase {
+	return &productExportUsecase{repo: repo, signer: signer}
+}
+
+func (u *productExportUsecase) Export(ctx context.Context, filter domain.ProductExportFilter, emit func(*domain.Product) error) error {
+	return u.repo.Export(ctx, filter, func(p domain.Product) error {
+		if p.PrimaryImagePrivate {
+			p.PrimaryImageURL = u.signer.Sign(domain.ImageMediaPath(p.PrimaryImageID))
+		}
+		for i := range p.Images {
+			presentImage(u.signer, &p.Images[i])
+		}
+		return emit(&p)
+	})
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-listing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// feedSaveBatchSize is how many items a rebuild copies at once.
+	feedSaveBatchSize = 1000
+	// feedPageSize is how many items are read at once while serving a feed.
+	feedPageSize = 1000
+)
+
+type ProductFeedUsecase interface {
+	// Feed returns the feed of the request's store, building it first when
+	// it is missing, stale or was rendered with other settings.
+	Feed(ctx context.Context) (*domain.ProductFeed, error)
+	// Write hands the feed of the request's store in format to emit, a piece
+	// at a time. Call Feed first.
+	Write(ctx context.Context, format string, emit func(string) error) error
+	// Publish keeps feeds up to date with committed changes. It is the
+	// "feed" outbox publisher: changed products are rendered again, category
+	// changes, which touch many items, mark the feed for a rebuild.
+	Publish(ctx context.Context, event domain.ChangeEvent) error
+}
+
+type productFeedUsecase struct {
+	feeds    domain.ProductFeedRepository
+	export   domain.ProductExportRepository
+	products domain.ProductRepository
+	images   domain.ProductImageRepository
+	stores   domain.StoreRepository
+	tx       domain.Transactor
+	renderer domain.ProductFeedRenderer
+}
+
+// NewProductFeedUsecase serves feeds rendered by renderer. Without a renderer
+// there are no feeds.
+func NewProductFeedUsecase(
+	feeds domain.ProductFeedRepository,
+	export domain.ProductExportRepository,
+	products domain.ProductRepository,
+	images domain.ProductImageRepository,
+	stores domain.StoreRepository,
+	tx domain.Transactor,
+	renderer domain.ProductFeedRenderer,
+) ProductFeedUsecase {
+	return &productFeedUsecase{
+		feeds:    feeds,
+		export:   export,
+		products: products,
+		images:   images,
+		stores:   stores,
+		tx:       tx,
+		renderer: renderer,
+	}
+}
+
+func (u *productFeedUsecase) current(feed *domain.ProductFeed) bool {
+	return feed != nil && !feed.Stale && feed.Fingerprint == u.renderer.Fingerprint()
+}
+
+func (u *productFeedUsecase) Feed(ctx context.Context) (*domain.ProductFeed, error) {
+	if u.renderer == nil {
+		return nil, domain.NewNotFoundError("feed_not_configured", "product feeds are not configured")
+	}
+
+	feed, err := u.feeds.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.current(feed) {
+		return feed, nil
+	}
+
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		feed, err = u.rebuild(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// rebuild renders every product of the store again. The feed stays locked
+// until the transaction ends, so concurrent requests wait for one rebuild and
+// the publisher applies changes made meanwhile on top of it.
+func (u *productFeedUsecase) rebuild(ctx context.Context) (*domain.ProductFeed, error) {
+	if err := u.feeds.Create(ctx); err != nil {
+		return nil, err
+	}
+	feed, err := u.feeds.Lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.current(feed) {
+		return feed, nil
+	}
+
+	if err := u.feeds.DeleteItems(ctx); err != nil {
+		return nil, err
+	}
+
+	store := domain.StoreFromContext(ctx)
+	batch := make([]domain.ProductFeedItem, 0, feedSaveBatchSize)
+	err = u.export.Export(ctx, domain.ProductExportFilter{}, func(p domain.Product) error {
+		item, ok := u.renderer.Render(store, &p, primaryImage(&p))
+		if !ok {
+			return nil
+		}
+		batch = append(batch, item)
+		if len(batch) < feedSaveBatchSize {
+			return nil
+		}
+		err := u.feeds.SaveItems(ctx, batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) > 0 {
+		if err := u.feeds.SaveItems(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.feeds.FinishBuild(ctx, u.renderer.Fingerprint())
+}
+
+func primaryImage(p *domain.Product) *domain.ProductImage {
+	for i := range p.Images {
+		if p.Images[i].IsPrimary {
+			return &p.Images[i]
+		}
+	}
+	return nil
+}
+
+func (u *productFeedUsecase) Write(ctx context.Context, format string, emit func(string) error) error {
+	if u.renderer == nil {
+		return domain.NewNotFoundError("feed_not_configured", "product feeds are not configured")
+	}
+	if format != domain.FeedFormatXML && format != domain.FeedFormatTSV {
+		return domain.NewInvalidError("invalid_format", "format must be xml or tsv")
+	}
+
+	if err := emit(u.renderer.Header(domain.StoreFromContext(ctx), format)); err != nil {
+		return err
+	}
+
+	after := uuid.Nil
+	for {
+		items, err := u.feeds.FetchItems(ctx, after, feedPageSize)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			entry := item.XML
+			if format == domain.FeedFormatTSV {
+				entry = item.TSV
+			}
+			if err := emit(entry); err != nil {
+				return err
+			}
+		}
+		if len(items) < feedPageSize {
+			break
+		}
+		after = items[len(items)-1].ProductID
+	}
+
+	return emit(u.renderer.Footer(format))
+}
+
+func (u *productFeedUsecase) Publish(ctx context.Context, event domain.ChangeEvent) error {
+	var productID uuid.UUID
+	switch event.EntityType {
+	case domain.AuditEntityProduct:
+		id, err := uuid.Parse(event.EntityID)
+		if err != nil {
+			return fmt.Errorf("invalid product id in event %s: %w", event.ID, err)
+		}
+		productID = id
+	case domain.AuditEntityProductImage:
+		var image struct {
+			ProductID uuid.UUID `json:"product_id"`
+		}
+		if err := json.Unmarshal(event.Data, &image); err != nil {
+			return fmt.Errorf("failed to decode image of event %s: %w", event.ID, err)
+		}
+		productID = image.ProductID
+	case domain.AuditEntityCategory:
+		if event.Action == domain.AuditActionCreate {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	store, err := u.stores.FetchByID(ctx, event.StoreID)
+	if err != nil {
+		return err
+	}
+	ctx = domain.ContextWithStore(ctx, store)
+
+	// Joining the relay's transaction switches it to the event's store
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// A feed that will be rebuilt anyway is left alone
+		feed, err := u.feeds.Lock(ctx)
+		if err != nil || !u.current(feed) {
+			return err
+		}
+
+		if event.EntityType == domain.AuditEntityCategory {
+			return u.feeds.MarkStale(ctx)
+		}
+		if err := u.refresh(ctx, store, productID); err != nil {
+			return err
+		}
+		return u.feeds.Touch(ctx)
+	})
+}
+
+// refresh renders a product as it is now, which also covers events that have
+// been overtaken by later changes. Products that are gone or cannot be listed
+// are removed from the feed.
+func (u *productFeedUsecase) refresh(ctx context.Context, store *domain.Store, productID uuid.UUID) error {
+	p, err := u.products.FetchById(ctx, productID)
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) && domainErr.Kind == domain.ErrorKindNotFound {
+		return u.feeds.DeleteItem(ctx, productID)
+	}
+	if err != nil {
+		return err
+	}
+
+	primary, err := u.images.GetPrimary(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	item, ok := u.renderer.Render(store, p, primary)
+	if !ok {
+		return u.feeds.DeleteItem(ctx, productID)
+	}
+	return u.feeds.UpsertItem(ctx, item)
+}
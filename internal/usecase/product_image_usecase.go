@@ -2,7 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
 	"product-listing/internal/domain"
+	"product-listing/pkg/urlsign"
 
 	"github.com/google/uuid"
 )
@@ -10,46 +14,540 @@ import (
 type ProductImageUsecase interface {
 	AddImage(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error)
 	GetProductImages(ctx context.Context, productID string) ([]domain.ProductImage, error)
+	GetImagesByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.ProductImage, error)
 	DeleteImage(ctx context.Context, id string) error
+	DeleteProductImage(ctx context.Context, productID string, id string) error
 	SetPrimary(ctx context.Context, productID string, imageID string) error
+	SetVisibility(ctx context.Context, id string, visibility string) error
+	SetProductImageVisibility(ctx context.Context, productID string, id string, visibility string) error
+	ResolveMedia(ctx context.Context, id string, query url.Values) (*domain.ProductImage, error)
+	ApplyOperations(ctx context.Context, ops []domain.ImageOperation) ([]domain.ImageOperationResult, error)
 }
 
+// maxImageOperations caps the size of a single batch request.
+const maxImageOperations = 500
+
+// productImageUsecase keeps every product with at least one image pointing at
+// exactly one primary image. All changes to a product's gallery run in a
+// transaction holding a lock on the product row. Private images are only ever
+// handed out as signed media URLs. Every change is recorded in the audit log
+// within the same transaction.
 type productImageUsecase struct {
-	repo domain.ProductImageRepository
+	repo   domain.ProductImageRepository
+	tx     domain.Transactor
+	signer domain.URLSigner
+	audit  AuditUsecase
 }
 
-func NewProductImageUsecase(repo domain.ProductImageRepository) ProductImageUsecase {
-	return &productImageUsecase{repo: repo}
+func NewProductImageUsecase(repo domain.ProductImageRepository, tx domain.Transactor, signer domain.URLSigner, audit AuditUsecase) ProductImageUsecase {
+	return &productImageUsecase{repo: repo, tx: tx, signer: signer, audit: audit}
 }
 
 func (u *productImageUsecase) AddImage(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error) {
-	return u.repo.Create(ctx, input)
+	var img *domain.ProductImage
+	err := u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		img, err = u.addImage(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	presentImage(u.signer, img)
+	return img, nil
 }
 
 func (u *productImageUsecase) GetProductImages(ctx context.Context, productID string) ([]domain.ProductImage, error) {
-	uid, err := uuid.Parse(productID)
+	uid, err := parseID(productID, "invalid_product_id")
 	if err != nil {
 		return nil, err
 	}
-	return u.repo.GetByProductID(ctx, uid)
+
+	images, err := u.repo.GetByProductID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range images {
+		presentImage(u.signer, &images[i])
+	}
+	return images, nil
+}
+
+// GetImagesByProductIDs loads the galleries of many products in one query.
+func (u *productImageUsecase) GetImagesByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.ProductImage, error) {
+	if len(productIDs) == 0 {
+		return map[uuid.UUID][]domain.ProductImage{}, nil
+	}
+
+	images, err := u.repo.GetByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, list := range images {
+		for i := range list {
+			presentImage(u.signer, &list[i])
+		}
+	}
+	return images, nil
 }
 
 func (u *productImageUsecase) DeleteImage(ctx context.Context, id string) error {
-	uid, err := uuid.Parse(id)
+	uid, err := parseID(id, "invalid_image_id")
+	if err != nil {
+		return err
+	}
+
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return u.deleteImage(ctx, uid)
+	})
+}
+
+// DeleteProductImage is DeleteImage for an image addressed through its
+// product. An image of another product is reported as not found.
+func (u *productImageUsecase) DeleteProductImage(ctx context.Context, productID string, id string) error {
+	puid, err := parseID(productID, "invalid_product_id")
 	if err != nil {
 		return err
 	}
-	return u.repo.Delete(ctx, uid)
+	uid, err := parseID(id, "invalid_image_id")
+	if err != nil {
+		return err
+	}
+
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.checkImageProduct(ctx, puid, uid); err != nil {
+			return err
+		}
+		return u.deleteImage(ctx, uid)
+	})
 }
 
 func (u *productImageUsecase) SetPrimary(ctx context.Context, productID string, imageID string) error {
-	puid, err := uuid.Parse(productID)
+	puid, err := parseID(productID, "invalid_product_id")
+	if err != nil {
+		return err
+	}
+	iuid, err := parseID(imageID, "invalid_image_id")
+	if err != nil {
+		return err
+	}
+
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return u.setPrimary(ctx, puid, iuid)
+	})
+}
+
+func (u *productImageUsecase) SetVisibility(ctx context.Context, id string, visibility string) error {
+	uid, err := parseID(id, "invalid_image_id")
+	if err != nil {
+		return err
+	}
+
+	if !validVisibility(visibility) {
+		return domain.NewInvalidError("invalid_visibility", "visibility must be public or private")
+	}
+
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return u.setVisibility(ctx, uid, visibility)
+	})
+}
+
+// SetProductImageVisibility is SetVisibility for an image addressed through
+// its product. An image of another product is reported as not found.
+func (u *productImageUsecase) SetProductImageVisibility(ctx context.Context, productID string, id string, visibility string) error {
+	puid, err := parseID(productID, "invalid_product_id")
+	if err != nil {
+		return err
+	}
+	uid, err := parseID(id, "invalid_image_id")
+	if err != nil {
+		return err
+	}
+
+	if !validVisibility(visibility) {
+		return domain.NewInvalidError("invalid_visibility", "visibility must be public or private")
+	}
+
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.checkImageProduct(ctx, puid, uid); err != nil {
+			return err
+		}
+		return u.setVisibility(ctx, uid, visibility)
+	})
+}
+
+// ResolveMedia checks a signed media request and returns the image with its
+// origin URL.
+func (u *productImageUsecase) ResolveMedia(ctx context.Context, id string, query url.Values) (*domain.ProductImage, error) {
+	uid, err := parseID(id, "invalid_image_id")
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.signer.Verify(domain.ImageMediaPath(uid), query)
+	if errors.Is(err, urlsign.ErrExpired) {
+		return nil, domain.NewForbiddenError("media_url_expired", "media url has expired")
+	}
+	if err != nil {
+		return nil, domain.NewForbiddenError("media_signature_invalid", "invalid media url signature")
+	}
+
+	return u.repo.GetMediaByID(ctx, uid)
+}
+
+// ApplyOperations groups the operations by product and applies each group in
+// its own transaction, in request order. A failing operation aborts the rest
+// of its product's group but leaves other products untouched.
+func (u *productImageUsecase) ApplyOperations(ctx context.Context, ops []domain.ImageOperation) ([]domain.ImageOperationResult, error) {
+	if len(ops) == 0 {
+		return nil, domain.NewInvalidError("operations_required", "at least one operation is required")
+	}
+	if len(ops) > maxImageOperations {
+		return nil, domain.NewInvalidError("too_many_operations", fmt.Sprintf("at most %d operations are allowed", maxImageOperations))
+	}
+
+	results := make([]domain.ImageOperationResult, len(ops))
+	groups := make(map[uuid.UUID][]int)
+	var order []uuid.UUID
+	for i, op := range ops {
+		results[i] = domain.ImageOperationResult{Index: i, Op: op.Op}
+
+		productID, err := u.operationProductID(ctx, op)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if _, ok := groups[productID]; !ok {
+			order = append(order, productID)
+		}
+		groups[productID] = append(groups[productID], i)
+	}
+
+	for _, productID := range order {
+		indexes := groups[productID]
+		failed := -1
+		err := u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			for _, i := range indexes {
+				img, err := u.applyOperation(ctx, productID, ops[i])
+				if err != nil {
+					failed = i
+					return err
+				}
+				results[i].Image = img
+			}
+			return nil
+		})
+		if err == nil {
+			continue
+		}
+
+		for _, i := range indexes {
+			results[i].Image = nil
+			if i == failed || failed == -1 {
+				results[i].Err = err
+			} else {
+				results[i].Aborted = true
+			}
+		}
+	}
+
+	for i := range results {
+		if results[i].Image != nil {
+			presentImage(u.signer, results[i].Image)
+		}
+	}
+
+	return results, nil
+}
+
+// operationProductID resolves the product an operation touches. Deletes only
+// carry the image ID, so the image is looked up.
+func (u *productImageUsecase) operationProductID(ctx context.Context, op domain.ImageOperation) (uuid.UUID, error) {
+	switch op.Op {
+	case domain.ImageOpAdd, domain.ImageOpSetPrimary, domain.ImageOpReorder:
+		return parseID(op.ProductID, "invalid_product_id")
+	case domain.ImageOpDelete:
+		imageID, err := parseID(op.ImageID, "invalid_image_id")
+		if err != nil {
+			return uuid.Nil, err
+		}
+		img, err := u.repo.GetByID(ctx, imageID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return img.ProductID, nil
+	default:
+		return uuid.Nil, domain.NewInvalidError("unknown_operation", "unknown operation: "+op.Op)
+	}
+}
+
+func (u *productImageUsecase) applyOperation(ctx context.Context, productID uuid.UUID, op domain.ImageOperation) (*domain.ProductImage, error) {
+	switch op.Op {
+	case domain.ImageOpAdd:
+		return u.addImage(ctx, domain.ProductImageInput{
+			ProductID:  productID,
+			Url:        op.Url,
+			IsPrimary:  op.IsPrimary,
+			Visibility: op.Visibility,
+		})
+	case domain.ImageOpDelete:
+		imageID, err := parseID(op.ImageID, "invalid_image_id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, u.deleteImage(ctx, imageID)
+	case domain.ImageOpSetPrimary:
+		imageID, err := parseID(op.ImageID, "invalid_image_id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, u.setPrimary(ctx, productID, imageID)
+	case domain.ImageOpReorder:
+		imageIDs := make([]uuid.UUID, 0, len(op.ImageIDs))
+		for _, id := range op.ImageIDs {
+			imageID, err := parseID(id, "invalid_image_id")
+			if err != nil {
+				return nil, err
+			}
+			imageIDs = append(imageIDs, imageID)
+		}
+		return nil, u.reorderImages(ctx, productID, imageIDs)
+	default:
+		return nil, domain.NewInvalidError("unknown_operation", "unknown operation: "+op.Op)
+	}
+}
+
+// addImage demotes the current primary when the new image claims the spot,
+// and promotes the new image when the product has no primary yet.
+func (u *productImageUsecase) addImage(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error) {
+	if input.Url == "" {
+		return nil, domain.NewInvalidError("image_url_required", "image url cannot be empty")
+	}
+
+	if input.Visibility == "" {
+		input.Visibility = domain.ImageVisibilityPublic
+	}
+	if !validVisibility(input.Visibility) {
+		return nil, domain.NewInvalidError("invalid_visibility", "visibility must be public or private")
+	}
+
+	if err := u.repo.LockProduct(ctx, input.ProductID); err != nil {
+		return nil, err
+	}
+
+	primary, err := u.repo.GetPrimary(ctx, input.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if primary == nil {
+		input.IsPrimary = true
+	} else if input.IsPrimary {
+		if err := u.repo.ClearPrimary(ctx, input.ProductID); err != nil {
+			return nil, err
+		}
+
+		demoted := *primary
+		demoted.IsPrimary = false
+		if err := u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, primary.ID.String(), primary, demoted); err != nil {
+			return nil, err
+		}
+	}
+
+	img, err := u.repo.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.audit.Record(ctx, domain.AuditActionCreate, domain.AuditEntityProductImage, img.ID.String(), nil, img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// deleteImage promotes the next image in gallery order when the primary is
+// removed. The image is read again once the product is locked, a concurrent
+// setPrimary may have made it the primary in between.
+func (u *productImageUsecase) deleteImage(ctx context.Context, id uuid.UUID) error {
+	unlocked, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.LockProduct(ctx, unlocked.ProductID); err != nil {
+		return err
+	}
+
+	img, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.audit.Record(ctx, domain.AuditActionDelete, domain.AuditEntityProductImage, id.String(), img, nil); err != nil {
+		return err
+	}
+
+	if !img.IsPrimary {
+		return nil
+	}
+
+	remaining, err := u.repo.GetByProductID(ctx, img.ProductID)
 	if err != nil {
 		return err
 	}
-	iuid, err := uuid.Parse(imageID)
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	next := remaining[0]
+	if err := u.repo.SetPrimary(ctx, img.ProductID, next.ID); err != nil {
+		return err
+	}
+
+	promoted := next
+	promoted.IsPrimary = true
+	return u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, next.ID.String(), next, promoted)
+}
+
+func (u *productImageUsecase) setVisibility(ctx context.Context, id uuid.UUID, visibility string) error {
+	before, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.UpdateVisibility(ctx, id, visibility); err != nil {
+		return err
+	}
+
+	after := *before
+	after.Visibility = visibility
+	return u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, id.String(), before, after)
+}
+
+// checkImageProduct fails as not found unless the image belongs to the
+// product, so nested routes cannot reach images of other products.
+func (u *productImageUsecase) checkImageProduct(ctx context.Context, productID, imageID uuid.UUID) error {
+	img, err := u.repo.GetByID(ctx, imageID)
 	if err != nil {
 		return err
 	}
-	return u.repo.SetPrimary(ctx, puid, iuid)
+	if img.ProductID != productID {
+		return domain.NewNotFoundError("image_not_found", "image not found")
+	}
+	return nil
+}
+
+func (u *productImageUsecase) setPrimary(ctx context.Context, productID, imageID uuid.UUID) error {
+	if err := u.repo.LockProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	img, err := u.repo.GetByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+
+	if img.ProductID != productID {
+		return domain.NewConflictError("image_product_mismatch", "image does not belong to product")
+	}
+
+	if img.IsPrimary {
+		return nil
+	}
+
+	current, err := u.repo.GetPrimary(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.SetPrimary(ctx, productID, imageID); err != nil {
+		return err
+	}
+
+	if current != nil {
+		demoted := *current
+		demoted.IsPrimary = false
+		if err := u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, current.ID.String(), current, demoted); err != nil {
+			return err
+		}
+	}
+
+	after := *img
+	after.IsPrimary = true
+	return u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, imageID.String(), img, after)
+}
+
+// reorderImages sets the gallery order. imageIDs must list every image of the
+// product exactly once.
+func (u *productImageUsecase) reorderImages(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	if err := u.repo.LockProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	images, err := u.repo.GetByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[uuid.UUID]bool, len(images))
+	byID := make(map[uuid.UUID]domain.ProductImage, len(images))
+	for _, img := range images {
+		pending[img.ID] = true
+		byID[img.ID] = img
+	}
+	for _, id := range imageIDs {
+		if !pending[id] {
+			return domain.NewInvalidError("reorder_mismatch", "image_ids must list every image of the product exactly once")
+		}
+		delete(pending, id)
+	}
+	if len(pending) > 0 {
+		return domain.NewInvalidError("reorder_mismatch", "image_ids must list every image of the product exactly once")
+	}
+
+	for position, id := range imageIDs {
+		before := byID[id]
+		if before.Position == position {
+			continue
+		}
+
+		if err := u.repo.UpdatePosition(ctx, id, position); err != nil {
+			return err
+		}
+
+		after := before
+		after.Position = position
+		if err := u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProductImage, id.String(), before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseID(id, code string) (uuid.UUID, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewInvalidError(code, "invalid id: "+id)
+	}
+	return uid, nil
+}
+
+func validVisibility(visibility string) bool {
+	return visibility == domain.ImageVisibilityPublic || visibility == domain.ImageVisibilityPrivate
+}
+
+// presentImage swaps the origin URL of a private image for a signed media URL.
+func presentImage(signer domain.URLSigner, img *domain.ProductImage) {
+	if img.Visibility == domain.ImageVisibilityPrivate {
+		img.Url = signer.Sign(domain.ImageMediaPath(img.ID))
+	}
 }
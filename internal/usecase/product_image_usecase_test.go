@@ -0,0 +1,483 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+	"product-listing/internal/domain"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(path string) string { return path + "?sig=test" }
+
+func (fakeSigner) Verify(path string, query url.Values) error { return nil }
+
+// fakeImageRepository keeps images in memory. onLock runs when a product is
+// locked, standing in for a transaction that committed just before.
+type fakeImageRepository struct {
+	images map[uuid.UUID]domain.ProductImage
+	onLock func()
+}
+
+func newFakeImageRepository(images ...domain.ProductImage) *fakeImageRepository {
+	r := &fakeImageRepository{images: make(map[uuid.UUID]domain.ProductImage)}
+	for _, img := range images {
+		r.images[img.ID] = img
+	}
+	return r
+}
+
+func (r *fakeImageRepository) Create(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error) {
+	img := domain.ProductImage{
+		ID:         uuid.New(),
+		ProductID:  input.ProductID,
+		Url:        input.Url,
+		IsPrimary:  input.IsPrimary,
+		Visibility: input.Visibility,
+		Position:   len(r.images),
+	}
+	r.images[img.ID] = img
+	return &img, nil
+}
+
+func (r *fakeImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ProductImage, error) {
+	img, ok := r.images[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("image_not_found", "image not found")
+	}
+	return &img, nil
+}
+
+func (r *fakeImageRepository) GetMediaByID(ctx context.Context, id uuid.UUID) (*domain.ProductImage, error) {
+	return r.GetByID(ctx, id)
+}
+
+// GetByProductID lists the primary image first, then the gallery order.
+func (r *fakeImageRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]domain.ProductImage, error) {
+	var images []domain.ProductImage
+	for _, img := range r.images {
+		if img.ProductID == productID {
+			images = append(images, img)
+		}
+	}
+	slices.SortFunc(images, func(a, b domain.ProductImage) int {
+		if a.IsPrimary != b.IsPrimary {
+			if a.IsPrimary {
+				return -1
+			}
+			return 1
+		}
+		return a.Position - b.Position
+	})
+	return images, nil
+}
+
+func (r *fakeImageRepository) GetByProductIDs(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.ProductImage, error) {
+	result := make(map[uuid.UUID][]domain.ProductImage)
+	for _, id := range productIDs {
+		result[id], _ = r.GetByProductID(ctx, id)
+	}
+	return result, nil
+}
+
+func (r *fakeImageRepository) GetPrimary(ctx context.Context, productID uuid.UUID) (*domain.ProductImage, error) {
+	for _, img := range r.images {
+		if img.ProductID == productID && img.IsPrimary {
+			return &img, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeImageRepository) GetReferencedURLs(ctx context.Context, urls []string) (map[string]bool, error) {
+	return nil, nil
+}
+
+func (r *fakeImageRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	if r.onLock != nil {
+		r.onLock()
+		r.onLock = nil
+	}
+	return nil
+}
+
+func (r *fakeImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.images, id)
+	return nil
+}
+
+func (r *fakeImageRepository) UpdateVisibility(ctx context.Context, id uuid.UUID, visibility string) error {
+	img := r.images[id]
+	img.Visibility = visibility
+	r.images[id] = img
+	return nil
+}
+
+func (r *fakeImageRepository) UpdatePosition(ctx context.Context, id uuid.UUID, position int) error {
+	img := r.images[id]
+	img.Position = position
+	r.images[id] = img
+	return nil
+}
+
+func (r *fakeImageRepository) ClearPrimary(ctx context.Context, productID uuid.UUID) error {
+	for id, img := range r.images {
+		if img.ProductID == productID {
+			img.IsPrimary = false
+			r.images[id] = img
+		}
+	}
+	return nil
+}
+
+func (r *fakeImageRepository) SetPrimary(ctx context.Context, productID uuid.UUID, imageID uuid.UUID) error {
+	for id, img := range r.images {
+		if img.ProductID == productID {
+			img.IsPrimary = id == imageID
+			r.images[id] = img
+		}
+	}
+	return nil
+}
+
+// primaries lists the primary images of a product.
+func (r *fakeImageRepository) primaries(productID uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for id, img := range r.images {
+		if img.ProductID == productID && img.IsPrimary {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func newTestImageUsecase(repo domain.ProductImageRepository) ProductImageUsecase {
+	return NewProductImageUsecase(repo, fakeTransactor{}, fakeSigner{}, NewAuditUsecase(&fakeAuditRepository{}))
+}
+
+// storeContext binds a store, which the audit log records changes under.
+func storeContext() context.Context {
+	return domain.ContextWithStore(context.Background(), &domain.Store{ID: uuid.New(), Slug: "main"})
+}
+
+func testImage(productID uuid.UUID, position int, primary bool) domain.ProductImage {
+	return domain.ProductImage{
+		ID:         uuid.New(),
+		ProductID:  productID,
+		Url:        "https://cdn.example.com/image.jpg",
+		IsPrimary:  primary,
+		Visibility: domain.ImageVisibilityPublic,
+		Position:   position,
+	}
+}
+
+func TestDeleteImagePromotesNextImage(t *testing.T) {
+	productID := uuid.New()
+	primary, second, third := testImage(productID, 0, true), testImage(productID, 1, false), testImage(productID, 2, false)
+	repo := newFakeImageRepository(primary, second, third)
+	u := newTestImageUsecase(repo)
+
+	if err := u.DeleteImage(storeContext(), primary.ID.String()); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != second.ID {
+		t.Errorf("primaries = %v, want the next image %s", got, second.ID)
+	}
+}
+
+func TestDeleteImageRereadsAfterLock(t *testing.T) {
+	productID := uuid.New()
+	first, second := testImage(productID, 0, true), testImage(productID, 1, false)
+	repo := newFakeImageRepository(first, second)
+	// second becomes the primary while the delete waits for the lock
+	repo.onLock = func() { _ = repo.SetPrimary(storeContext(), productID, second.ID) }
+	u := newTestImageUsecase(repo)
+
+	if err := u.DeleteImage(storeContext(), second.ID.String()); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != first.ID {
+		t.Errorf("primaries = %v, want %s promoted again", got, first.ID)
+	}
+}
+
+func TestAddImageDemotesPrimary(t *testing.T) {
+	productID := uuid.New()
+	old := testImage(productID, 0, true)
+	repo := newFakeImageRepository(old)
+	u := newTestImageUsecase(repo)
+
+	added, err := u.AddImage(storeContext(), domain.ProductImageInput{ProductID: productID, Url: "https://cdn.example.com/new.jpg", IsPrimary: true})
+	if err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != added.ID {
+		t.Errorf("primaries = %v, want only the new image %s", got, added.ID)
+	}
+
+	other := uuid.New()
+	first, err := u.AddImage(storeContext(), domain.ProductImageInput{ProductID: other, Url: "https://cdn.example.com/first.jpg"})
+	if err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+	if !first.IsPrimary {
+		t.Error("first image of a product was not made primary")
+	}
+}
+
+func TestImageOfAnotherProductIsRejected(t *testing.T) {
+	productID, otherID := uuid.New(), uuid.New()
+	own, foreign := testImage(productID, 0, true), testImage(otherID, 0, true)
+	repo := newFakeImageRepository(own, foreign)
+	u := newTestImageUsecase(repo)
+
+	var domainErr *domain.Error
+	err := u.SetPrimary(storeContext(), productID.String(), foreign.ID.String())
+	if !errors.As(err, &domainErr) || domainErr.Code != "image_product_mismatch" {
+		t.Errorf("SetPrimary with a foreign image = %v, want image_product_mismatch", err)
+	}
+
+	err = u.DeleteProductImage(storeContext(), productID.String(), foreign.ID.String())
+	if !errors.As(err, &domainErr) || domainErr.Kind != domain.ErrorKindNotFound {
+		t.Errorf("DeleteProductImage with a foreign image = %v, want not found", err)
+	}
+	if _, ok := repo.images[foreign.ID]; !ok {
+		t.Error("image of another product was deleted")
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != own.ID {
+		t.Errorf("primaries = %v, want %s untouched", got, own.ID)
+	}
+}
+
+func TestSetPrimaryMovesPrimary(t *testing.T) {
+	productID := uuid.New()
+	old, next := testImage(productID, 0, true), testImage(productID, 1, false)
+	repo := newFakeImageRepository(old, next)
+	u := newTestImageUsecase(repo)
+
+	if err := u.SetPrimary(storeContext(), productID.String(), next.ID.String()); err != nil {
+		t.Fatalf("SetPrimary: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != next.ID {
+		t.Errorf("primaries = %v, want only %s", got, next.ID)
+	}
+	// Setting the current primary again changes nothing
+	if err := u.SetPrimary(storeContext(), productID.String(), next.ID.String()); err != nil {
+		t.Fatalf("SetPrimary on the primary: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != next.ID {
+		t.Errorf("primaries = %v, want only %s", got, next.ID)
+	}
+}
+
+func TestDeleteImageKeepsOrLeavesPrimary(t *testing.T) {
+	productID := uuid.New()
+	primary, other := testImage(productID, 0, true), testImage(productID, 1, false)
+	repo := newFakeImageRepository(primary, other)
+	u := newTestImageUsecase(repo)
+
+	if err := u.DeleteImage(storeContext(), other.ID.String()); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if got := repo.primaries(productID); len(got) != 1 || got[0] != primary.ID {
+		t.Errorf("primaries = %v, want %s untouched", got, primary.ID)
+	}
+
+	// Without images left there is nothing to promote
+	if err := u.DeleteImage(storeContext(), primary.ID.String()); err != nil {
+		t.Fatalf("DeleteImage of the last image: %v", err)
+	}
+	if len(repo.images) != 0 {
+		t.Errorf("images = %v, want none", repo.images)
+	}
+}
+
+func TestImageErrorCodes(t *testing.T) {
+	productID := uuid.New()
+	img := testImage(productID, 0, true)
+	u := newTestImageUsecase(newFakeImageRepository(img))
+	ctx := storeContext()
+
+	tests := []struct {
+		name string
+		call func() error
+		kind domain.ErrorKind
+		code string
+	}{
+		{"add without url", func() error {
+			_, err := u.AddImage(ctx, domain.ProductImageInput{ProductID: productID})
+			return err
+		}, domain.ErrorKindInvalid, "image_url_required"},
+		{"add with unknown visibility", func() error {
+			_, err := u.AddImage(ctx, domain.ProductImageInput{ProductID: productID, Url: "https://cdn.example.com/a.jpg", Visibility: "hidden"})
+			return err
+		}, domain.ErrorKindInvalid, "invalid_visibility"},
+		{"set primary with invalid product", func() error {
+			return u.SetPrimary(ctx, "nope", img.ID.String())
+		}, domain.ErrorKindInvalid, "invalid_product_id"},
+		{"set primary with invalid image", func() error {
+			return u.SetPrimary(ctx, productID.String(), "nope")
+		}, domain.ErrorKindInvalid, "invalid_image_id"},
+		{"set primary with unknown image", func() error {
+			return u.SetPrimary(ctx, productID.String(), uuid.NewString())
+		}, domain.ErrorKindNotFound, "image_not_found"},
+		{"delete unknown image", func() error {
+			return u.DeleteImage(ctx, uuid.NewString())
+		}, domain.ErrorKindNotFound, "image_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var domainErr *domain.Error
+			if err := tt.call(); !errors.As(err, &domainErr) || domainErr.Kind != tt.kind || domainErr.Code != tt.code {
+				t.Errorf("error = %v, want kind %d %s", err, tt.kind, tt.code)
+			}
+		})
+	}
+}
+
+// trackingTransactor reports whether a transaction is open.
+type trackingTransactor struct {
+	open bool
+}
+
+func (t *trackingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.open = true
+	defer func() { t.open = false }()
+	return fn(ctx)
+}
+
+// txCheckedImageRepository counts the writes made outside a transaction.
+type txCheckedImageRepository struct {
+	*fakeImageRepository
+	tx      *trackingTransactor
+	outside int
+}
+
+func (r *txCheckedImageRepository) write() {
+	if !r.tx.open {
+		r.outside++
+	}
+}
+
+func (r *txCheckedImageRepository) Create(ctx context.Context, input domain.ProductImageInput) (*domain.ProductImage, error) {
+	r.write()
+	return r.fakeImageRepository.Create(ctx, input)
+}
+
+func (r *txCheckedImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.write()
+	return r.fakeImageRepository.Delete(ctx, id)
+}
+
+func (r *txCheckedImageRepository) ClearPrimary(ctx context.Context, productID uuid.UUID) error {
+	r.write()
+	return r.fakeImageRepository.ClearPrimary(ctx, productID)
+}
+
+func (r *txCheckedImageRepository) SetPrimary(ctx context.Context, productID uuid.UUID, imageID uuid.UUID) error {
+	r.write()
+	return r.fakeImageRepository.SetPrimary(ctx, productID, imageID)
+}
+
+func TestPrimaryChangesRunInTransaction(t *testing.T) {
+	productID := uuid.New()
+	first, second := testImage(productID, 0, true), testImage(productID, 1, false)
+	tx := &trackingTransactor{}
+	repo := &txCheckedImageRepository{fakeImageRepository: newFakeImageRepository(first, second), tx: tx}
+	u := NewProductImageUsecase(repo, tx, fakeSigner{}, NewAuditUsecase(&fakeAuditRepository{}))
+	ctx := storeContext()
+
+	if _, err := u.AddImage(ctx, domain.ProductImageInput{ProductID: productID, Url: "https://cdn.example.com/new.jpg", IsPrimary: true}); err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+	if err := u.SetPrimary(ctx, productID.String(), second.ID.String()); err != nil {
+		t.Fatalf("SetPrimary: %v", err)
+	}
+	if err := u.DeleteImage(ctx, second.ID.String()); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+
+	if repo.outside != 0 {
+		t.Errorf("%d writes ran outside a transaction", repo.outside)
+	}
+	if got := repo.primaries(productID); len(got) != 1 {
+		t.Errorf("primaries = %v, want exactly one", got)
+	}
+}
+
+// rollbackTransactor restores the images of repo when a transaction fails.
+type rollbackTransactor struct {
+	repo *fakeImageRepository
+}
+
+func (t rollbackTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := maps.Clone(t.repo.images)
+	if err := fn(ctx); err != nil {
+		t.repo.images = saved
+		return err
+	}
+	return nil
+}
+
+func TestApplyOperationsFailsPerProduct(t *testing.T) {
+	failing, healthy := uuid.New(), uuid.New()
+	primary, other := testImage(failing, 0, true), testImage(healthy, 0, true)
+	repo := newFakeImageRepository(primary, other)
+	u := NewProductImageUsecase(repo, rollbackTransactor{repo: repo}, fakeSigner{}, NewAuditUsecase(&fakeAuditRepository{}))
+
+	results, err := u.ApplyOperations(storeContext(), []domain.ImageOperation{
+		{Op: domain.ImageOpAdd, ProductID: failing.String(), Url: "https://cdn.example.com/added.jpg", IsPrimary: true},
+		{Op: domain.ImageOpAdd, ProductID: healthy.String(), Url: "https://cdn.example.com/kept.jpg"},
+		{Op: domain.ImageOpSetPrimary, ProductID: failing.String(), ImageID: other.ID.String()},
+		{Op: domain.ImageOpDelete, ImageID: primary.ID.String()},
+		{Op: "rename", ProductID: healthy.String()},
+	})
+	if err != nil {
+		t.Fatalf("ApplyOperations: %v", err)
+	}
+
+	var domainErr *domain.Error
+	if results[0].Err != nil || !results[0].Aborted || results[0].Image != nil {
+		t.Errorf("result 0 = %+v, want aborted without an image", results[0])
+	}
+	if results[1].Err != nil || results[1].Aborted || results[1].Image == nil {
+		t.Errorf("result 1 = %+v, want the added image", results[1])
+	}
+	if !errors.As(results[2].Err, &domainErr) || domainErr.Code != "image_product_mismatch" {
+		t.Errorf("result 2 = %+v, want image_product_mismatch", results[2])
+	}
+	if results[3].Err != nil || !results[3].Aborted {
+		t.Errorf("result 3 = %+v, want aborted", results[3])
+	}
+	if !errors.As(results[4].Err, &domainErr) || domainErr.Code != "unknown_operation" {
+		t.Errorf("result 4 = %+v, want unknown_operation", results[4])
+	}
+
+	// The failing product is as before, the other one got its image
+	if images, _ := repo.GetByProductID(storeContext(), failing); len(images) != 1 || images[0].ID != primary.ID || !images[0].IsPrimary {
+		t.Errorf("images of the failing product = %+v, want them untouched", images)
+	}
+	if images, _ := repo.GetByProductID(storeContext(), healthy); len(images) != 2 {
+		t.Errorf("images of the other product = %+v, want the added image kept", images)
+	}
+}
+
+func TestApplyOperationsLimits(t *testing.T) {
+	u := newTestImageUsecase(newFakeImageRepository())
+
+	for name, ops := range map[string][]domain.ImageOperation{
+		"operations_required": nil,
+		"too_many_operations": make([]domain.ImageOperation, maxImageOperations+1),
+	} {
+		var domainErr *domain.Error
+		if _, err := u.ApplyOperations(storeContext(), ops); !errors.As(err, &domainErr) || domainErr.Code != name {
+			t.Errorf("ApplyOperations with %d operations = %v, want %s", len(ops), err, name)
+		}
+	}
+}
@@ -0,0 +1,419 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"product-listing/internal/domain"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// importStageBatchSize is how many rows of an upload are copied at once
+	importStageBatchSize = 1000
+	// importApplyBatchSize is how many rows a runner applies per transaction
+	importApplyBatchSize = 500
+	// importLease hides a running job from other runners. Every applied
+	// batch extends it, so it only runs out when the runner holding it died.
+	importLease = 5 * time.Minute
+
+	importJobPageSize           = 50
+	defaultImportErrorsPageSize = 100
+	maxImportErrorsPageSize     = 1000
+
+	// maxImportPrice is one more than the largest price a NUMERIC(12,2) holds
+	maxImportPrice = 1e10
+)
+
+// errDryRun rolls back the batch of a dry run once it has been applied.
+var errDryRun = errors.New("dry run")
+
+type ProductImportUsecase interface {
+	// Import validates the rows and queues a job to apply them. Rows that are
+	// invalid on their own are rejected right away; the rest are checked
+	// against the catalog when the job runs.
+	Import(ctx context.Context, rows domain.ImportRowReader, format string, dryRun bool) (*domain.ImportJob, error)
+	GetJobs(ctx context.Context) ([]domain.ImportJob, error)
+	GetJobByID(ctx context.Context, id string) (*domain.ImportJob, error)
+	// GetRejectedRows returns one page of the rows a job rejected, in order of
+	// line, and the cursor of the next page, which is empty on the last page.
+	GetRejectedRows(ctx context.Context, id, cursor string, limit int) ([]domain.ImportRow, string, error)
+}
+
+type productImportUsecase struct {
+	repo domain.ImportRepository
+	tx   domain.Transactor
+}
+
+func NewProductImportUsecase(repo domain.ImportRepository, tx domain.Transactor) ProductImportUsecase {
+	return &productImportUsecase{repo: repo, tx: tx}
+}
+
+// Import stages the whole file in one transaction, so a job is never queued
+// with only part of its rows. The job remembers who uploaded it and the
+// request it came from, and its changes are audited in their name.
+func (u *productImportUsecase) Import(ctx context.Context, rows domain.ImportRowReader, format string, dryRun bool) (*domain.ImportJob, error) {
+	job := domain.ImportJob{
+		Format:      format,
+		DryRun:      dryRun,
+		Actor:       "anonymous",
+		ActorMethod: "none",
+	}
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		job.Actor = principal.Subject
+		job.ActorMethod = principal.Method
+	}
+	meta := domain.RequestMetaFromContext(ctx)
+	job.RequestID = meta.ID
+	job.IP = meta.IP
+
+	var result *domain.ImportJob
+	err := u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := u.repo.CreateJob(ctx, job)
+		if err != nil {
+			return err
+		}
+
+		total, rejected := 0, 0
+		seen := make(map[string]int)
+		batch := make([]domain.ImportRow, 0, importStageBatchSize)
+		for {
+			row, err := rows.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			validateImportRow(&row, seen)
+			if row.Error != "" {
+				rejected++
+			}
+			total++
+
+			batch = append(batch, row)
+			if len(batch) == importStageBatchSize {
+				if err := u.repo.StageRows(ctx, created.ID, batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+
+		if total == 0 {
+			return domain.NewInvalidError("empty_import", "the file has no rows")
+		}
+		if len(batch) > 0 {
+			if err := u.repo.StageRows(ctx, created.ID, batch); err != nil {
+				return err
+			}
+		}
+
+		result, err = u.repo.SetJobTotals(ctx, created.ID, total, rejected)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (u *productImportUsecase) GetJobs(ctx context.Context) ([]domain.ImportJob, error) {
+	return u.repo.FetchJobs(ctx, importJobPageSize)
+}
+
+func (u *productImportUsecase) GetJobByID(ctx context.Context, id string) (*domain.ImportJob, error) {
+	jobID, err := parseImportJobID(id)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FetchJobByID(ctx, jobID)
+}
+
+func (u *productImportUsecase) GetRejectedRows(ctx context.Context, id, cursor string, limit int) ([]domain.ImportRow, string, error) {
+	jobID, err := parseImportJobID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	// The rows are only reachable through a job of the request's store
+	if _, err := u.repo.FetchJobByID(ctx, jobID); err != nil {
+		return nil, "", err
+	}
+
+	if limit <= 0 {
+		limit = defaultImportErrorsPageSize
+	}
+	if limit > maxImportErrorsPageSize {
+		limit = maxImportErrorsPageSize
+	}
+
+	afterLine := 0
+	if cursor != "" {
+		afterLine, err = strconv.Atoi(cursor)
+		if err != nil || afterLine <= 0 {
+			return nil, "", domain.NewInvalidError("invalid_cursor", "invalid cursor: "+cursor)
+		}
+	}
+
+	rows, err := u.repo.FetchRejectedRows(ctx, jobID, afterLine, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(rows) == limit {
+		next = strconv.Itoa(rows[len(rows)-1].Line)
+	}
+	return rows, next, nil
+}
+
+func parseImportJobID(id string) (uuid.UUID, error) {
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewInvalidError("invalid_id", "invalid import job id: "+id)
+	}
+	return jobID, nil
+}
+
+// validateImportRow sets the Error of a row that cannot be imported whatever
+// the catalog holds. seen maps the slugs of earlier rows to their line, a
+// slug may only appear once per file. Repeated category slugs and image URLs
+// are dropped.
+func validateImportRow(row *domain.ImportRow, seen map[string]int) {
+	if row.Error != "" {
+		return
+	}
+
+	switch {
+	case row.Name == "":
+		row.Error = "name is required"
+	case row.Slug == "":
+		row.Error = "slug is required"
+	case math.IsNaN(row.Price) || row.Price < 0 || row.Price >= maxImportPrice:
+		row.Error = fmt.Sprintf("price must be between 0 and %.2f", maxImportPrice-0.01)
+	}
+	if row.Error != "" {
+		return
+	}
+
+	if line, ok := seen[row.Slug]; ok {
+		row.Error = fmt.Sprintf("slug already used on line %d", line)
+		return
+	}
+	seen[row.Slug] = row.Line
+
+	for _, raw := range row.ImageURLs {
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			row.Error = "invalid image URL: " + raw
+			return
+		}
+	}
+
+	row.CategorySlugs = uniqueStrings(row.CategorySlugs)
+	row.ImageURLs = uniqueStrings(row.ImageURLs)
+}
+
+// uniqueStrings drops repeated items, keeping the first of each in order.
+func uniqueStrings(items []string) []string {
+	result := items[:0]
+	for i, item := range items {
+		if !slices.Contains(items[:i], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+type ProductImportRunner interface {
+	// Run applies one queued job to the end and reports whether there was
+	// one.
+	Run(ctx context.Context) (bool, error)
+	// Prune deletes jobs that finished longer than the retention ago.
+	Prune(ctx context.Context) (int, error)
+}
+
+// productImportRunner applies the jobs of every store in batches. Each batch
+// is applied, audited and recorded as progress in one transaction, so a job
+// whose runner died resumes after the last batch that committed. A dry run
+// rolls every batch back and only keeps its progress.
+type productImportRunner struct {
+	repo      domain.ImportRepository
+	stores    domain.StoreRepository
+	tx        domain.Transactor
+	audit     AuditUsecase
+	retention time.Duration
+}
+
+func NewProductImportRunner(repo domain.ImportRepository, stores domain.StoreRepository, tx domain.Transactor, audit AuditUsecase, retention time.Duration) ProductImportRunner {
+	return &productImportRunner{repo: repo, stores: stores, tx: tx, audit: audit, retention: retention}
+}
+
+// Run leaves a job it lost to another runner alone, and one it was cancelled
+// on for the next runner once the lease runs out. Any other error fails the
+// job.
+func (r *productImportRunner) Run(ctx context.Context) (bool, error) {
+	job, err := r.repo.ClaimJob(ctx, importLease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	store, err := r.stores.FetchByID(ctx, job.StoreID)
+	if err != nil {
+		return true, err
+	}
+
+	// Act as the uploader in the job's store, as if the rows came with the
+	// upload request
+	ctx = domain.ContextWithStore(ctx, store)
+	ctx = domain.ContextWithPrincipal(ctx, &domain.Principal{Subject: job.Actor, Method: job.ActorMethod, Store: store.ID.String()})
+	ctx = domain.ContextWithRequestMeta(ctx, domain.RequestMeta{ID: job.RequestID, IP: job.IP})
+
+	err = r.run(ctx, job)
+	switch {
+	case errors.Is(err, domain.ErrImportJobTakenOver), ctx.Err() != nil:
+		return true, err
+	case err != nil:
+		if finishErr := r.repo.FinishJob(ctx, job.ID, domain.ImportJobFailed, err.Error()); finishErr != nil {
+			return true, errors.Join(err, finishErr)
+		}
+		return true, err
+	}
+	return true, r.repo.FinishJob(ctx, job.ID, domain.ImportJobSucceeded, "")
+}
+
+func (r *productImportRunner) run(ctx context.Context, job *domain.ImportJob) error {
+	lastLine := job.LastLine
+	for {
+		rows, err := r.repo.FetchPendingRows(ctx, job.ID, lastLine, importApplyBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		if err := r.applyBatch(ctx, job, lastLine, rows); err != nil {
+			return err
+		}
+		lastLine = rows[len(rows)-1].Line
+	}
+}
+
+// applyBatch applies rows and records the progress of the job, which is at
+// fromLine.
+func (r *productImportRunner) applyBatch(ctx context.Context, job *domain.ImportJob, fromLine int, rows []domain.ImportRow) error {
+	var progress domain.ImportProgress
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		progress, err = r.apply(ctx, rows)
+		if err != nil {
+			return err
+		}
+
+		if job.DryRun {
+			return errDryRun
+		}
+		return r.repo.RecordProgress(ctx, job.ID, fromLine, progress, importLease)
+	})
+	if errors.Is(err, errDryRun) {
+		return r.repo.RecordProgress(ctx, job.ID, fromLine, progress, importLease)
+	}
+	return err
+}
+
+// apply writes the products of rows and audits every product and image it
+// creates or updates. Rows naming an unknown category are rejected.
+func (r *productImportRunner) apply(ctx context.Context, rows []domain.ImportRow) (domain.ImportProgress, error) {
+	progress := domain.ImportProgress{LastLine: rows[len(rows)-1].Line, Processed: len(rows)}
+
+	var categorySlugs []string
+	for _, row := range rows {
+		categorySlugs = append(categorySlugs, row.CategorySlugs...)
+	}
+	categoryIDs := map[string]uuid.UUID{}
+	if len(categorySlugs) > 0 {
+		var err error
+		if categoryIDs, err = r.repo.FetchCategoryIDs(ctx, uniqueStrings(categorySlugs)); err != nil {
+			return progress, err
+		}
+	}
+
+	valid := make([]domain.ImportRow, 0, len(rows))
+	slugs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		for _, slug := range row.CategorySlugs {
+			if _, ok := categoryIDs[slug]; !ok {
+				row.Error = "unknown category: " + slug
+				break
+			}
+		}
+		if row.Error != "" {
+			progress.Rejected = append(progress.Rejected, row)
+			continue
+		}
+		valid = append(valid, row)
+		slugs = append(slugs, row.Slug)
+	}
+	if len(valid) == 0 {
+		return progress, nil
+	}
+
+	before, err := r.repo.FetchProductsBySlugs(ctx, slugs)
+	if err != nil {
+		return progress, err
+	}
+
+	imported, err := r.repo.UpsertProducts(ctx, valid, categoryIDs)
+	if err != nil {
+		return progress, err
+	}
+	productIDs := make(map[string]uuid.UUID, len(imported))
+	for _, p := range imported {
+		productIDs[p.Slug] = p.ID
+	}
+
+	images, err := r.repo.AddImages(ctx, valid, productIDs)
+	if err != nil {
+		return progress, err
+	}
+
+	after, err := r.repo.FetchProductsBySlugs(ctx, slugs)
+	if err != nil {
+		return progress, err
+	}
+
+	for _, p := range imported {
+		product := after[p.Slug]
+		if p.Created {
+			progress.Created++
+			err = r.audit.Record(ctx, domain.AuditActionCreate, domain.AuditEntityProduct, p.ID.String(), nil, &product)
+		} else {
+			progress.Updated++
+			previous := before[p.Slug]
+			err = r.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProduct, p.ID.String(), &previous, &product)
+		}
+		if err != nil {
+			return progress, err
+		}
+	}
+	for _, img := range images {
+		if err := r.audit.Record(ctx, domain.AuditActionCreate, domain.AuditEntityProductImage, img.ID.String(), nil, &img); err != nil {
+			return progress, err
+		}
+	}
+	return progress, nil
+}
+
+func (r *productImportRunner) Prune(ctx context.Context) (int, error) {
+	deleted, err := r.repo.DeleteFinishedJobs(ctx, time.Now().Add(-r.retention))
+	return int(deleted), err
+}
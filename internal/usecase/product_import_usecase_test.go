@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"product-listing/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type sliceRowReader []domain.ImportRow
+
+func (r *sliceRowReader) Next() (domain.ImportRow, error) {
+	if len(*r) == 0 {
+		return domain.ImportRow{}, io.EOF
+	}
+	row := (*r)[0]
+	*r = (*r)[1:]
+	return row, nil
+}
+
+// fakeImportRepository holds one job. Products are keyed by slug and start
+// out as existing.
+type fakeImportRepository struct {
+	job        domain.ImportJob
+	rows       []domain.ImportRow
+	categories map[string]uuid.UUID
+	products   map[string]domain.Product
+	finished   string
+}
+
+func (r *fakeImportRepository) CreateJob(ctx context.Context, job domain.ImportJob) (*domain.ImportJob, error) {
+	job.ID = uuid.New()
+	job.Status = domain.ImportJobQueued
+	r.job = job
+	return &job, nil
+}
+
+func (r *fakeImportRepository) StageRows(ctx context.Context, jobID uuid.UUID, rows []domain.ImportRow) error {
+	r.rows = append(r.rows, rows...)
+	return nil
+}
+
+func (r *fakeImportRepository) SetJobTotals(ctx context.Context, jobID uuid.UUID, totalRows, rejectedRows int) (*domain.ImportJob, error) {
+	r.job.TotalRows = totalRows
+	r.job.ProcessedRows = rejectedRows
+	r.job.FailedCount = rejectedRows
+	job := r.job
+	return &job, nil
+}
+
+func (r *fakeImportRepository) FetchJobs(ctx context.Context, limit int) ([]domain.ImportJob, error) {
+	return []domain.ImportJob{r.job}, nil
+}
+
+func (r *fakeImportRepository) FetchJobByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	if id != r.job.ID {
+		return nil, domain.NewNotFoundError("import_not_found", "import job not found")
+	}
+	job := r.job
+	return &job, nil
+}
+
+func (r *fakeImportRepository) FetchRejectedRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]domain.ImportRow, error) {
+	var rows []domain.ImportRow
+	for _, row := range r.rows {
+		if row.Error != "" && row.Line > afterLine && len(rows) < limit {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (r *fakeImportRepository) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ImportJob, error) {
+	if r.job.Status != domain.ImportJobQueued {
+		return nil, nil
+	}
+	r.job.Status = domain.ImportJobRunning
+	job := r.job
+	return &job, nil
+}
+
+func (r *fakeImportRepository) FetchPendingRows(ctx context.Context, jobID uuid.UUID, afterLine, limit int) ([]domain.ImportRow, error) {
+	var rows []domain.ImportRow
+	for _, row := range r.rows {
+		if row.Error == "" && row.Line > afterLine && len(rows) < limit {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (r *fakeImportRepository) RecordProgress(ctx context.Context, jobID uuid.UUID, fromLine int, progress domain.ImportProgress, lease time.Duration) error {
+	if r.job.LastLine != fromLine {
+		return domain.ErrImportJobTakenOver
+	}
+	for _, rejected := range progress.Rejected {
+		for i := range r.rows {
+			if r.rows[i].Line == rejected.Line {
+				r.rows[i].Error = rejected.Error
+			}
+		}
+	}
+	r.job.LastLine = progress.LastLine
+	r.job.ProcessedRows += progress.Processed
+	r.job.CreatedCount += progress.Created
+	r.job.UpdatedCount += progress.Updated
+	r.job.FailedCount += len(progress.Rejected)
+	return nil
+}
+
+func (r *fakeImportRepository) FinishJob(ctx context.Context, jobID uuid.UUID, status, message string) error {
+	r.job.Status = status
+	r.finished = message
+	return nil
+}
+
+func (r *fakeImportRepository) DeleteFinishedJobs(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeImportRepository) FetchCategoryIDs(ctx context.Context, slugs []string) (map[string]uuid.UUID, error) {
+	result := make(map[string]uuid.UUID)
+	for _, slug := range slugs {
+		if id, ok := r.categories[slug]; ok {
+			result[slug] = id
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeImportRepository) FetchProductsBySlugs(ctx context.Context, slugs []string) (map[string]domain.Product, error) {
+	result := make(map[string]domain.Product)
+	for _, slug := range slugs {
+		if p, ok := r.products[slug]; ok {
+			result[slug] = p
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeImportRepository) UpsertProducts(ctx context.Context, rows []domain.ImportRow, categoryIDs map[string]uuid.UUID) ([]domain.ImportedProduct, error) {
+	var result []domain.ImportedProduct
+	for _, row := range rows {
+		p, ok := r.products[row.Slug]
+		if !ok {
+			p.ID = uuid.New()
+		}
+		p.Name, p.Slug, p.Price = row.Name, row.Slug, row.Price
+		r.products[row.Slug] = p
+		result = append(result, domain.ImportedProduct{ID: p.ID, Slug: p.Slug, Created: !ok})
+	}
+	return result, nil
+}
+
+func (r *fakeImportRepository) AddImages(ctx context.Context, rows []domain.ImportRow, productIDs map[string]uuid.UUID) ([]domain.ProductImage, error) {
+	var images []domain.ProductImage
+	for _, row := range rows {
+		for _, u := range row.ImageURLs {
+			images = append(images, domain.ProductImage{ID: uuid.New(), ProductID: productIDs[row.Slug], Url: u})
+		}
+	}
+	return images, nil
+}
+
+type fakeAuditRepository struct {
+	entries []domain.AuditEntry
+}
+
+func (r *fakeAuditRepository) Create(ctx context.Context, entry domain.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *fakeAuditRepository) Fetch(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return r.entries, nil
+}
+
+type fakeStoreRepository struct {
+	store domain.Store
+}
+
+func (r *fakeStoreRepository) Create(ctx context.Context, input domain.StoreInput) (*domain.Store, error) {
+	return nil, nil
+}
+
+func (r *fakeStoreRepository) Fetch(ctx context.Context) ([]domain.Store, error) {
+	return []domain.Store{r.store}, nil
+}
+
+func (r *fakeStoreRepository) FetchByID(ctx context.Context, id uuid.UUID) (*domain.Store, error) {
+	return &r.store, nil
+}
+
+func (r *fakeStoreRepository) FetchBySlug(ctx context.Context, slug string) (*domain.Store, error) {
+	return &r.store, nil
+}
+
+func TestProductImportRejectsInvalidRows(t *testing.T) {
+	repo := &fakeImportRepository{}
+	u := NewProductImportUsecase(repo, fakeTransactor{})
+
+	ctx := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "alice", Method: "jwt"})
+	rows := sliceRowReader{
+		{Line: 2, Name: "Mug", Slug: "mug", Price: 12, CategorySlugs: []string{"kitchen", "kitchen"}},
+		{Line: 3, Name: "Cup", Slug: "mug", Price: 3},
+		{Line: 4, Slug: "nameless", Price: 3},
+		{Line: 5, Name: "Pot", Slug: "pot", Price: -1},
+		{Line: 6, Name: "Pan", Slug: "pan", Price: 30, ImageURLs: []string{"ftp://example.com/pan.jpg"}},
+		{Line: 7, Error: "invalid price: abc"},
+	}
+
+	job, err := u.Import(ctx, &rows, domain.ImportFormatCSV, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if job.TotalRows != 6 || job.FailedCount != 5 || job.Actor != "alice" {
+		t.Errorf("job = %+v, want 6 rows with 5 rejected by alice", job)
+	}
+
+	rejected, next, err := u.GetRejectedRows(ctx, job.ID.String(), "", 2)
+	if err != nil {
+		t.Fatalf("GetRejectedRows: %v", err)
+	}
+	if len(rejected) != 2 || rejected[0].Error != "slug already used on line 2" || next != "4" {
+		t.Errorf("rejected = %+v with cursor %q", rejected, next)
+	}
+	if got := repo.rows[0].CategorySlugs; len(got) != 1 {
+		t.Errorf("category slugs = %v, want repeats dropped", got)
+	}
+
+	empty := sliceRowReader{}
+	if _, err := u.Import(ctx, &empty, domain.ImportFormatCSV, false); err == nil {
+		t.Error("Import of an empty file succeeded")
+	}
+}
+
+func TestProductImportRunnerAppliesRows(t *testing.T) {
+	store := domain.Store{ID: uuid.New(), Slug: "main"}
+	repo := &fakeImportRepository{
+		categories: map[string]uuid.UUID{"kitchen": uuid.New()},
+		products:   map[string]domain.Product{"mug": {ID: uuid.New(), Slug: "mug", Name: "Old mug"}},
+	}
+	audit := &fakeAuditRepository{}
+	runner := NewProductImportRunner(repo, &fakeStoreRepository{store: store}, fakeTransactor{}, NewAuditUsecase(audit), time.Hour)
+
+	ctx := domain.ContextWithStore(context.Background(), &store)
+	ctx = domain.ContextWithPrincipal(ctx, &domain.Principal{Subject: "alice", Method: "api_key"})
+	rows := sliceRowReader{
+		{Line: 1, Name: "Mug", Slug: "mug", Price: 12, CategorySlugs: []string{"kitchen"}},
+		{Line: 2, Name: "Tea", Slug: "tea", Price: 4, ImageURLs: []string{"https://cdn.example.com/tea.jpg"}},
+		{Line: 3, Name: "Pan", Slug: "pan", Price: 30, CategorySlugs: []string{"garden"}},
+	}
+	if _, err := NewProductImportUsecase(repo, fakeTransactor{}).Import(ctx, &rows, domain.ImportFormatNDJSON, false); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	ran, err := runner.Run(context.Background())
+	if err != nil || !ran {
+		t.Fatalf("Run = %v, %v", ran, err)
+	}
+	job := repo.job
+	if job.Status != domain.ImportJobSucceeded || job.ProcessedRows != 3 || job.CreatedCount != 1 || job.UpdatedCount != 1 || job.FailedCount != 1 {
+		t.Errorf("job = %+v, want 1 created, 1 updated and 1 failed", job)
+	}
+	if repo.rows[2].Error != "unknown category: garden" {
+		t.Errorf("row 3 error = %q", repo.rows[2].Error)
+	}
+
+	// The mug update, the tea and its image
+	if len(audit.entries) != 3 {
+		t.Fatalf("audited %d changes, want 3", len(audit.entries))
+	}
+	for _, entry := range audit.entries {
+		if entry.StoreID != store.ID || entry.Actor != "alice" || entry.ActorMethod != "api_key" {
+			t.Errorf("entry = %+v, want it made by alice in the job's store", entry)
+		}
+	}
+
+	if ran, err := runner.Run(context.Background()); err != nil || ran {
+		t.Errorf("second Run = %v, %v, want nothing to do", ran, err)
+	}
+}
@@ -8,30 +8,49 @@ import (
 )
 
 type ProductUsecase interface {
-	CreateProduct(ctx context.Context, p domain.ProductInput) error
+	CreateProduct(ctx context.Context, p domain.ProductInput) (*domain.Product, error)
 	GetProducts(ctx context.Context, page, limit int) ([]domain.Product, error)
+	GetProductsAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error)
 	GetProductCount(ctx context.Context) (int, error)
 	GetProductsById(ctx context.Context, id string) (*domain.Product, error)
 	GetProductsByCategory(ctx context.Context, cID string) ([]domain.Product, error)
-	UpdateProduct(ctx context.Context, id string, p domain.ProductInput) error
+	GetProductsByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error)
+	UpdateProduct(ctx context.Context, id string, p domain.ProductInput) (*domain.Product, error)
 	DeleteProduct(ctx context.Context, id string) error
+	ExpandProducts(ctx context.Context, products []domain.Product, include domain.ProductInclude) error
 }
 
+// productUsecase records every write in the audit log, in the same
+// transaction as the write.
 type productUsecase struct {
-	repo domain.ProductRepository
+	repo      domain.ProductRepository
+	imageRepo domain.ProductImageRepository
+	signer    domain.URLSigner
+	tx        domain.Transactor
+	audit     AuditUsecase
 }
 
-func NewProductUsecase(repo domain.ProductRepository) ProductUsecase {
-	return &productUsecase{repo: repo}
+func NewProductUsecase(repo domain.ProductRepository, imageRepo domain.ProductImageRepository, signer domain.URLSigner, tx domain.Transactor, audit AuditUsecase) ProductUsecase {
+	return &productUsecase{repo: repo, imageRepo: imageRepo, signer: signer, tx: tx, audit: audit}
 }
 
-func (u *productUsecase) CreateProduct(ctx context.Context, p domain.ProductInput) error {
-	err := u.repo.Create(ctx, p)
+func (u *productUsecase) CreateProduct(ctx context.Context, p domain.ProductInput) (*domain.Product, error) {
+	var product *domain.Product
+	err := u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		product, err = u.repo.Create(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionCreate, domain.AuditEntityProduct, product.ID.String(), nil, product)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	u.presentProduct(product)
+	return product, nil
 }
 
 func (u *productUsecase) GetProducts(ctx context.Context, page, limit int) ([]domain.Product, error) {
@@ -41,6 +60,23 @@ func (u *productUsecase) GetProducts(ctx context.Context, page, limit int) ([]do
 		return nil, err
 	}
 
+	for i := range products {
+		u.presentProduct(&products[i])
+	}
+	return products, nil
+}
+
+// GetProductsAfter pages through the products by cursor, see
+// domain.ProductRepository.FetchAfter.
+func (u *productUsecase) GetProductsAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error) {
+	products, err := u.repo.FetchAfter(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range products {
+		u.presentProduct(&products[i])
+	}
 	return products, nil
 }
 
@@ -61,6 +97,7 @@ func (u *productUsecase) GetProductsById(ctx context.Context, id string) (*domai
 		return nil, err
 	}
 
+	u.presentProduct(product)
 	return product, nil
 }
 
@@ -71,25 +108,132 @@ func (u *productUsecase) GetProductsByCategory(ctx context.Context, cID string)
 		return nil, err
 	}
 
+	for i := range products {
+		u.presentProduct(&products[i])
+	}
 	return products, nil
 }
 
-func (u *productUsecase) UpdateProduct(ctx context.Context, id string, p domain.ProductInput) error {
-	uid, _ := uuid.Parse(id)
-	err := u.repo.Update(ctx, uid, p)
+// GetProductsByCategories loads the newest limit products of many categories
+// in one query.
+func (u *productUsecase) GetProductsByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error) {
+	if len(categoryIDs) == 0 {
+		return map[uuid.UUID][]domain.Product{}, nil
+	}
+
+	products, err := u.repo.FetchByCategories(ctx, categoryIDs, limit)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	for _, list := range products {
+		for i := range list {
+			u.presentProduct(&list[i])
+		}
+	}
+	return products, nil
+}
+
+func (u *productUsecase) UpdateProduct(ctx context.Context, id string, p domain.ProductInput) (*domain.Product, error) {
+	uid, err := parseID(id, "invalid_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var after *domain.Product
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		if err := u.repo.Update(ctx, uid, p); err != nil {
+			return err
+		}
+
+		after, err = u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionUpdate, domain.AuditEntityProduct, id, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.presentProduct(after)
+	return after, nil
 }
 
 func (u *productUsecase) DeleteProduct(ctx context.Context, id string) error {
-	uid, _ := uuid.Parse(id)
-	err := u.repo.Delete(ctx, uid)
+	uid, err := parseID(id, "invalid_id")
 	if err != nil {
 		return err
 	}
 
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.repo.FetchById(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		// The delete cascades to the images, each is recorded as deleted too.
+		// The lock keeps images from being added until the delete commits.
+		if err := u.imageRepo.LockProduct(ctx, uid); err != nil {
+			return err
+		}
+		images, err := u.imageRepo.GetByProductID(ctx, uid)
+		if err != nil {
+			return err
+		}
+		for _, img := range images {
+			if err := u.audit.Record(ctx, domain.AuditActionDelete, domain.AuditEntityProductImage, img.ID.String(), img, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := u.repo.Delete(ctx, uid); err != nil {
+			return err
+		}
+
+		return u.audit.Record(ctx, domain.AuditActionDelete, domain.AuditEntityProduct, id, before, nil)
+	})
+}
+
+// ExpandProducts fills the requested relations in place with one query per
+// relation, however many products are passed.
+func (u *productUsecase) ExpandProducts(ctx context.Context, products []domain.Product, include domain.ProductInclude) error {
+	if !include.Images || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+
+	images, err := u.imageRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+		if products[i].Images == nil {
+			products[i].Images = []domain.ProductImage{}
+		}
+		for j := range products[i].Images {
+			presentImage(u.signer, &products[i].Images[j])
+		}
+	}
+
 	return nil
 }
+
+// presentProduct replaces a private primary image URL with a signed one.
+func (u *productUsecase) presentProduct(p *domain.Product) {
+	if p.PrimaryImagePrivate {
+		p.PrimaryImageURL = u.signer.Sign(domain.ImageMediaPath(p.PrimaryImageID))
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type fakeProductRepository struct {
+	products map[uuid.UUID]domain.Product
+}
+
+func (r *fakeProductRepository) Create(ctx context.Context, p domain.ProductInput) (*domain.Product, error) {
+	product := domain.Product{ID: uuid.New(), Name: p.Name, Description: p.Description, Price: p.Price}
+	r.products[product.ID] = product
+	return &product, nil
+}
+
+func (r *fakeProductRepository) Fetch(ctx context.Context, limit, offset int) ([]domain.Product, error) {
+	return nil, nil
+}
+
+func (r *fakeProductRepository) FetchAfter(ctx context.Context, after *domain.ProductCursor, limit int) ([]domain.Product, error) {
+	return nil, nil
+}
+
+func (r *fakeProductRepository) FetchById(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("product_not_found", "product not found")
+	}
+	return &product, nil
+}
+
+func (r *fakeProductRepository) FetchByCategory(ctx context.Context, cID uuid.UUID) ([]domain.Product, error) {
+	return nil, nil
+}
+
+func (r *fakeProductRepository) FetchByCategories(ctx context.Context, categoryIDs []uuid.UUID, limit int) (map[uuid.UUID][]domain.Product, error) {
+	return nil, nil
+}
+
+func (r *fakeProductRepository) FetchCount(ctx context.Context) (int, error) {
+	return len(r.products), nil
+}
+
+func (r *fakeProductRepository) Update(ctx context.Context, id uuid.UUID, p domain.ProductInput) error {
+	product := r.products[id]
+	product.Name, product.Description, product.Price = p.Name, p.Description, p.Price
+	r.products[id] = product
+	return nil
+}
+
+func (r *fakeProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.products, id)
+	return nil
+}
+
+func TestProductChangesReportBadIDs(t *testing.T) {
+	repo := &fakeProductRepository{products: make(map[uuid.UUID]domain.Product)}
+	u := NewProductUsecase(repo, newFakeImageRepository(), fakeSigner{}, fakeTransactor{}, NewAuditUsecase(&fakeAuditRepository{}))
+
+	tests := []struct {
+		name string
+		id   string
+		kind domain.ErrorKind
+	}{
+		{"malformed", "not-a-uuid", domain.ErrorKindInvalid},
+		{"missing", uuid.NewString(), domain.ErrorKindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var domainErr *domain.Error
+			if err := u.DeleteProduct(storeContext(), tt.id); !errors.As(err, &domainErr) || domainErr.Kind != tt.kind {
+				t.Errorf("DeleteProduct = %v, want kind %v", err, tt.kind)
+			}
+			if _, err := u.UpdateProduct(storeContext(), tt.id, domain.ProductInput{Name: "Mug"}); !errors.As(err, &domainErr) || domainErr.Kind != tt.kind {
+				t.Errorf("UpdateProduct = %v, want kind %v", err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestDeleteProductRecordsItsImages(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Mug"}
+	repo := &fakeProductRepository{products: map[uuid.UUID]domain.Product{product.ID: product}}
+	first, second := testImage(product.ID, 0, true), testImage(product.ID, 1, false)
+	images := newFakeImageRepository(first, second, testImage(uuid.New(), 0, true))
+	audit := &fakeAuditRepository{}
+	u := NewProductUsecase(repo, images, fakeSigner{}, fakeTransactor{}, NewAuditUsecase(audit))
+
+	if err := u.DeleteProduct(storeContext(), product.ID.String()); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+
+	deleted := make(map[string]string)
+	for _, entry := range audit.entries {
+		if entry.Action == domain.AuditActionDelete {
+			deleted[entry.EntityID] = entry.EntityType
+		}
+	}
+	want := map[string]string{
+		product.ID.String(): domain.AuditEntityProduct,
+		first.ID.String():   domain.AuditEntityProductImage,
+		second.ID.String():  domain.AuditEntityProductImage,
+	}
+	if len(deleted) != len(want) {
+		t.Fatalf("deleted = %v, want %v", deleted, want)
+	}
+	for id, entityType := range want {
+		if deleted[id] != entityType {
+			t.Errorf("deleted %s as %q, want %q", id, deleted[id], entityType)
+		}
+	}
+}
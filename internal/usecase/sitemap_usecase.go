@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"product-listing/internal/storefront"
+)
+
+// maxSitemapImages is the most images the image extension allows per URL.
+const maxSitemapImages = 1000
+
+type SitemapUsecase interface {
+	// Index lists the sitemaps of the request's store: its categories, then
+	// its products, domain.MaxSitemapURLs per sitemap. There is always a first
+	// sitemap of each kind, so the index is never empty.
+	Index(ctx context.Context) ([]domain.SitemapPage, error)
+	// Sitemap lists the storefront pages of a sitemap the index lists.
+	Sitemap(ctx context.Context, kind string, number int) ([]domain.SitemapURL, error)
+}
+
+// sitemapUsecase points sitemaps at the storefront, which links builds the
+// URLs of.
+type sitemapUsecase struct {
+	repo  domain.SitemapRepository
+	links storefront.Links
+}
+
+// NewSitemapUsecase serves sitemaps once links has a base URL.
+func NewSitemapUsecase(repo domain.SitemapRepository, links storefront.Links) SitemapUsecase {
+	return &sitemapUsecase{repo: repo, links: links}
+}
+
+func (u *sitemapUsecase) configured() error {
+	if u.links.BaseURL == "" {
+		return domain.NewNotFoundError("sitemap_not_configured", "sitemaps are not configured")
+	}
+	return nil
+}
+
+func (u *sitemapUsecase) Index(ctx context.Context) ([]domain.SitemapPage, error) {
+	if err := u.configured(); err != nil {
+		return nil, err
+	}
+
+	categories, err := u.repo.FetchCategoryPages(ctx, domain.MaxSitemapURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		categories = []domain.SitemapPage{{Kind: domain.SitemapCategories, Number: 1}}
+	}
+
+	products, err := u.repo.FetchProductPages(ctx, domain.MaxSitemapURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		products = []domain.SitemapPage{{Kind: domain.SitemapProducts, Number: 1}}
+	}
+
+	return append(categories, products...), nil
+}
+
+func (u *sitemapUsecase) Sitemap(ctx context.Context, kind string, number int) ([]domain.SitemapURL, error) {
+	if err := u.configured(); err != nil {
+		return nil, err
+	}
+	if number < 1 {
+		return nil, domain.NewNotFoundError("sitemap_not_found", "sitemap not found")
+	}
+
+	var (
+		entries []domain.SitemapEntry
+		err     error
+	)
+	switch kind {
+	case domain.SitemapCategories:
+		entries, err = u.repo.FetchCategories(ctx, number, domain.MaxSitemapURLs)
+	case domain.SitemapProducts:
+		entries, err = u.repo.FetchProducts(ctx, number, domain.MaxSitemapURLs)
+	default:
+		return nil, domain.NewNotFoundError("sitemap_not_found", "sitemap not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The first sitemap of a kind is listed even when it is empty
+	if len(entries) == 0 && number > 1 {
+		return nil, domain.NewNotFoundError("sitemap_not_found", "sitemap not found")
+	}
+
+	store := domain.StoreFromContext(ctx)
+	urls := make([]domain.SitemapURL, 0, len(entries))
+	for _, e := range entries {
+		url := domain.SitemapURL{LastModified: e.UpdatedAt, ImageURLs: e.ImageURLs}
+		if kind == domain.SitemapCategories {
+			url.Loc = u.links.Category(store, e.Slug, e.ID)
+		} else {
+			url.Loc = u.links.Product(store, e.Slug, e.ID)
+		}
+		if len(url.ImageURLs) > maxSitemapImages {
+			url.ImageURLs = url.ImageURLs[:maxSitemapImages]
+		}
+		urls = append(urls, url)
+	}
+	return urls, nil
+}
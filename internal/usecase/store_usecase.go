@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var storeSlugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type StoreUsecase interface {
+	CreateStore(ctx context.Context, input domain.StoreInput) (*domain.Store, error)
+	GetStores(ctx context.Context) ([]domain.Store, error)
+	ResolveStore(ctx context.Context, principal *domain.Principal, requested string) (*domain.Store, error)
+}
+
+// storeUsecase decides which store a request acts on. defaultSlug is used when
+// neither the caller nor the request names one.
+type storeUsecase struct {
+	repo        domain.StoreRepository
+	defaultSlug string
+}
+
+func NewStoreUsecase(repo domain.StoreRepository, defaultSlug string) StoreUsecase {
+	return &storeUsecase{repo: repo, defaultSlug: defaultSlug}
+}
+
+func (u *storeUsecase) CreateStore(ctx context.Context, input domain.StoreInput) (*domain.Store, error) {
+	if input.Name == "" {
+		return nil, domain.NewInvalidError("store_name_required", "store name cannot be empty")
+	}
+	// Slugs double as subdomains, so they follow DNS label rules
+	if !storeSlugPattern.MatchString(input.Slug) {
+		return nil, domain.NewInvalidError("invalid_store_slug", "store slug must be a lowercase DNS label")
+	}
+
+	return u.repo.Create(ctx, input)
+}
+
+func (u *storeUsecase) GetStores(ctx context.Context) ([]domain.Store, error) {
+	return u.repo.Fetch(ctx)
+}
+
+// ResolveStore picks the store bound to the principal, else the requested
+// slug, else the default store. A principal bound to one store cannot request
+// another.
+func (u *storeUsecase) ResolveStore(ctx context.Context, principal *domain.Principal, requested string) (*domain.Store, error) {
+	if principal != nil && principal.Store != "" {
+		store, err := u.fetch(ctx, principal.Store)
+		if err != nil {
+			return nil, err
+		}
+		if requested != "" && requested != store.Slug {
+			return nil, domain.NewForbiddenError("store_mismatch", "credentials are not valid for store "+requested)
+		}
+		return store, nil
+	}
+
+	if requested == "" {
+		requested = u.defaultSlug
+	}
+	if requested == "" {
+		return nil, domain.NewInvalidError("store_required", "no store given in the X-Store header or host name")
+	}
+
+	return u.repo.FetchBySlug(ctx, requested)
+}
+
+// fetch accepts a store ID or slug, as found in credentials.
+func (u *storeUsecase) fetch(ctx context.Context, ref string) (*domain.Store, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return u.repo.FetchByID(ctx, id)
+	}
+	return u.repo.FetchBySlug(ctx, ref)
+}
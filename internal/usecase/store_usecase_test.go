@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"product-listing/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// storeList finds stores by ID or slug among a fixed list.
+type storeList struct {
+	domain.StoreRepository
+	stores []domain.Store
+}
+
+func (r storeList) FetchByID(ctx context.Context, id uuid.UUID) (*domain.Store, error) {
+	for _, s := range r.stores {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("store_not_found", "store not found")
+}
+
+func (r storeList) FetchBySlug(ctx context.Context, slug string) (*domain.Store, error) {
+	for _, s := range r.stores {
+		if s.Slug == slug {
+			return &s, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("store_not_found", "store not found")
+}
+
+func TestResolveStore(t *testing.T) {
+	acme := domain.Store{ID: uuid.New(), Slug: "acme"}
+	other := domain.Store{ID: uuid.New(), Slug: "other"}
+	repo := storeList{stores: []domain.Store{acme, other, {ID: uuid.New(), Slug: "default"}}}
+
+	tests := []struct {
+		name        string
+		defaultSlug string
+		bound       string
+		requested   string
+		want        string
+		code        string
+	}{
+		{"bound by slug", "default", "acme", "", "acme", ""},
+		{"bound by ID", "default", acme.ID.String(), "", "acme", ""},
+		{"bound store requested", "default", "acme", "acme", "acme", ""},
+		{"bound to another store", "default", "acme", "other", "", "store_mismatch"},
+		{"bound by ID to another store", "default", other.ID.String(), "acme", "", "store_mismatch"},
+		{"unbound request", "default", "", "other", "other", ""},
+		{"unbound default", "default", "", "", "default", ""},
+		{"unknown store", "default", "", "missing", "", "store_not_found"},
+		{"no store", "", "", "", "", "store_required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewStoreUsecase(repo, tt.defaultSlug)
+			principal := &domain.Principal{Subject: "alice", Store: tt.bound}
+
+			store, err := u.ResolveStore(context.Background(), principal, tt.requested)
+			if tt.code != "" {
+				var domainErr *domain.Error
+				if !errors.As(err, &domainErr) || domainErr.Code != tt.code {
+					t.Fatalf("ResolveStore = %v, %v, want %s", store, err, tt.code)
+				}
+				return
+			}
+			if err != nil || store.Slug != tt.want {
+				t.Fatalf("ResolveStore = %v, %v, want %s", store, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateStoreRequiresDNSLabelSlug(t *testing.T) {
+	u := NewStoreUsecase(storeList{}, "default")
+
+	for _, slug := range []string{"", "Acme", "-acme", "acme-", "acme.shop", "acme_shop", string(make([]byte, 64))} {
+		_, err := u.CreateStore(context.Background(), domain.StoreInput{Slug: slug, Name: "Acme"})
+		var domainErr *domain.Error
+		if !errors.As(err, &domainErr) || domainErr.Code != "invalid_store_slug" {
+			t.Errorf("CreateStore(%q) = %v, want invalid_store_slug", slug, err)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package usecase
+
+// Usecases are built once at startup and shared by every API, so the REST,
+// GraphQL and gRPC servers act on the same state, such as the catalog cache.
+type Usecases struct {
+	Auth          AuthUsecase
+	Store         StoreUsecase
+	Idempotency   IdempotencyUsecase
+	Outbox        OutboxUsecase
+	Audit         AuditUsecase
+	Webhook       WebhookUsecase
+	Category      CategoryUsecase
+	Product       ProductUsecase
+	ProductImage  ProductImageUsecase
+	ChangeFeed    ChangeFeedUsecase
+	ProductImport ProductImportUsecase
+	ProductExport ProductExportUsecase
+	ProductFeed   ProductFeedUsecase
+	Sitemap       SitemapUsecase
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"product-listing/internal/domain"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookBatchSize is how many deliveries are claimed and sent at once
+	webhookBatchSize = 50
+	// webhookLease hides claimed deliveries from other instances. It outlasts
+	// any send, so a delivery is only claimed twice when an instance dies
+	// while sending it.
+	webhookLease = 5 * time.Minute
+)
+
+type WebhookDispatcher interface {
+	// Dispatch sends the deliveries that are due and returns how many it
+	// attempted.
+	Dispatch(ctx context.Context) (int, error)
+}
+
+// webhookDispatcher sends deliveries of every store. A failed delivery is
+// retried after backoffBase, doubling with each attempt up to backoffMax,
+// until maxAttempts are used up and it is dead.
+type webhookDispatcher struct {
+	repo        domain.WebhookRepository
+	sender      domain.WebhookSender
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+}
+
+func NewWebhookDispatcher(repo domain.WebhookRepository, sender domain.WebhookSender, maxAttempts int, backoffBase, backoffMax time.Duration) WebhookDispatcher {
+	return &webhookDispatcher{
+		repo:        repo,
+		sender:      sender,
+		maxAttempts: max(maxAttempts, 1),
+		backoffBase: backoffBase,
+		backoffMax:  backoffMax,
+	}
+}
+
+func (d *webhookDispatcher) Dispatch(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deliveries))
+	for i, delivery := range deliveries {
+		wg.Go(func() {
+			errs[i] = d.send(ctx, delivery)
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// send makes one attempt and records its outcome.
+func (d *webhookDispatcher) send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	status, err := d.sender.Send(ctx, delivery.URL, delivery.Secret, delivery)
+	now := time.Now().UTC()
+	attempts := delivery.Attempts + 1
+
+	attempt := domain.WebhookAttempt{StatusCode: status, NextAttemptAt: now}
+	switch {
+	case err == nil:
+		attempt.Status = domain.WebhookDeliveryDelivered
+		attempt.DeliveredAt = &now
+	case attempts >= d.maxAttempts:
+		attempt.Status = domain.WebhookDeliveryDead
+		attempt.Error = err.Error()
+	default:
+		attempt.Status = domain.WebhookDeliveryPending
+		attempt.Error = err.Error()
+		attempt.NextAttemptAt = now.Add(d.backoff(attempts))
+	}
+
+	// Record even when ctx is cancelled, the attempt was made
+	return d.repo.RecordAttempt(context.WithoutCancel(ctx), delivery.ID, attempt)
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (d *webhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.backoffBase
+	for i := 1; i < attempts && wait < d.backoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, d.backoffMax)
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"product-listing/internal/domain"
+	"product-listing/internal/netguard"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWebhookDeliveryPageSize = 50
+	maxWebhookDeliveryPageSize     = 500
+)
+
+var webhookDeliveryStatuses = map[string]bool{
+	domain.WebhookDeliveryPending:   true,
+	domain.WebhookDeliveryDelivered: true,
+	domain.WebhookDeliveryDead:      true,
+}
+
+type WebhookUsecase interface {
+	domain.EventPublisher
+	CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id string, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter, cursor string) ([]domain.WebhookDelivery, string, error)
+	RetryDelivery(ctx context.Context, id string) error
+}
+
+// webhookUsecase manages subscriptions and, as the outbox's webhook
+// publisher, queues a delivery per matching subscription for every change.
+// Deliveries are sent by WebhookDispatcher.
+type webhookUsecase struct {
+	repo domain.WebhookRepository
+}
+
+func NewWebhookUsecase(repo domain.WebhookRepository) WebhookUsecase {
+	return &webhookUsecase{repo: repo}
+}
+
+// Publish queues the event for every active subscription that wants it. The
+// outbox relay calls it in the transaction that moves its cursor, so an event
+// is queued once even though it may be published again.
+func (u *webhookUsecase) Publish(ctx context.Context, event domain.ChangeEvent) error {
+	subscriptions, err := u.repo.FetchActiveSubscriptions(ctx, event.StoreID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, s := range subscriptions {
+		if !s.Wants(event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode webhook event: %w", err)
+			}
+		}
+
+		err := u.repo.CreateDelivery(ctx, domain.WebhookDelivery{
+			StoreID:        event.StoreID,
+			SubscriptionID: s.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateSubscription generates a secret when none is given. The secret is
+// only ever returned here.
+func (u *webhookUsecase) CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	if err := validateWebhookInput(&input); err != nil {
+		return nil, err
+	}
+
+	if input.Secret == "" {
+		input.Secret = newWebhookSecret()
+	}
+
+	return u.repo.CreateSubscription(ctx, input)
+}
+
+func (u *webhookUsecase) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return u.repo.FetchSubscriptions(ctx)
+}
+
+func (u *webhookUsecase) GetSubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscriptionID, err := parseWebhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FetchSubscriptionByID(ctx, subscriptionID)
+}
+
+// UpdateSubscription replaces the URL, events and active flag. The secret is
+// rotated only when a new one is given.
+func (u *webhookUsecase) UpdateSubscription(ctx context.Context, id string, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	subscriptionID, err := parseWebhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhookInput(&input); err != nil {
+		return nil, err
+	}
+	return u.repo.UpdateSubscription(ctx, subscriptionID, input)
+}
+
+// DeleteSubscription drops the subscription together with its deliveries.
+func (u *webhookUsecase) DeleteSubscription(ctx context.Context, id string) error {
+	subscriptionID, err := parseWebhookID(id)
+	if err != nil {
+		return err
+	}
+	return u.repo.DeleteSubscription(ctx, subscriptionID)
+}
+
+// GetDeliveries returns one page of deliveries, newest first, and the cursor
+// of the next page, which is empty on the last page.
+func (u *webhookUsecase) GetDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter, cursor string) ([]domain.WebhookDelivery, string, error) {
+	if filter.Status != "" && !webhookDeliveryStatuses[filter.Status] {
+		return nil, "", domain.NewInvalidError("invalid_status", "status must be pending, delivered or dead")
+	}
+
+	if filter.SubscriptionID != nil {
+		if _, err := u.repo.FetchSubscriptionByID(ctx, *filter.SubscriptionID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultWebhookDeliveryPageSize
+	}
+	if filter.Limit > maxWebhookDeliveryPageSize {
+		filter.Limit = maxWebhookDeliveryPageSize
+	}
+
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, "", domain.NewInvalidError("invalid_cursor", "invalid cursor: "+cursor)
+		}
+		filter.BeforeID = id
+	}
+
+	deliveries, err := u.repo.FetchDeliveries(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(deliveries) == filter.Limit {
+		next = strconv.FormatInt(deliveries[len(deliveries)-1].ID, 10)
+	}
+	return deliveries, next, nil
+}
+
+// RetryDelivery sends a dead delivery again, with a fresh set of attempts.
+func (u *webhookUsecase) RetryDelivery(ctx context.Context, id string) error {
+	deliveryID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || deliveryID <= 0 {
+		return domain.NewInvalidError("invalid_id", "invalid delivery id: "+id)
+	}
+	return u.repo.RetryDelivery(ctx, deliveryID)
+}
+
+func validateWebhookInput(input *domain.WebhookSubscriptionInput) error {
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return domain.NewInvalidError("invalid_webhook_url", "url must be an absolute http or https URL")
+	}
+	// Host names are checked when dialing, as they can resolve elsewhere later
+	if ip := net.ParseIP(target.Hostname()); ip != nil && !netguard.PublicIP(ip) {
+		return domain.NewInvalidError("invalid_webhook_url", "url must point to a public address")
+	}
+
+	if len(input.Events) == 0 {
+		return domain.NewInvalidError("webhook_events_required", "events cannot be empty")
+	}
+
+	types := domain.ChangeEventTypes()
+	for _, event := range input.Events {
+		if event == "*" || slices.Contains(types, event) {
+			continue
+		}
+		entity, ok := strings.CutSuffix(event, ".*")
+		if ok && slices.ContainsFunc(types, func(t string) bool { return strings.HasPrefix(t, entity+".") }) {
+			continue
+		}
+		return domain.NewInvalidError("invalid_webhook_event", "unknown event "+event)
+	}
+
+	input.Events = slices.Compact(slices.Sorted(slices.Values(input.Events)))
+	return nil
+}
+
+func parseWebhookID(id string) (uuid.UUID, error) {
+	subscriptionID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewInvalidError("invalid_id", "invalid webhook id: "+id)
+	}
+	return subscriptionID, nil
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-listing/internal/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeWebhookRepository keeps one store's subscriptions and deliveries in
+// memory. Claims return every pending delivery regardless of when it is due.
+type fakeWebhookRepository struct {
+	mu            sync.Mutex
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	s := domain.WebhookSubscription{ID: uuid.New(), URL: input.URL, Events: input.Events, Secret: input.Secret, Active: input.Active}
+	r.subscriptions = append(r.subscriptions, s)
+	return &s, nil
+}
+
+func (r *fakeWebhookRepository) FetchSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.subscriptions, nil
+}
+
+func (r *fakeWebhookRepository) FetchActiveSubscriptions(ctx context.Context, storeID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	var active []domain.WebhookSubscription
+	for _, s := range r.subscriptions {
+		if s.Active {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeWebhookRepository) FetchSubscriptionByID(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	for _, s := range r.subscriptions {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("webhook_not_found", "webhook subscription not found")
+}
+
+func (r *fakeWebhookRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, input domain.WebhookSubscriptionInput) (*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	delivery.ID = int64(len(r.deliveries) + 1)
+	delivery.Status = domain.WebhookDeliveryPending
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeWebhookRepository) FetchDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	return r.deliveries, nil
+}
+
+func (r *fakeWebhookRepository) RetryDelivery(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status != domain.WebhookDeliveryPending || len(claimed) == limit {
+			continue
+		}
+		s, _ := r.FetchSubscriptionByID(ctx, d.SubscriptionID)
+		d.URL, d.Secret = s.URL, s.Secret
+		claimed = append(claimed, d)
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) RecordAttempt(ctx context.Context, id int64, attempt domain.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := &r.deliveries[id-1]
+	d.Attempts++
+	d.Status = attempt.Status
+	d.NextAttemptAt = attempt.NextAttemptAt
+	d.LastStatusCode = attempt.StatusCode
+	d.LastError = attempt.Error
+	d.DeliveredAt = attempt.DeliveredAt
+	return nil
+}
+
+// flakySender answers 500 to the first failures deliveries and 200 after,
+// checking that each is sent to the subscription.
+type flakySender struct {
+	t        *testing.T
+	failures int
+	calls    int
+}
+
+func (s *flakySender) Send(ctx context.Context, url, secret string, delivery domain.WebhookDelivery) (int, error) {
+	if url != "https://example.com/hook" || secret != "secret" {
+		s.t.Errorf("sent to %s with secret %q", url, secret)
+	}
+	s.calls++
+	if s.calls <= s.failures {
+		return http.StatusInternalServerError, errors.New("receiver answered 500 Internal Server Error")
+	}
+	return http.StatusOK, nil
+}
+
+func TestWebhookPublishQueuesMatchingSubscriptions(t *testing.T) {
+	repo := &fakeWebhookRepository{}
+	u := NewWebhookUsecase(repo)
+	ctx := context.Background()
+
+	products, _ := u.CreateSubscription(ctx, domain.WebhookSubscriptionInput{URL: "http://example.com/a", Events: []string{"product.*"}, Active: true})
+	all, _ := u.CreateSubscription(ctx, domain.WebhookSubscriptionInput{URL: "http://example.com/b", Events: []string{"*"}, Active: true})
+	_, _ = u.CreateSubscription(ctx, domain.WebhookSubscriptionInput{URL: "http://example.com/c", Events: []string{"product.deleted"}, Active: true})
+	_, _ = u.CreateSubscription(ctx, domain.WebhookSubscriptionInput{URL: "http://example.com/d", Events: []string{"*"}, Active: false})
+
+	if products.Secret == "" {
+		t.Error("no secret generated")
+	}
+
+	event := domain.ChangeEvent{
+		ID:         uuid.New(),
+		Type:       "product.updated",
+		EntityType: domain.AuditEntityProduct,
+		EntityID:   uuid.NewString(),
+		Action:     domain.AuditActionUpdate,
+		Data:       json.RawMessage(`{"name":"Chair"}`),
+	}
+	if err := u.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("queued %d deliveries, want 2", len(repo.deliveries))
+	}
+	for i, want := range []uuid.UUID{products.ID, all.ID} {
+		d := repo.deliveries[i]
+		if d.SubscriptionID != want || d.EventID != event.ID || d.EventType != event.Type {
+			t.Errorf("delivery %d = %+v", i, d)
+		}
+	}
+
+	var payload domain.ChangeEvent
+	if err := json.Unmarshal(repo.deliveries[0].Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Type != event.Type || payload.EntityID != event.EntityID || string(payload.Data) != string(event.Data) {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestWebhookSubscriptionValidation(t *testing.T) {
+	u := NewWebhookUsecase(&fakeWebhookRepository{})
+
+	tests := []struct {
+		name  string
+		input domain.WebhookSubscriptionInput
+		code  string
+	}{
+		{"relative url", domain.WebhookSubscriptionInput{URL: "/hook", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"other scheme", domain.WebhookSubscriptionInput{URL: "ftp://example.com", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"loopback", domain.WebhookSubscriptionInput{URL: "http://127.0.0.1:8080/hook", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"private", domain.WebhookSubscriptionInput{URL: "http://10.0.0.5/hook", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"metadata", domain.WebhookSubscriptionInput{URL: "http://169.254.169.254/latest", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"ipv6 loopback", domain.WebhookSubscriptionInput{URL: "http://[::1]/hook", Events: []string{"*"}}, "invalid_webhook_url"},
+		{"no events", domain.WebhookSubscriptionInput{URL: "https://example.com"}, "webhook_events_required"},
+		{"unknown event", domain.WebhookSubscriptionInput{URL: "https://example.com", Events: []string{"order.created"}}, "invalid_webhook_event"},
+		{"unknown entity", domain.WebhookSubscriptionInput{URL: "https://example.com", Events: []string{"order.*"}}, "invalid_webhook_event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.CreateSubscription(context.Background(), tt.input)
+			domainErr, ok := err.(*domain.Error)
+			if !ok || domainErr.Code != tt.code {
+				t.Errorf("CreateSubscription = %v, want code %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcherRetriesUntilDelivered(t *testing.T) {
+	sender := &flakySender{t: t, failures: 2}
+	repo := &fakeWebhookRepository{}
+	subscription, _ := repo.CreateSubscription(context.Background(), domain.WebhookSubscriptionInput{URL: "https://example.com/hook", Secret: "secret", Events: []string{"*"}, Active: true})
+	_ = repo.CreateDelivery(context.Background(), domain.WebhookDelivery{SubscriptionID: subscription.ID, EventID: uuid.New(), EventType: "category.created", Payload: []byte(`{}`)})
+
+	d := NewWebhookDispatcher(repo, sender, 5, time.Minute, time.Hour)
+
+	for attempt, wantWait := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		if _, err := d.Dispatch(context.Background()); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+
+		got := repo.deliveries[0]
+		if got.Status != domain.WebhookDeliveryPending || got.LastStatusCode != http.StatusInternalServerError || got.LastError == "" {
+			t.Fatalf("after attempt %d: %+v", attempt+1, got)
+		}
+		if wait := got.NextAttemptAt.Sub(before); wait < wantWait || wait > wantWait+time.Second {
+			t.Errorf("after attempt %d: retry in %s, want %s", attempt+1, wait, wantWait)
+		}
+	}
+
+	if _, err := d.Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	got := repo.deliveries[0]
+	if got.Status != domain.WebhookDeliveryDelivered || got.Attempts != 3 || got.DeliveredAt == nil {
+		t.Errorf("delivery = %+v, want delivered after 3 attempts", got)
+	}
+	if sender.calls != 3 {
+		t.Errorf("sent %d times, want 3", sender.calls)
+	}
+
+	if n, _ := d.Dispatch(context.Background()); n != 0 {
+		t.Errorf("Dispatch sent %d deliveries after delivery, want 0", n)
+	}
+}
+
+func TestWebhookDispatcherGivesUp(t *testing.T) {
+	sender := &flakySender{t: t, failures: 100}
+	repo := &fakeWebhookRepository{}
+	subscription, _ := repo.CreateSubscription(context.Background(), domain.WebhookSubscriptionInput{URL: "https://example.com/hook", Secret: "secret", Events: []string{"*"}, Active: true})
+	_ = repo.CreateDelivery(context.Background(), domain.WebhookDelivery{SubscriptionID: subscription.ID, EventID: uuid.New(), EventType: "category.created", Payload: []byte(`{}`)})
+
+	d := NewWebhookDispatcher(repo, sender, 3, time.Minute, time.Hour)
+	for range 5 {
+		if _, err := d.Dispatch(context.Background()); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+
+	got := repo.deliveries[0]
+	if got.Status != domain.WebhookDeliveryDead || got.Attempts != 3 {
+		t.Errorf("delivery = %+v, want dead after 3 attempts", got)
+	}
+	if sender.calls != 3 {
+		t.Errorf("sent %d times, want 3", sender.calls)
+	}
+}
+
+func TestWebhookBackoffIsCapped(t *testing.T) {
+	d := &webhookDispatcher{backoffBase: 30 * time.Second, backoffMax: 10 * time.Minute}
+	for attempts, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		5:  8 * time.Minute,
+		6:  10 * time.Minute,
+		60: 10 * time.Minute,
+	} {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
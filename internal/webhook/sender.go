@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"product-listing/internal/domain"
+	"product-listing/internal/netguard"
+	"strconv"
+	"time"
+)
+
+// maxErrorBody is how much of a failed response is kept in the delivery log.
+const maxErrorBody = 512
+
+type sender struct {
+	client *http.Client
+}
+
+// NewSender posts deliveries with the given timeout per attempt. Redirects
+// are not followed, a receiver has to answer at the URL it registered, and
+// only public addresses are dialed, since the delivery log would otherwise
+// show what an internal service answered.
+func NewSender(timeout time.Duration) domain.WebhookSender {
+	return newSender(timeout, netguard.Transport(timeout))
+}
+
+func newSender(timeout time.Duration, transport http.RoundTripper) *sender {
+	return &sender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *sender) Send(ctx context.Context, url, secret string, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "product-listing-webhooks/1")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID.String())
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, delivery.Payload, time.Now()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("receiver answered %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"product-listing/internal/domain"
+	"product-listing/internal/netguard"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSenderSignsDelivery(t *testing.T) {
+	delivery := domain.WebhookDelivery{
+		ID:        42,
+		EventID:   uuid.New(),
+		EventType: "product.updated",
+		Payload:   []byte(`{"type":"product.updated"}`),
+	}
+
+	var got *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	status, err := newSender(time.Second, http.DefaultTransport).Send(context.Background(), receiver.URL, "secret", delivery)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", status, http.StatusNoContent)
+	}
+
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":       "application/json",
+		"X-Webhook-Event":    "product.updated",
+		"X-Webhook-Event-ID": delivery.EventID.String(),
+		"X-Webhook-Delivery": "42",
+	} {
+		if v := got.Header.Get(header); v != want {
+			t.Errorf("%s = %q, want %q", header, v, want)
+		}
+	}
+
+	if err := Verify("secret", got.Header.Get(SignatureHeader), body, time.Now(), time.Minute); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify("other", got.Header.Get(SignatureHeader), body, time.Now(), time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with wrong secret = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestSenderFailsOnErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	status, err := newSender(time.Second, http.DefaultTransport).Send(context.Background(), receiver.URL, "secret", domain.WebhookDelivery{Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("Send succeeded, want an error")
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+}
+
+func TestSenderDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	status, err := newSender(time.Second, http.DefaultTransport).Send(context.Background(), receiver.URL, "secret", domain.WebhookDelivery{Payload: []byte(`{}`)})
+	if err == nil || status != http.StatusTemporaryRedirect {
+		t.Errorf("Send = %d, %v, want %d and an error", status, err, http.StatusTemporaryRedirect)
+	}
+	if followed {
+		t.Error("redirect was followed")
+	}
+}
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, _ = w.Write([]byte("internal secrets"))
+	}))
+	defer receiver.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), receiver.URL, "secret", domain.WebhookDelivery{Payload: []byte(`{}`)})
+	if !errors.Is(err, netguard.ErrNonPublicAddress) || status != 0 {
+		t.Errorf("Send to %s = %d, %v, want %v", receiver.URL, status, err, netguard.ErrNonPublicAddress)
+	}
+	if called {
+		t.Error("loopback receiver was called")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	sent := time.Unix(1700000000, 0)
+	header := Sign("secret", body, sent)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"valid", header, body, sent.Add(time.Minute), nil},
+		{"tampered body", header, []byte(`{"id":"2"}`), sent, ErrInvalidSignature},
+		{"expired", header, body, sent.Add(time.Hour), ErrSignatureExpired},
+		{"malformed", "v1=abc", body, sent, ErrInvalidSignature},
+		{"second signature", header + ",v1=0000", body, sent, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify("secret", tt.header, tt.body, tt.now, 5*time.Minute); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
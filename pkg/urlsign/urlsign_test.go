@@ -0,0 +1,118 @@
+package urlsign
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	current = Key{ID: "k2", Secret: []byte("current secret")}
+	retired = Key{ID: "k1", Secret: []byte("retired secret")}
+)
+
+// split parses a signed URL into the path and query Verify takes.
+func split(t *testing.T, signed string) (string, url.Values) {
+	t.Helper()
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	return u.Path, u.Query()
+}
+
+func newSigner(t *testing.T, ttl time.Duration, keys ...Key) *Signer {
+	t.Helper()
+	s, err := New(keys, ttl)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	s := newSigner(t, time.Minute, current, retired)
+
+	path, query := split(t, s.Sign("/media/images/1"))
+	if query.Get("kid") != current.ID {
+		t.Errorf("kid = %q, want the first key %q", query.Get("kid"), current.ID)
+	}
+	if err := s.Verify(path, query); err != nil {
+		t.Errorf("Verify = %v, want nil", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	signed := newSigner(t, time.Minute, current).Sign("/media/images/1")
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		change func(path string, query url.Values) string
+		want   error
+	}{
+		{"expired link", newSigner(t, -time.Minute, current), nil, ErrExpired},
+		{"tampered path", nil, func(path string, query url.Values) string {
+			return "/media/images/2"
+		}, ErrInvalidSignature},
+		{"extended expiry", nil, func(path string, query url.Values) string {
+			query.Set("expires", "99999999999")
+			return path
+		}, ErrInvalidSignature},
+		{"unknown key", nil, func(path string, query url.Values) string {
+			query.Set("kid", "k9")
+			return path
+		}, ErrInvalidSignature},
+		{"missing signature", nil, func(path string, query url.Values) string {
+			query.Del("sig")
+			return path
+		}, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSigner(t, time.Minute, current)
+			link := signed
+			if tt.signer != nil {
+				s = tt.signer
+				link = s.Sign("/media/images/1")
+			}
+			path, query := split(t, link)
+			if tt.change != nil {
+				path = tt.change(path, query)
+			}
+			if err := s.Verify(path, query); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsRotatedOutKey(t *testing.T) {
+	// A link signed before rotation stays valid while its key is listed
+	path, query := split(t, newSigner(t, time.Minute, retired).Sign("/media/images/1"))
+
+	if err := newSigner(t, time.Minute, current, retired).Verify(path, query); err != nil {
+		t.Errorf("Verify with the old key listed = %v, want nil", err)
+	}
+	if err := newSigner(t, time.Minute, current).Verify(path, query); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify after the old key was removed = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys(" k2:current secret , k1:retired secret,")
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "k2" || string(keys[1].Secret) != "retired secret" {
+		t.Errorf("keys = %+v", keys)
+	}
+
+	for _, spec := range []string{"k1", ":secret", "k1:"} {
+		if _, err := ParseKeys(spec); err == nil || !strings.Contains(err.Error(), "id:secret") {
+			t.Errorf("ParseKeys(%q) = %v, want an error", spec, err)
+		}
+	}
+}
@@ -1,24 +1,23 @@
 #!/bin/bash
 
 BASE_URL="http://localhost:8081/api"
+# Write endpoints need an editor or admin key
+API_KEY="${API_KEY:-}"
 PREFIX=$(date +%s)
 
 echo "Creating 10 categories with prefix $PREFIX..."
+CATEGORY_IDS=""
 for i in {1..10}
 do
-  curl -s -X POST "$BASE_URL/category" -H "Content-Type: application/json" -d "{\"name\": \"MCat $PREFIX $i\", \"slug\": \"mcat-$PREFIX-$i\"}" > /dev/null
+  CAT_ID=$(curl -s -X POST -H "X-API-Key: $API_KEY" "$BASE_URL/category" -H "Content-Type: application/json" -d "{\"name\": \"MCat $PREFIX $i\", \"slug\": \"mcat-$PREFIX-$i\"}" | jq -r '.data.id')
+  CATEGORY_IDS="$CATEGORY_IDS $CAT_ID"
 done
-sleep 1
-
-echo "Fetching category IDs..."
-# Increased limit to 1000
-CATEGORY_IDS=$(curl -s "$BASE_URL/category?limit=1000" | jq -r ".data[] | select(.slug | contains(\"$PREFIX\")) | .id")
 IDS_ARRAY=($CATEGORY_IDS)
 NUM_IDS=${#IDS_ARRAY[@]}
-echo "Found $NUM_IDS new categories"
+echo "Created $NUM_IDS categories"
 
 if [ $NUM_IDS -eq 0 ]; then
-    echo "No categories found. Check server output."
+    echo "No categories created. Check server output."
     exit 1
 fi
 
@@ -30,7 +29,7 @@ do
   CAT_ID1=${IDS_ARRAY[$IDX1]}
   CAT_ID2=${IDS_ARRAY[$IDX2]}
 
-  curl -s -X POST "$BASE_URL/products/" -H "Content-Type: application/json" -d "{\"name\": \"Multi-Cat Product $PREFIX $i\", \"slug\": \"prod-multi-$PREFIX-$i\", \"Description\": \"desc\", \"category_ids\": [\"$CAT_ID1\", \"$CAT_ID2\"], \"price\": 49.99}" > /dev/null
+  curl -s -X POST -H "X-API-Key: $API_KEY" "$BASE_URL/products/" -H "Content-Type: application/json" -d "{\"name\": \"Multi-Cat Product $PREFIX $i\", \"slug\": \"prod-multi-$PREFIX-$i\", \"Description\": \"desc\", \"category_ids\": [\"$CAT_ID1\", \"$CAT_ID2\"], \"price\": 49.99}" > /dev/null
 done
 
 echo "--------------------------------"
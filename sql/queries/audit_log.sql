@@ -0,0 +1,28 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (
+    store_id,
+    actor,
+    actor_method,
+    action,
+    entity_type,
+    entity_id,
+    before,
+    after,
+    request_id,
+    ip
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+);
+
+-- name: GetAuditEntries :many
+SELECT * FROM audit_log
+WHERE store_id = sqlc.arg(store_id)
+    AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+    AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+    AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+    AND (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor))
+    AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+    AND (sqlc.narg(since)::timestamp IS NULL OR created_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamp IS NULL OR created_at < sqlc.narg(until))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
@@ -1,35 +1,41 @@
 -- name: CreateCategory :one
-INSERT INTO categories (name, slug, created_at, updated_at)
-VALUES ($1, $2, NOW(), NOW())
-RETURNING id, name, slug, created_at, updated_at;
+INSERT INTO categories (store_id, name, slug, created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING *;
 
 -- name: GetCategories :many
-SELECT id, name, slug, created_at, updated_at
-FROM categories
+SELECT * FROM categories
+WHERE store_id = $1
 ORDER BY name
-LIMIT $1 OFFSET $2;
+LIMIT $2 OFFSET $3;
 
 -- name: GetCategoryById :one
-SELECT id, name, slug, created_at, updated_at
-FROM categories
-WHERE id = $1;
+SELECT * FROM categories
+WHERE store_id = $1 AND id = $2;
 
 -- name: GetCategoryBySlug :one
-SELECT id, name, slug, created_at, updated_at
-FROM categories
-WHERE slug = $1;
+SELECT * FROM categories
+WHERE store_id = $1 AND slug = $2;
 
 -- name: UpdateCategory :exec
 UPDATE categories
 SET
-    name = COALESCE($2, name),
-    slug = COALESCE($3, slug),
+    name = COALESCE($3, name),
+    slug = COALESCE($4, slug),
     updated_at = NOW()
-WHERE id = $1;
+WHERE store_id = $1 AND id = $2;
 
 -- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1;
+DELETE FROM categories WHERE store_id = $1 AND id = $2;
 
 -- name: GetCategoriesCount :one
-SELECT COUNT(*) FROM categories;
+SELECT COUNT(*) FROM categories
+WHERE store_id = $1;
 
+-- name: GetCategoriesByIDs :many
+SELECT * FROM categories
+WHERE store_id = $1 AND id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: GetCategoryIDsBySlugs :many
+SELECT id, slug FROM categories
+WHERE store_id = sqlc.arg(store_id) AND slug = ANY(sqlc.arg(slugs)::text[]);
@@ -0,0 +1,25 @@
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, key, request_hash, expires_at, claim)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (scope, key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = $1 AND key = $2;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $3, content_type = $4, response_body = $5, expires_at = $6, location = $7
+WHERE scope = $1 AND key = $2 AND claim = $8 AND status_code IS NULL;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE scope = $1 AND key = $2 AND claim = $3 AND status_code IS NULL;
+
+-- name: DeleteIdempotencyKeyIfExpired :exec
+DELETE FROM idempotency_keys
+WHERE scope = $1 AND key = $2 AND expires_at < now();
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < now();
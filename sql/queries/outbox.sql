@@ -0,0 +1,55 @@
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (event_id, store_id, event_type, entity_type, entity_id, action, data, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: InitOutboxCursor :exec
+INSERT INTO outbox_cursors (publisher, tx_id, last_id)
+SELECT sqlc.arg(publisher), COALESCE(max(tx_id), 0)::bigint, COALESCE(max(id), 0)::bigint FROM outbox
+ON CONFLICT (publisher) DO NOTHING;
+
+-- name: LockOutboxCursor :one
+SELECT * FROM outbox_cursors
+WHERE publisher = $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetOutboxEventsAfter :many
+SELECT * FROM outbox
+WHERE (tx_id, id) > (sqlc.arg(tx_id)::bigint, sqlc.arg(last_id)::bigint)
+    AND tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+ORDER BY tx_id, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateOutboxCursor :exec
+UPDATE outbox_cursors
+SET tx_id = $2, last_id = $3, updated_at = now()
+WHERE publisher = $1;
+
+-- name: GetOutboxLag :many
+SELECT c.publisher,
+    count(o.id)::bigint AS pending,
+    min(o.created_at)::timestamptz AS oldest_pending_at,
+    c.updated_at
+FROM outbox_cursors c
+LEFT JOIN outbox o ON (o.tx_id, o.id) > (c.tx_id, c.last_id)
+GROUP BY c.publisher, c.updated_at
+ORDER BY c.publisher;
+
+-- name: DeleteRelayedOutboxEvents :execrows
+DELETE FROM outbox o
+WHERE o.created_at < $1
+    AND NOT EXISTS (
+        SELECT 1 FROM outbox_cursors c
+        WHERE (o.tx_id, o.id) > (c.tx_id, c.last_id)
+    );
+
+-- name: GetOutboxHead :one
+SELECT (pg_snapshot_xmin(pg_current_snapshot())::text::bigint - 1)::bigint AS tx_id;
+
+-- name: GetStoreOutboxEventsAfter :many
+SELECT * FROM outbox
+WHERE store_id = sqlc.arg(store_id)
+    AND (tx_id, id) > (sqlc.arg(tx_id)::bigint, sqlc.arg(last_id)::bigint)
+    AND tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+    AND (sqlc.narg(entity_types)::text[] IS NULL OR entity_type = ANY(sqlc.narg(entity_types)::text[]))
+ORDER BY tx_id, id
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,58 @@
+-- name: CreateProductFeed :exec
+INSERT INTO product_feeds (store_id)
+VALUES ($1)
+ON CONFLICT (store_id) DO NOTHING;
+
+-- name: GetProductFeed :one
+SELECT * FROM product_feeds
+WHERE store_id = $1;
+
+-- name: LockProductFeed :one
+SELECT * FROM product_feeds
+WHERE store_id = $1
+FOR UPDATE;
+
+-- name: MarkProductFeedStale :exec
+UPDATE product_feeds
+SET stale = true
+WHERE store_id = $1;
+
+-- name: FinishProductFeedBuild :one
+UPDATE product_feeds
+SET stale = false,
+    fingerprint = $2,
+    version = version + 1,
+    updated_at = now()
+WHERE store_id = $1
+RETURNING *;
+
+-- name: TouchProductFeed :exec
+UPDATE product_feeds
+SET version = version + 1,
+    updated_at = now()
+WHERE store_id = $1;
+
+-- name: DeleteProductFeedItems :exec
+DELETE FROM product_feed_items
+WHERE store_id = $1;
+
+-- name: CreateProductFeedItems :copyfrom
+INSERT INTO product_feed_items (store_id, product_id, xml, tsv)
+VALUES ($1, $2, $3, $4);
+
+-- name: UpsertProductFeedItem :exec
+INSERT INTO product_feed_items (store_id, product_id, xml, tsv)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (store_id, product_id) DO UPDATE
+SET xml = EXCLUDED.xml,
+    tsv = EXCLUDED.tsv;
+
+-- name: DeleteProductFeedItem :exec
+DELETE FROM product_feed_items
+WHERE store_id = $1 AND product_id = $2;
+
+-- name: GetProductFeedItems :many
+SELECT product_id, xml, tsv FROM product_feed_items
+WHERE store_id = $1 AND product_id > $2
+ORDER BY product_id
+LIMIT $3;
@@ -1,27 +1,81 @@
 -- name: CreateProductImage :one
 INSERT INTO product_images (
+    store_id,
     product_id,
     url,
-    is_primary
+    is_primary,
+    visibility,
+    position
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5,
+    (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE store_id = $1 AND product_id = $2)
 ) RETURNING *;
 
 -- name: GetProductImages :many
 SELECT * FROM product_images
-WHERE product_id = $1
-ORDER BY is_primary DESC, created_at ASC;
+WHERE store_id = $1 AND product_id = $2
+ORDER BY is_primary DESC, position ASC, created_at ASC;
 
 -- name: GetProductPrimaryImage :one
 SELECT * FROM product_images
-WHERE product_id = $1 AND is_primary = true
+WHERE store_id = $1 AND product_id = $2 AND is_primary = true
 LIMIT 1;
 
 -- name: DeleteProductImage :exec
 DELETE FROM product_images
+WHERE store_id = $1 AND id = $2;
+
+-- name: GetProductImageByID :one
+SELECT * FROM product_images
+WHERE store_id = $1 AND id = $2;
+
+-- name: GetMediaImageByID :one
+SELECT * FROM product_images
 WHERE id = $1;
 
--- name: SetProductPrimaryImage :exec
+-- name: ClearProductPrimaryImage :exec
+UPDATE product_images
+SET is_primary = false
+WHERE store_id = $1 AND product_id = $2 AND is_primary = true;
+
+-- name: MarkProductImagePrimary :exec
+UPDATE product_images
+SET is_primary = true
+WHERE store_id = $1 AND id = $2;
+
+-- name: GetProductImagesByProductIDs :many
+SELECT * FROM product_images
+WHERE store_id = sqlc.arg(store_id) AND product_id = ANY(sqlc.arg(product_ids)::uuid[])
+ORDER BY product_id, is_primary DESC, position ASC, created_at ASC;
+
+-- name: UpdateProductImageVisibility :exec
 UPDATE product_images
-SET is_primary = (id = $2)
-WHERE product_id = $1;
+SET visibility = $3
+WHERE store_id = $1 AND id = $2;
+
+-- name: UpdateProductImagePosition :exec
+UPDATE product_images
+SET position = $3
+WHERE store_id = $1 AND id = $2;
+
+-- name: GetReferencedImageURLs :many
+SELECT DISTINCT url FROM product_images
+WHERE url = ANY(sqlc.arg(urls)::text[]);
+
+-- name: AddImportedProductImages :many
+INSERT INTO product_images (store_id, product_id, url, is_primary, position)
+SELECT sqlc.arg(store_id), u.product_id, u.url,
+    u.ord = 1 AND NOT EXISTS (
+        SELECT 1 FROM product_images pi
+        WHERE pi.product_id = u.product_id AND pi.is_primary = true
+    ),
+    (
+        SELECT COALESCE(MAX(pi.position) + 1, 0) FROM product_images pi
+        WHERE pi.product_id = u.product_id
+    ) + u.ord - 1
+FROM unnest(sqlc.arg(product_ids)::uuid[], sqlc.arg(urls)::text[], sqlc.arg(ords)::int[]) AS u(product_id, url, ord)
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_images pi
+    WHERE pi.product_id = u.product_id AND pi.url = u.url
+)
+RETURNING *;
@@ -0,0 +1,100 @@
+-- name: CreateProductImportJob :one
+INSERT INTO product_import_jobs (store_id, format, dry_run, actor, actor_method, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: CreateProductImportRows :copyfrom
+INSERT INTO product_import_rows (job_id, line, name, slug, description, price, category_slugs, image_urls, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: SetProductImportJobTotals :one
+UPDATE product_import_jobs
+SET total_rows = sqlc.arg(total_rows),
+    processed_rows = sqlc.arg(rejected_rows),
+    failed_count = sqlc.arg(rejected_rows)
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetProductImportJobs :many
+SELECT * FROM product_import_jobs
+WHERE store_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: GetProductImportJob :one
+SELECT * FROM product_import_jobs
+WHERE store_id = $1 AND id = $2;
+
+-- name: GetRejectedProductImportRows :many
+SELECT * FROM product_import_rows
+WHERE job_id = $1 AND line > $2 AND error IS NOT NULL
+ORDER BY line
+LIMIT $3;
+
+-- name: ClaimProductImportJob :one
+UPDATE product_import_jobs
+SET status = 'running',
+    started_at = COALESCE(started_at, now()),
+    lease_until = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+WHERE id = (
+    SELECT id FROM product_import_jobs
+    WHERE status = 'queued' OR (status = 'running' AND lease_until < now())
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetPendingProductImportRows :many
+SELECT * FROM product_import_rows
+WHERE job_id = $1 AND line > $2 AND error IS NULL
+ORDER BY line
+LIMIT $3;
+
+-- name: RecordProductImportProgress :execrows
+UPDATE product_import_jobs
+SET last_line = sqlc.arg(last_line),
+    processed_rows = processed_rows + sqlc.arg(processed),
+    created_count = created_count + sqlc.arg(created),
+    updated_count = updated_count + sqlc.arg(updated),
+    failed_count = failed_count + sqlc.arg(failed),
+    lease_until = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+WHERE id = sqlc.arg(id) AND status = 'running' AND last_line = sqlc.arg(from_line);
+
+-- name: RejectProductImportRows :exec
+UPDATE product_import_rows r
+SET error = e.error
+FROM unnest(sqlc.arg(lines)::int[], sqlc.arg(errors)::text[]) AS e(line, error)
+WHERE r.job_id = sqlc.arg(job_id) AND r.line = e.line;
+
+-- name: FinishProductImportJob :exec
+UPDATE product_import_jobs
+SET status = $2,
+    error = $3,
+    finished_at = now(),
+    lease_until = NULL
+WHERE id = $1;
+
+-- name: DeleteAppliedProductImportRows :exec
+DELETE FROM product_import_rows
+WHERE job_id = $1 AND error IS NULL;
+
+-- name: DeleteFinishedProductImportJobs :execrows
+DELETE FROM product_import_jobs
+WHERE finished_at < $1;
+
+-- name: UpsertImportedProducts :many
+INSERT INTO products (store_id, name, slug, description, price, created_at, updated_at)
+SELECT sqlc.arg(store_id), u.name, u.slug, u.description, u.price, NOW(), NOW()
+FROM unnest(
+    sqlc.arg(names)::text[],
+    sqlc.arg(slugs)::text[],
+    sqlc.arg(descriptions)::text[],
+    sqlc.arg(prices)::float8[]
+) AS u(name, slug, description, price)
+ON CONFLICT (store_id, slug) DO UPDATE
+SET name = EXCLUDED.name,
+    description = EXCLUDED.description,
+    price = EXCLUDED.price,
+    updated_at = NOW()
+RETURNING id, slug, (xmax = 0)::boolean AS inserted;
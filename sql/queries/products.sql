@@ -1,7 +1,7 @@
 -- name: CreateProduct :one
-INSERT INTO products(name, slug, description, price, created_at, updated_at)
-VALUES ($1, $2, $3, $4, NOW(), NOW())
-RETURNING id, name, slug, description, price, created_at, updated_at;
+INSERT INTO products(store_id, name, slug, description, price, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING *;
 
 -- name: GetAllProducts :many
 SELECT 
@@ -13,6 +13,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc
@@ -21,8 +23,9 @@ SELECT
     )::json as categories
 FROM products p
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = $1
 ORDER BY p.created_at DESC
-LIMIT $1 OFFSET $2;
+LIMIT $2 OFFSET $3;
 
 -- name: GetProductByID :one
 SELECT 
@@ -34,6 +37,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc
@@ -42,7 +47,33 @@ SELECT
     )::json as categories
 FROM products p
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
-WHERE p.id = $1;
+WHERE p.store_id = $1 AND p.id = $2;
+
+-- name: GetProductsAfter :many
+SELECT 
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc
+        JOIN categories c ON c.id = pc.category_id
+        WHERE pc.product_id = p.id
+    )::json as categories
+FROM products p
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = sqlc.arg(store_id)
+    AND (sqlc.narg(after_created_at)::timestamp IS NULL
+        OR (p.created_at, p.id) < (sqlc.narg(after_created_at), sqlc.narg(after_id)::uuid))
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetProductsByCategoryID :many
 SELECT 
@@ -54,6 +85,8 @@ SELECT
     p.created_at,
     p.updated_at,
     pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
     (
         SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
         FROM product_categories pc_all
@@ -63,28 +96,102 @@ SELECT
 FROM products p
 JOIN product_categories pc ON p.id = pc.product_id
 LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
-WHERE pc.category_id = $1;
+WHERE p.store_id = $1 AND pc.category_id = $2;
 
--- name: AddProductCategory :exec
-INSERT INTO product_categories (product_id, category_id)
-VALUES ($1, $2);
+-- name: GetProductsByCategoryIDs :many
+WITH ranked AS (
+    SELECT
+        pc.category_id,
+        pc.product_id,
+        row_number() OVER (PARTITION BY pc.category_id ORDER BY p.created_at DESC, p.id) AS position
+    FROM products p
+    JOIN product_categories pc ON p.id = pc.product_id
+    WHERE p.store_id = $1 AND pc.category_id = ANY(sqlc.arg(category_ids)::uuid[])
+)
+SELECT 
+    ranked.category_id,
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc_all
+        JOIN categories c ON c.id = pc_all.category_id
+        WHERE pc_all.product_id = p.id
+    )::json as categories
+FROM ranked
+JOIN products p ON p.id = ranked.product_id
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE ranked.position <= sqlc.arg(per_category)::int
+ORDER BY ranked.category_id, ranked.position;
+
+-- name: AddProductCategory :execrows
+INSERT INTO product_categories (store_id, product_id, category_id)
+SELECT sqlc.arg(store_id), sqlc.arg(product_id), c.id
+FROM categories c
+WHERE c.store_id = sqlc.arg(store_id) AND c.id = sqlc.arg(category_id);
 
 -- name: ClearProductCategories :exec
 DELETE FROM product_categories
-WHERE product_id = $1;
+WHERE store_id = $1 AND product_id = $2;
 
 -- name: UpdateProduct :exec
 UPDATE products
 SET 
-    name = COALESCE($2, name),
-    description = COALESCE($3, description),
-    price = COALESCE($4, price),
+    name = COALESCE($3, name),
+    description = COALESCE($4, description),
+    price = COALESCE($5, price),
     updated_at = NOW()
-WHERE id = $1;
+WHERE store_id = $1 AND id = $2;
 
 -- name: DeleteProduct :exec
 DELETE FROM products
-WHERE id = $1;
+WHERE store_id = $1 AND id = $2;
 
 -- name: GetProductsCount :one
-SELECT COUNT(*) FROM products;
+SELECT COUNT(*) FROM products
+WHERE store_id = $1;
+
+-- name: LockProduct :one
+SELECT id FROM products
+WHERE store_id = $1 AND id = $2
+FOR UPDATE;
+
+-- name: GetProductsBySlugs :many
+SELECT 
+    p.id,
+    p.name,
+    p.slug,
+    p.description,
+    p.price,
+    p.created_at,
+    p.updated_at,
+    pi.url as primary_image_url,
+    pi.id as primary_image_id,
+    pi.visibility as primary_image_visibility,
+    (
+        SELECT json_agg(jsonb_build_object('id', c.id, 'name', c.name, 'slug', c.slug))
+        FROM product_categories pc
+        JOIN categories c ON c.id = pc.category_id
+        WHERE pc.product_id = p.id
+    )::json as categories
+FROM products p
+LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.is_primary = true
+WHERE p.store_id = sqlc.arg(store_id) AND p.slug = ANY(sqlc.arg(slugs)::text[]);
+
+-- name: ClearProductCategoriesByProductIDs :exec
+DELETE FROM product_categories
+WHERE store_id = sqlc.arg(store_id) AND product_id = ANY(sqlc.arg(product_ids)::uuid[]);
+
+-- name: AddProductCategories :exec
+INSERT INTO product_categories (store_id, product_id, category_id)
+SELECT sqlc.arg(store_id), u.product_id, u.category_id
+FROM unnest(sqlc.arg(product_ids)::uuid[], sqlc.arg(category_ids)::uuid[]) AS u(product_id, category_id)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,49 @@
+-- name: GetProductSitemapPages :many
+SELECT
+    ((n - 1) / sqlc.arg(page_size)::int + 1)::int AS page,
+    max(updated_at)::timestamp AS last_modified
+FROM (
+    SELECT updated_at, row_number() OVER (ORDER BY slug) AS n
+    FROM products
+    WHERE store_id = $1
+) p
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetCategorySitemapPages :many
+SELECT
+    ((n - 1) / sqlc.arg(page_size)::int + 1)::int AS page,
+    max(updated_at)::timestamp AS last_modified
+FROM (
+    SELECT updated_at, row_number() OVER (ORDER BY slug) AS n
+    FROM categories
+    WHERE store_id = $1
+) c
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetSitemapProducts :many
+SELECT
+    p.id,
+    p.slug,
+    p.updated_at,
+    COALESCE(
+        array_agg(pi.url ORDER BY pi.is_primary DESC NULLS LAST, pi.position, pi.created_at)
+            FILTER (WHERE pi.id IS NOT NULL),
+        '{}'
+    )::text[] AS image_urls
+FROM (
+    SELECT id, slug, updated_at FROM products
+    WHERE store_id = $1
+    ORDER BY slug
+    LIMIT $2 OFFSET $3
+) p
+LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.visibility = 'public'
+GROUP BY p.id, p.slug, p.updated_at
+ORDER BY p.slug;
+
+-- name: GetSitemapCategories :many
+SELECT id, slug, updated_at FROM categories
+WHERE store_id = $1
+ORDER BY slug
+LIMIT $2 OFFSET $3;
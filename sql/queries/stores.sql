@@ -0,0 +1,16 @@
+-- name: CreateStore :one
+INSERT INTO stores (slug, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetStores :many
+SELECT * FROM stores
+ORDER BY created_at ASC;
+
+-- name: GetStoreByID :one
+SELECT * FROM stores
+WHERE id = $1;
+
+-- name: GetStoreBySlug :one
+SELECT * FROM stores
+WHERE slug = $1;
@@ -0,0 +1,73 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (store_id, url, events, secret, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE store_id = $1
+ORDER BY created_at;
+
+-- name: GetActiveWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE store_id = $1 AND active = true;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE store_id = $1 AND id = $2;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = sqlc.arg(url),
+    events = sqlc.arg(events),
+    active = sqlc.arg(active),
+    secret = COALESCE(sqlc.narg(secret), secret),
+    updated_at = now()
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions
+WHERE store_id = $1 AND id = $2;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (store_id, subscription_id, event_id, event_type, payload)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id
+    AND d.id IN (
+        SELECT id FROM webhook_deliveries
+        WHERE status = 'pending' AND next_attempt_at <= now()
+        ORDER BY id
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING d.id, d.store_id, d.subscription_id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    status = $2,
+    next_attempt_at = $3,
+    last_status_code = $4,
+    last_error = $5,
+    delivered_at = $6
+WHERE id = $1;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE store_id = sqlc.arg(store_id)
+    AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+    AND (sqlc.narg(subscription_id)::uuid IS NULL OR subscription_id = sqlc.narg(subscription_id))
+    AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: RetryWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = now()
+WHERE store_id = $1 AND id = $2 AND status = 'dead';
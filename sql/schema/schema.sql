@@ -1,9 +1,21 @@
 CREATE EXTENSION IF NOT EXISTS pgcrypto;
 
+CREATE TABLE IF NOT EXISTS stores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Rows that predate multi-tenancy belong to the default store
+INSERT INTO stores (id, slug, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default')
+ON CONFLICT DO NOTHING;
+
 CREATE TABLE IF NOT EXISTS categories (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name TEXT NOT NULL,
-    slug TEXT NOT NULL UNIQUE,
+    slug TEXT NOT NULL,
     created_at TIMESTAMP NOT NULL DEFAULT now(),
     updated_at TIMESTAMP NOT NULL DEFAULT now()
 );
@@ -11,7 +23,7 @@ CREATE TABLE IF NOT EXISTS categories (
 CREATE TABLE IF NOT EXISTS products (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name TEXT NOT NULL,
-    slug TEXT NOT NULL UNIQUE,
+    slug TEXT NOT NULL,
     description TEXT NOT NULL,
     price NUMERIC(12,2) NOT NULL,
     created_at TIMESTAMP NOT NULL DEFAULT now(),
@@ -28,6 +40,13 @@ CREATE TABLE IF NOT EXISTS product_images (
     created_at TIMESTAMP NOT NULL DEFAULT now()
 );
 
+ALTER TABLE product_images
+ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'private'));
+
+ALTER TABLE product_images
+ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
 CREATE TABLE IF NOT EXISTS product_categories (
     product_id UUID REFERENCES products(id) ON DELETE CASCADE,
     category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
@@ -41,3 +60,343 @@ ON product_images(product_id);
 CREATE UNIQUE INDEX IF NOT EXISTS one_primary_image_per_product
 ON product_images(product_id)
 WHERE is_primary = true;
+
+ALTER TABLE categories
+ADD COLUMN IF NOT EXISTS store_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES stores(id);
+
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS store_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES stores(id);
+
+ALTER TABLE product_images
+ADD COLUMN IF NOT EXISTS store_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES stores(id);
+
+ALTER TABLE product_categories
+ADD COLUMN IF NOT EXISTS store_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES stores(id);
+
+-- Slugs are unique per store instead of globally
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_slug_key;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_slug_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS categories_store_slug_key
+ON categories(store_id, slug);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_store_slug_key
+ON products(store_id, slug);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_store_id
+ON product_categories(store_id);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_store_id
+ON product_images(store_id);
+
+-- Row level security backs up the store_id filter every query carries. The
+-- connection pool sets app.store_id to the store of the request. Sessions
+-- without a store see no rows, unless app.store_id is '*' for the few reads
+-- that span stores on purpose, such as media garbage collection.
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categories FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS store_isolation ON categories;
+CREATE POLICY store_isolation ON categories
+USING (current_setting('app.store_id', true) = '*'
+    OR store_id = NULLIF(NULLIF(current_setting('app.store_id', true), ''), '*')::uuid);
+
+ALTER TABLE products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS store_isolation ON products;
+CREATE POLICY store_isolation ON products
+USING (current_setting('app.store_id', true) = '*'
+    OR store_id = NULLIF(NULLIF(current_setting('app.store_id', true), ''), '*')::uuid);
+
+ALTER TABLE product_images ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_images FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS store_isolation ON product_images;
+CREATE POLICY store_isolation ON product_images
+USING (current_setting('app.store_id', true) = '*'
+    OR store_id = NULLIF(NULLIF(current_setting('app.store_id', true), ''), '*')::uuid);
+
+ALTER TABLE product_categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_categories FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS store_isolation ON product_categories;
+CREATE POLICY store_isolation ON product_categories
+USING (current_setting('app.store_id', true) = '*'
+    OR store_id = NULLIF(NULLIF(current_setting('app.store_id', true), ''), '*')::uuid);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+-- Keys bound to a store can only act on that store, keys without one on any
+ALTER TABLE api_keys
+ADD COLUMN IF NOT EXISTS store_id UUID REFERENCES stores(id);
+
+-- Append-only record of every catalog write
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    store_id UUID NOT NULL REFERENCES stores(id),
+    actor TEXT NOT NULL,
+    actor_method TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_store_id
+ON audit_log(store_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity
+ON audit_log(store_id, entity_type, entity_id);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_change ON audit_log;
+CREATE TRIGGER audit_log_no_change
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- Token buckets shared by all replicas. Losing them on a crash only resets
+-- the limits, so the table skips the write-ahead log.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Responses to POST requests sent with an Idempotency-Key header. A row
+-- without a status code is a request still being handled.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER,
+    content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+ON idempotency_keys(expires_at);
+
+-- Location of the stored response, replayed along with it
+ALTER TABLE idempotency_keys
+ADD COLUMN IF NOT EXISTS location TEXT;
+
+-- Claim identifies the request holding the key, so one whose lock timed out
+-- and was reclaimed cannot complete or release the key of another
+ALTER TABLE idempotency_keys
+ADD COLUMN IF NOT EXISTS claim UUID;
+
+-- Webhook subscriptions of a store. Events lists the event types delivered to
+-- url, such as "product.updated", "product.*" or "*".
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_store_id
+ON webhook_subscriptions(store_id);
+
+-- One row per event and subscription. A pending delivery is attempted at
+-- next_attempt_at until it is delivered or runs out of attempts and is dead.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+ON webhook_deliveries(next_attempt_at)
+WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_store_id
+ON webhook_deliveries(store_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id
+ON webhook_deliveries(subscription_id, id DESC);
+
+-- Change events, written in the transaction of the change and relayed to
+-- publishers in order. tx_id is the writing transaction, rows are relayed once
+-- every transaction before it has finished so none is skipped.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    tx_id BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint,
+    event_id UUID NOT NULL,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    data JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_position
+ON outbox(tx_id, id);
+
+-- How far each publisher has got through the outbox. The relay of a publisher
+-- holds its row locked while it publishes, so only one instance relays it.
+CREATE TABLE IF NOT EXISTS outbox_cursors (
+    publisher TEXT PRIMARY KEY,
+    tx_id BIGINT NOT NULL,
+    last_id BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Committed writes to the catalog are announced on the catalog_changes
+-- channel, so every replica can drop what it derived from the row. The
+-- argument names the column identifying the row.
+CREATE OR REPLACE FUNCTION notify_catalog_change() RETURNS trigger AS $$
+DECLARE
+    r JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := to_jsonb(OLD);
+    ELSE
+        r := to_jsonb(NEW);
+    END IF;
+
+    PERFORM pg_notify('catalog_changes', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', lower(TG_OP),
+        'store_id', r ->> 'store_id',
+        'id', r ->> TG_ARGV[0],
+        'product_id', r ->> 'product_id'
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS categories_notify ON categories;
+CREATE TRIGGER categories_notify
+AFTER INSERT OR UPDATE OR DELETE ON categories
+FOR EACH ROW EXECUTE FUNCTION notify_catalog_change('id');
+
+DROP TRIGGER IF EXISTS products_notify ON products;
+CREATE TRIGGER products_notify
+AFTER INSERT OR UPDATE OR DELETE ON products
+FOR EACH ROW EXECUTE FUNCTION notify_catalog_change('id');
+
+DROP TRIGGER IF EXISTS product_images_notify ON product_images;
+CREATE TRIGGER product_images_notify
+AFTER INSERT OR UPDATE OR DELETE ON product_images
+FOR EACH ROW EXECUTE FUNCTION notify_catalog_change('id');
+
+DROP TRIGGER IF EXISTS product_categories_notify ON product_categories;
+CREATE TRIGGER product_categories_notify
+AFTER INSERT OR UPDATE OR DELETE ON product_categories
+FOR EACH ROW EXECUTE FUNCTION notify_catalog_change('product_id');
+
+-- Bulk product imports. The rows of a job are staged in product_import_rows
+-- when the file is uploaded, and a worker applies them in order of line
+-- while it holds the job's lease. last_line is the last row applied, so a
+-- job whose worker died is resumed there by the next one.
+CREATE TABLE IF NOT EXISTS product_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    format TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    last_line INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    actor TEXT NOT NULL,
+    actor_method TEXT NOT NULL,
+    request_id TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    lease_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_store_id
+ON product_import_jobs(store_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_unfinished
+ON product_import_jobs(created_at)
+WHERE status IN ('queued', 'running');
+
+-- Rows of an import file. error is set on rejected rows, which are kept as
+-- the job's error report after the others have been applied and removed.
+-- category_slugs is NULL for rows that keep a product's categories.
+CREATE TABLE IF NOT EXISTS product_import_rows (
+    job_id UUID NOT NULL REFERENCES product_import_jobs(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    description TEXT NOT NULL,
+    price NUMERIC(12,2) NOT NULL,
+    category_slugs TEXT[],
+    image_urls TEXT[] NOT NULL,
+    error TEXT,
+    PRIMARY KEY (job_id, line)
+);
+
+-- Merchant feeds, one per store, built the first time they are requested.
+-- product_feed_items holds the rendered entry of every listed product and is
+-- kept up to date by the feed outbox publisher, which bumps version. A stale
+-- feed, or one rendered with other settings than fingerprint, is rebuilt
+-- when next requested.
+CREATE TABLE IF NOT EXISTS product_feeds (
+    store_id UUID PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL DEFAULT '',
+    stale BOOLEAN NOT NULL DEFAULT true,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS product_feed_items (
+    store_id UUID NOT NULL REFERENCES product_feeds(store_id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    xml TEXT NOT NULL,
+    tsv TEXT NOT NULL,
+    PRIMARY KEY (store_id, product_id)
+);